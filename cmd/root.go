@@ -14,6 +14,7 @@ import (
 var (
 	cfgFile     string
 	projectPath string
+	readOnly    bool
 )
 
 var rootCmd = &cobra.Command{
@@ -46,7 +47,11 @@ for safe parallel development.`,
 			fmt.Fprintf(os.Stderr, "Config warnings:\n%s\n", result.FormatWarnings())
 		}
 
-		return app.Run(cfg, projectPath, Version)
+		path := cfgFile
+		if path == "" {
+			path, _ = config.ConfigPath()
+		}
+		return app.Run(cfg, result, path, projectPath, Version, readOnly)
 	},
 }
 
@@ -57,12 +62,28 @@ func Execute() error {
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/openkanban/config.json)")
 	rootCmd.PersistentFlags().StringVarP(&projectPath, "project", "p", "", "project or repository path")
+	rootCmd.Flags().BoolVar(&readOnly, "read-only", false, "browse the board without changing tickets, projects or worktrees, or starting agents")
 
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(deleteCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(worktreesCmd)
+
+	newCmd.Flags().StringVar(&newFrom, "from", "", "copy settings from an existing project (name or ID)")
+	exportCmd.Flags().StringVar(&exportStatus, "status", "", "only export tickets with this status (e.g. backlog, in_progress, done)")
+	showCmd.Flags().IntVar(&showWidth, "width", 100, "total width of the printed board")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "print the statistics as JSON")
+	worktreesPruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", true, "only report what would be removed")
+	worktreesCmd.AddCommand(worktreesPruneCmd)
 }
 
+var newFrom string
+
 var newCmd = &cobra.Command{
 	Use:   "new [name]",
 	Short: "Create a new project",
@@ -92,7 +113,7 @@ var newCmd = &cobra.Command{
 			name = args[0]
 		}
 
-		return app.CreateProject(cfg, name, repoPath)
+		return app.CreateProject(cfg, name, repoPath, newFrom)
 	},
 }
 
@@ -112,3 +133,111 @@ var deleteCmd = &cobra.Command{
 		return app.DeleteProject(args[0])
 	},
 }
+
+var exportStatus string
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print tickets as JSON",
+	Long: `Print all tickets as a JSON array, sorted by project then creation time.
+
+Use --project (name, ID or repository path) and --status to narrow the output.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return app.ExportTickets(os.Stdout, projectPath, exportStatus)
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Create backlog tickets from a JSON or markdown file",
+	Long: `Create backlog tickets from a JSON array of tickets (as printed by export)
+or a markdown checklist, where each "- [ ] task" line becomes a ticket.
+
+Tickets go into the project named by --project, or the project for the
+current directory. Titles that already exist in the project are skipped.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return app.ImportTickets(os.Stdout, args[0], projectPath)
+	},
+}
+
+var showWidth int
+
+var showCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the board as plain text",
+	Long: `Print the board as plain text columns, one per status, and exit.
+
+Use --project (name, ID or repository path) to show a single project.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return app.ShowBoard(os.Stdout, projectPath, showWidth)
+	},
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize time spent by agents per ticket",
+	Long: `List every ticket that has had an agent spawned, with the time from its
+first spawn until it was completed (or until now), plus per-project totals.
+
+Use --project (name, ID or repository path) to report on a single project.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return app.ReportAgentTime(os.Stdout, projectPath)
+	},
+}
+
+var statsJSON bool
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show ticket statistics per project",
+	Long: `Show, for each project, how many tickets are in each status, how many
+are in progress against the In Progress WIP limit, the average time from
+starting a ticket to completing it, and how many were completed this week
+(since Monday).
+
+Use --project (name, ID or repository path) to show a single project and
+--json for machine-readable output.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return app.ProjectStats(os.Stdout, projectPath, statsJSON)
+	},
+}
+
+var worktreesCmd = &cobra.Command{
+	Use:   "worktrees",
+	Short: "List worktrees with their tickets and disk usage",
+	Long: `List every git worktree of each project's repository with the ticket
+using it, its branch and its size on disk (excluding the shared .git data).
+
+Worktrees that no ticket points at are shown as (orphan): they were left
+behind when a ticket was deleted or created outside OpenKanban.
+
+Use --project (name, ID or repository path) to list a single project.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return app.ListWorktrees(os.Stdout, projectPath)
+	},
+}
+
+var pruneDryRun bool
+
+var worktreesPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove worktrees that no ticket uses",
+	Long: `Remove orphaned worktrees: ones whose path and branch match no ticket.
+git worktree prune runs first to drop entries whose directories are gone.
+
+Only worktrees inside the project's worktree directory are removed, and
+ones with uncommitted changes are kept. Branches are never deleted.
+
+This is a dry run by default; pass --dry-run=false to remove them.
+Use --project (name, ID or repository path) to prune a single project.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return app.PruneWorktrees(os.Stdout, projectPath, pruneDryRun)
+	},
+}
@@ -98,15 +98,69 @@ func FindCodexSession(directory string) string {
 	return "last"
 }
 
+// FindQwenSession finds the most recent Qwen Code session for a directory.
+// Returns "latest" to signal --continue, which resumes the project's last
+// session.
+func FindQwenSession(directory string) string {
+	if _, err := exec.LookPath("qwen"); err != nil {
+		return ""
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	qwenDir := filepath.Join(homeDir, ".qwen", "tmp")
+	if _, err := os.Stat(qwenDir); os.IsNotExist(err) {
+		return ""
+	}
+
+	return "latest"
+}
+
+// FindCursorSession finds the most recent Cursor agent chat for a directory.
+// Returns "latest" to signal "cursor-agent resume", which continues the last
+// chat.
+func FindCursorSession(directory string) string {
+	if _, err := exec.LookPath("cursor-agent"); err != nil {
+		return ""
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	chatsDir := filepath.Join(homeDir, ".cursor", "chats")
+	if _, err := os.Stat(chatsDir); os.IsNotExist(err) {
+		return ""
+	}
+
+	return "latest"
+}
+
 // Manager handles AI agent configuration and status polling.
 // Agent lifecycle (spawn/stop) is now managed by terminal.Pane via PTY.
 type Manager struct {
-	config *config.Config
+	config   *config.Config
+	opencode *OpencodeServer
 }
 
 // NewManager creates a new agent manager
 func NewManager(cfg *config.Config) *Manager {
-	return &Manager{config: cfg}
+	return &Manager{config: cfg, opencode: NewOpencodeServer(cfg)}
+}
+
+// OpencodeServer returns the shared opencode server.
+func (m *Manager) OpencodeServer() *OpencodeServer {
+	return m.opencode
+}
+
+// EnsureOpencodeServer starts the shared opencode server, or restarts it if
+// it stopped answering. It can block for opencode.startup_timeout seconds.
+func (m *Manager) EnsureOpencodeServer() error {
+	return m.opencode.Start()
 }
 
 // GetAgentConfig returns the configuration for a specific agent type
@@ -129,3 +183,12 @@ func (m *Manager) StatusPollInterval() time.Duration {
 	}
 	return time.Duration(interval) * time.Second
 }
+
+// AgentPollInterval returns how often agentType's status should be checked:
+// its poll_interval_seconds when set, otherwise StatusPollInterval.
+func (m *Manager) AgentPollInterval(agentType string) time.Duration {
+	if cfg, ok := m.config.Agents[agentType]; ok && cfg.PollIntervalSeconds > 0 {
+		return time.Duration(cfg.PollIntervalSeconds) * time.Second
+	}
+	return m.StatusPollInterval()
+}
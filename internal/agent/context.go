@@ -2,6 +2,9 @@ package agent
 
 import (
 	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -16,6 +19,8 @@ type ContextData struct {
 	TicketID     string
 	Status       string
 	WorktreePath string
+	// ContextFiles is the ticket's context files joined with ", ".
+	ContextFiles string
 }
 
 func BuildContextPrompt(promptTemplate string, ticket *board.Ticket) string {
@@ -31,6 +36,7 @@ func BuildContextPrompt(promptTemplate string, ticket *board.Ticket) string {
 		TicketID:     string(ticket.ID),
 		Status:       string(ticket.Status),
 		WorktreePath: ticket.WorktreePath,
+		ContextFiles: strings.Join(ticket.ContextFiles, ", "),
 	}
 
 	tmpl, err := template.New("prompt").Parse(promptTemplate)
@@ -43,20 +49,151 @@ func BuildContextPrompt(promptTemplate string, ticket *board.Ticket) string {
 		return buildFallbackPrompt(ticket)
 	}
 
+	// Templates that don't place the context files themselves get them
+	// appended, so every prompt points the agent at them.
+	if !strings.Contains(promptTemplate, ".ContextFiles") {
+		writeContextFiles(&buf, ticket)
+	}
 	return buf.String()
 }
 
+func writeContextFiles(buf *bytes.Buffer, ticket *board.Ticket) {
+	if len(ticket.ContextFiles) == 0 {
+		return
+	}
+	buf.WriteString("\n\nRelevant files: ")
+	buf.WriteString(strings.Join(ticket.ContextFiles, ", "))
+}
+
 func buildFallbackPrompt(ticket *board.Ticket) string {
-	var sb strings.Builder
-	sb.WriteString("Task: ")
-	sb.WriteString(ticket.Title)
+	var buf bytes.Buffer
+	buf.WriteString("Task: ")
+	buf.WriteString(ticket.Title)
 	if ticket.Description != "" {
-		sb.WriteString("\n\n")
-		sb.WriteString(ticket.Description)
+		buf.WriteString("\n\n")
+		buf.WriteString(ticket.Description)
 	}
-	return sb.String()
+	writeContextFiles(&buf, ticket)
+	return buf.String()
 }
 
 func ShouldInjectContext(ticket *board.Ticket) bool {
 	return ticket.AgentSpawnedAt == nil
 }
+
+// ArgValues holds the spawn-time values substituted into agent Args.
+type ArgValues struct {
+	Worktree string
+	Branch   string
+	Title    string
+	Port     int
+}
+
+// ExpandArgs returns a copy of args with {worktree}, {branch}, {title} and
+// {port} placeholders replaced. A zero Port expands to an empty string.
+func ExpandArgs(args []string, v ArgValues) []string {
+	port := ""
+	if v.Port > 0 {
+		port = strconv.Itoa(v.Port)
+	}
+
+	replacer := strings.NewReplacer(
+		"{worktree}", v.Worktree,
+		"{branch}", v.Branch,
+		"{title}", v.Title,
+		"{port}", port,
+	)
+
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		expanded[i] = replacer.Replace(arg)
+	}
+	return expanded
+}
+
+// OpencodeArgs returns args with the worktree and "--port <port>" appended
+// when they are missing. opencode needs both however its args are
+// configured: the worktree to open and the port its status is polled on.
+func OpencodeArgs(args []string, worktree string, port int) []string {
+	hasWorktree, hasPort := false, false
+	for _, arg := range args {
+		switch {
+		case arg == worktree:
+			hasWorktree = true
+		case arg == "--port" || strings.HasPrefix(arg, "--port="):
+			hasPort = true
+		}
+	}
+
+	args = slices.Clone(args)
+	if !hasWorktree && worktree != "" {
+		args = append(args, worktree)
+	}
+	if !hasPort && port > 0 {
+		args = append(args, "--port", strconv.Itoa(port))
+	}
+	return args
+}
+
+// SplitArgs splits a command-line string into arguments. Single and double
+// quotes group words; a backslash escapes the next character outside single
+// quotes.
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg, escaped := false, false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// JoinArgs is the inverse of SplitArgs, quoting arguments that need it.
+func JoinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\") {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
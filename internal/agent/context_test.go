@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -67,6 +68,24 @@ func TestBuildContextPrompt(t *testing.T) {
 				"Path=/path/to/worktree",
 			},
 		},
+		{
+			name:     "appends context files",
+			template: "Work on: {{.Title}}",
+			ticket: &board.Ticket{
+				Title:        "Fix login",
+				ContextFiles: []string{"src/auth.go", "docs/spec.md"},
+			},
+			expectContains: []string{"Work on: Fix login\n\nRelevant files: src/auth.go, docs/spec.md"},
+		},
+		{
+			name:     "template places context files",
+			template: "Files: {{.ContextFiles}}. Task: {{.Title}}",
+			ticket: &board.Ticket{
+				Title:        "Fix login",
+				ContextFiles: []string{"src/auth.go"},
+			},
+			expectContains: []string{"Files: src/auth.go. Task: Fix login"},
+		},
 		{
 			name:     "handles empty fields gracefully",
 			template: "Title={{.Title}} Desc={{.Description}}",
@@ -198,3 +217,162 @@ func TestContextData_AllFieldsMapped(t *testing.T) {
 		t.Errorf("All fields mapping:\ngot:  %q\nwant: %q", result, expected)
 	}
 }
+
+func TestExpandArgs(t *testing.T) {
+	values := ArgValues{
+		Worktree: "/tmp/wt",
+		Branch:   "task/fix-bug",
+		Title:    "Fix the bug",
+		Port:     4097,
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		values   ArgValues
+		expected []string
+	}{
+		{
+			name:     "no placeholders",
+			args:     []string{"--yes"},
+			values:   values,
+			expected: []string{"--yes"},
+		},
+		{
+			name:     "all placeholders",
+			args:     []string{"{worktree}", "--port", "{port}", "--branch={branch}", "{title}"},
+			values:   values,
+			expected: []string{"/tmp/wt", "--port", "4097", "--branch=task/fix-bug", "Fix the bug"},
+		},
+		{
+			name:     "zero port expands empty",
+			args:     []string{"--port={port}"},
+			values:   ArgValues{},
+			expected: []string{"--port="},
+		},
+		{
+			name:     "unknown placeholder untouched",
+			args:     []string{"{model}"},
+			values:   values,
+			expected: []string{"{model}"},
+		},
+		{
+			name:     "nil args",
+			args:     nil,
+			values:   values,
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExpandArgs(tt.args, tt.values)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("ExpandArgs() = %v; want %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("ExpandArgs()[%d] = %q; want %q", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExpandArgs_DoesNotMutateInput(t *testing.T) {
+	args := []string{"{branch}"}
+	ExpandArgs(args, ArgValues{Branch: "main"})
+	if args[0] != "{branch}" {
+		t.Errorf("input args mutated: %v", args)
+	}
+}
+
+func TestOpencodeArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "empty args",
+			args:     nil,
+			expected: []string{"/tmp/wt", "--port", "4097"},
+		},
+		{
+			name:     "custom args keep worktree and port",
+			args:     []string{"--model", "gpt"},
+			expected: []string{"--model", "gpt", "/tmp/wt", "--port", "4097"},
+		},
+		{
+			name:     "worktree already given",
+			args:     []string{"/tmp/wt", "--model", "gpt"},
+			expected: []string{"/tmp/wt", "--model", "gpt", "--port", "4097"},
+		},
+		{
+			name:     "port already given",
+			args:     []string{"--port=5000"},
+			expected: []string{"--port=5000", "/tmp/wt"},
+		},
+		{
+			name:     "both already given",
+			args:     []string{"/tmp/wt", "--port", "4097"},
+			expected: []string{"/tmp/wt", "--port", "4097"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OpencodeArgs(tt.args, "/tmp/wt", 4097)
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("OpencodeArgs() = %v; want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "empty", input: "   ", expected: nil},
+		{name: "plain words", input: "--model opus  --max-turns 5", expected: []string{"--model", "opus", "--max-turns", "5"}},
+		{name: "double quotes", input: `--prompt "fix the bug"`, expected: []string{"--prompt", "fix the bug"}},
+		{name: "single quotes keep backslash", input: `'a\b'`, expected: []string{`a\b`}},
+		{name: "escaped space", input: `a\ b c`, expected: []string{"a b", "c"}},
+		{name: "empty quoted arg", input: `--flag ""`, expected: []string{"--flag", ""}},
+		{name: "unterminated quote", input: `"oops`, wantErr: true},
+		{name: "trailing backslash", input: `oops\`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitArgs(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("SplitArgs(%q) = %v; want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitArgs(%q) error: %v", tt.input, err)
+			}
+			if strings.Join(got, "\x00") != strings.Join(tt.expected, "\x00") || len(got) != len(tt.expected) {
+				t.Errorf("SplitArgs(%q) = %q; want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJoinArgs_RoundTrip(t *testing.T) {
+	args := []string{"--model", "opus", "two words", "it's", "", `back\slash`}
+	got, err := SplitArgs(JoinArgs(args))
+	if err != nil {
+		t.Fatalf("SplitArgs(JoinArgs()) error: %v", err)
+	}
+	if strings.Join(got, "\x00") != strings.Join(args, "\x00") || len(got) != len(args) {
+		t.Errorf("round trip = %q; want %q", got, args)
+	}
+}
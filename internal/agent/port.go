@@ -0,0 +1,35 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// ErrNoFreePort is returned by AllocatePort when every port in the range is
+// taken.
+var ErrNoFreePort = errors.New("no free port")
+
+// AllocatePort returns the first port in [base, base+size) that is neither
+// in used nor already bound by another process on this host.
+func AllocatePort(base, size int, used map[int]bool) (int, error) {
+	for port := base; port < base+size; port++ {
+		if used[port] || !PortFree(port) {
+			continue
+		}
+		return port, nil
+	}
+	return 0, fmt.Errorf("%w in %d-%d", ErrNoFreePort, base, base+size-1)
+}
+
+// PortFree reports whether port can be bound on the loopback interface,
+// which is where agents listen.
+func PortFree(port int) bool {
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	ln.Close()
+	return true
+}
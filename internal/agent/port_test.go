@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestAllocatePort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	bound := ln.Addr().(*net.TCPAddr).Port
+
+	if PortFree(bound) {
+		t.Errorf("PortFree(%d) = true for a bound port", bound)
+	}
+
+	if _, err := AllocatePort(bound, 1, nil); !errors.Is(err, ErrNoFreePort) {
+		t.Errorf("AllocatePort() over a bound port = %v; want ErrNoFreePort", err)
+	}
+
+	// The port after the bound one is nearly always free; skip rather than
+	// fail if something else happens to hold it.
+	next := bound + 1
+	if !PortFree(next) {
+		t.Skipf("port %d is in use", next)
+	}
+	if port, err := AllocatePort(bound, 2, nil); err != nil || port != next {
+		t.Errorf("AllocatePort() = %d, %v; want %d", port, err, next)
+	}
+	if _, err := AllocatePort(bound, 2, map[int]bool{next: true}); !errors.Is(err, ErrNoFreePort) {
+		t.Errorf("AllocatePort() with the free port used = %v; want ErrNoFreePort", err)
+	}
+}
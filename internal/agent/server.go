@@ -11,12 +11,18 @@ import (
 	"github.com/techdufus/openkanban/internal/config"
 )
 
+// OpencodeServer manages the shared `opencode serve` process. A server
+// already answering on the configured port, such as one left by another
+// OpenKanban, is used as-is and never stopped.
 type OpencodeServer struct {
-	config  *config.Config
-	cmd     *exec.Cmd
-	port    int
-	running bool
-	mu      sync.RWMutex
+	config *config.Config
+	port   int
+
+	mu sync.Mutex
+	// cmd is the server process started by Start, if any; exited is
+	// closed once it has exited.
+	cmd    *exec.Cmd
+	exited chan struct{}
 }
 
 func NewOpencodeServer(cfg *config.Config) *OpencodeServer {
@@ -26,69 +32,90 @@ func NewOpencodeServer(cfg *config.Config) *OpencodeServer {
 	}
 }
 
+// Start makes sure a server answers on the configured port, starting
+// `opencode serve` when none does. A server it started earlier that no
+// longer answers is killed and replaced. It does nothing when the server is
+// disabled or opencode isn't installed.
 func (s *OpencodeServer) Start() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.running {
+	if !s.config.Opencode.ServerEnabled {
 		return nil
 	}
 
-	if !s.config.Opencode.ServerEnabled {
+	if s.IsRunning() {
 		return nil
 	}
+	s.stopUnlocked()
 
 	// Check if opencode binary exists
 	if _, err := exec.LookPath("opencode"); err != nil {
 		return nil // opencode not installed, skip gracefully
 	}
 
-	if s.isServerAlreadyRunning() {
-		s.running = true
-		return nil
-	}
-
-	s.cmd = exec.Command("opencode", "serve", "--port", fmt.Sprintf("%d", s.port))
-	if err := s.cmd.Start(); err != nil {
+	cmd := exec.Command("opencode", "serve", "--port", fmt.Sprintf("%d", s.port))
+	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start opencode server: %w", err)
 	}
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+	s.cmd = cmd
+	s.exited = exited
 
 	timeout := s.config.Opencode.StartupTimeout
 	if timeout <= 0 {
 		timeout = 10 // default fallback
 	}
-	if err := s.waitForReady(time.Duration(timeout) * time.Second); err != nil {
-		s.cmd.Process.Kill()
-		s.cmd = nil
+	if err := s.waitForReady(time.Duration(timeout)*time.Second, exited); err != nil {
+		s.stopUnlocked()
 		return err
 	}
-
-	s.running = true
 	return nil
 }
 
+// Stop kills the server if Start started it.
 func (s *OpencodeServer) Stop() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.stopUnlocked()
+	return nil
+}
 
-	if !s.running || s.cmd == nil {
-		return nil
-	}
-
-	if s.cmd.Process != nil {
-		s.cmd.Process.Kill()
-		s.cmd.Wait()
+func (s *OpencodeServer) stopUnlocked() {
+	if s.cmd == nil {
+		return
 	}
-
+	s.cmd.Process.Kill()
+	<-s.exited
 	s.cmd = nil
-	s.running = false
-	return nil
+	s.exited = nil
 }
 
+// IsRunning reports whether a server answers on the configured port.
 func (s *OpencodeServer) IsRunning() bool {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.running
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	return s.probe(ctx)
+}
+
+// Owned reports whether the server was started by this OpenKanban and is
+// still running. It waits while Start is starting a server.
+func (s *OpencodeServer) Owned() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cmd == nil {
+		return false
+	}
+	select {
+	case <-s.exited:
+		return false
+	default:
+		return true
+	}
 }
 
 func (s *OpencodeServer) Port() int {
@@ -99,12 +126,9 @@ func (s *OpencodeServer) URL() string {
 	return fmt.Sprintf("http://localhost:%d", s.port)
 }
 
-func (s *OpencodeServer) isServerAlreadyRunning() bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
-	defer cancel()
-
-	url := fmt.Sprintf("http://localhost:%d/session/status", s.port)
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// probe asks the server for its session status.
+func (s *OpencodeServer) probe(ctx context.Context) bool {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.URL()+"/session/status", nil)
 	if err != nil {
 		return false
 	}
@@ -117,28 +141,22 @@ func (s *OpencodeServer) isServerAlreadyRunning() bool {
 	return resp.StatusCode == http.StatusOK
 }
 
-func (s *OpencodeServer) waitForReady(timeout time.Duration) error {
+// waitForReady polls the server until it answers, it exits, or timeout
+// passes.
+func (s *OpencodeServer) waitForReady(timeout time.Duration, exited <-chan struct{}) error {
 	deadline := time.Now().Add(timeout)
-	url := fmt.Sprintf("http://localhost:%d/session/status", s.port)
-
 	for time.Now().Before(deadline) {
 		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			cancel()
-			time.Sleep(100 * time.Millisecond)
-			continue
-		}
-
-		resp, err := http.DefaultClient.Do(req)
+		ready := s.probe(ctx)
 		cancel()
-		if err == nil {
-			resp.Body.Close()
-			if resp.StatusCode == http.StatusOK {
-				return nil
-			}
+		if ready {
+			return nil
+		}
+		select {
+		case <-exited:
+			return fmt.Errorf("opencode server exited during startup")
+		case <-time.After(100 * time.Millisecond):
 		}
-		time.Sleep(100 * time.Millisecond)
 	}
 
 	return fmt.Errorf("opencode server failed to become ready within %v", timeout)
@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+func TestOpencodeServerAdoptsRunningServer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/session/status" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Opencode.ServerPort = ts.Listener.Addr().(*net.TCPAddr).Port
+	server := NewOpencodeServer(cfg)
+
+	if !server.IsRunning() {
+		t.Fatal("IsRunning() = false for a server answering on the port")
+	}
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	if server.Owned() {
+		t.Error("Owned() = true for a server started elsewhere")
+	}
+	server.Stop()
+	if !server.IsRunning() {
+		t.Error("Stop() affected a server it didn't start")
+	}
+}
+
+func TestOpencodeServerNotRunning(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Opencode.ServerPort = port
+	cfg.Opencode.ServerEnabled = false
+	server := NewOpencodeServer(cfg)
+
+	if server.IsRunning() {
+		t.Error("IsRunning() = true with nothing listening")
+	}
+	if err := server.Start(); err != nil || server.Owned() {
+		t.Errorf("Start() with the server disabled = %v, owned %v; want nil, false", err, server.Owned())
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
 )
 
 const (
@@ -33,6 +35,15 @@ type StatusDetector struct {
 	cacheExpiration time.Duration
 	statusDirs      []string
 	httpClient      *http.Client
+	patterns        map[string]compiledPatterns
+	patternsMu      sync.RWMutex
+}
+
+// compiledPatterns is an agent's config.StatusPatterns, compiled once.
+type compiledPatterns struct {
+	working []*regexp.Regexp
+	waiting []*regexp.Regexp
+	errors  []*regexp.Regexp
 }
 
 type cachedStatus struct {
@@ -55,6 +66,37 @@ func NewStatusDetector() *StatusDetector {
 	}
 }
 
+// SetStatusPatterns compiles the custom status patterns of each agent in
+// agents, replacing any set before. Invalid expressions are skipped; config
+// validation reports them.
+func (d *StatusDetector) SetStatusPatterns(agents map[string]config.AgentConfig) {
+	patterns := make(map[string]compiledPatterns)
+	for name, cfg := range agents {
+		if cfg.StatusPatterns == nil {
+			continue
+		}
+		patterns[name] = compiledPatterns{
+			working: compilePatterns(cfg.StatusPatterns.Working),
+			waiting: compilePatterns(cfg.StatusPatterns.Waiting),
+			errors:  compilePatterns(cfg.StatusPatterns.Error),
+		}
+	}
+
+	d.patternsMu.Lock()
+	d.patterns = patterns
+	d.patternsMu.Unlock()
+}
+
+func compilePatterns(exprs []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, expr := range exprs {
+		if re, err := regexp.Compile(expr); err == nil {
+			compiled = append(compiled, re)
+		}
+	}
+	return compiled
+}
+
 func (d *StatusDetector) DetectStatus(agentType, sessionID string, processRunning bool, terminalContent string) board.AgentStatus {
 	return d.DetectStatusWithPort(agentType, sessionID, "", 0, processRunning, terminalContent)
 }
@@ -96,16 +138,48 @@ func (d *StatusDetector) detectFromTerminalContent(agentType, content string) bo
 		lastLines = lines[len(lines)-10:]
 	}
 	recentContent := strings.Join(lastLines, "\n")
+	if status := d.detectCustomStatus(agentType, recentContent); status != board.AgentNone {
+		return status
+	}
 	recentLower := strings.ToLower(recentContent)
 
 	switch agentType {
-	case "opencode", "claude", "gemini", "codex":
+	case "opencode", "claude", "gemini", "codex", "qwen", "cursor-agent":
 		return d.detectCodingAgentStatus(recentLower, contentLower)
 	default:
 		return d.detectGenericAgentStatus(recentLower)
 	}
 }
 
+// detectCustomStatus matches the agent's configured status patterns against
+// recent output, checking waiting, then working, then error like the
+// built-in lists.
+func (d *StatusDetector) detectCustomStatus(agentType, recent string) board.AgentStatus {
+	d.patternsMu.RLock()
+	p, ok := d.patterns[agentType]
+	d.patternsMu.RUnlock()
+	if !ok {
+		return board.AgentNone
+	}
+
+	groups := []struct {
+		patterns []*regexp.Regexp
+		status   board.AgentStatus
+	}{
+		{p.waiting, board.AgentWaiting},
+		{p.working, board.AgentWorking},
+		{p.errors, board.AgentError},
+	}
+	for _, g := range groups {
+		for _, re := range g.patterns {
+			if re.MatchString(recent) {
+				return g.status
+			}
+		}
+	}
+	return board.AgentNone
+}
+
 func (d *StatusDetector) detectCodingAgentStatus(recentLower, fullLower string) board.AgentStatus {
 	waitingPatterns := []string{
 		"waiting for",
@@ -439,6 +513,61 @@ func (d *StatusDetector) InvalidateCache(sessionName string) {
 	}
 }
 
+// StatusSmoother holds back status changes until they have been observed on
+// several consecutive polls, so output scrolling past a transient match
+// doesn't make a ticket flicker between working, idle and waiting.
+type StatusSmoother struct {
+	stablePolls int
+	states      map[board.TicketID]smoothedStatus
+}
+
+type smoothedStatus struct {
+	shown   board.AgentStatus
+	pending board.AgentStatus
+	count   int
+}
+
+// NewStatusSmoother returns a smoother that requires stablePolls consecutive
+// observations before a status change is shown. Values below 2 disable
+// smoothing.
+func NewStatusSmoother(stablePolls int) *StatusSmoother {
+	return &StatusSmoother{
+		stablePolls: stablePolls,
+		states:      make(map[board.TicketID]smoothedStatus),
+	}
+}
+
+// Observe records a detected status for a ticket and returns the status to
+// show. A ticket's first status, AgentNone (the agent stopped) and
+// AgentCompleted are shown immediately.
+func (s *StatusSmoother) Observe(ticketID board.TicketID, status board.AgentStatus) board.AgentStatus {
+	if s.stablePolls < 2 {
+		return status
+	}
+	if status == board.AgentNone {
+		delete(s.states, ticketID)
+		return status
+	}
+
+	state, ok := s.states[ticketID]
+	switch {
+	case !ok || status == board.AgentCompleted:
+		state = smoothedStatus{shown: status}
+	case status == state.shown:
+		state.pending, state.count = board.AgentNone, 0
+	case status == state.pending:
+		state.count++
+	default:
+		state.pending, state.count = status, 1
+	}
+	if state.pending != board.AgentNone && state.count >= s.stablePolls {
+		state = smoothedStatus{shown: state.pending}
+	}
+
+	s.states[ticketID] = state
+	return state.shown
+}
+
 func WriteStatusFile(sessionName string, status board.AgentStatus) error {
 	homeDir, _ := os.UserHomeDir()
 	statusDir := filepath.Join(homeDir, ".cache", "openkanban-status")
@@ -468,6 +597,21 @@ func WriteStatusFile(sessionName string, status board.AgentStatus) error {
 	return os.WriteFile(statusFile, []byte(statusStr+"\n"), 0644)
 }
 
+// AgentLogPath returns the file a ticket's agent output is appended to when
+// behavior.log_agent_output is on.
+func AgentLogPath(ticketID string) string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".cache", "openkanban-logs", ticketID+".log")
+}
+
+// RemoveAgentLog deletes a ticket's agent output log, if it has one.
+func RemoveAgentLog(ticketID string) error {
+	if err := os.Remove(AgentLogPath(ticketID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
 func CleanupStatusFile(sessionName string) error {
 	homeDir, _ := os.UserHomeDir()
 	statusDir := filepath.Join(homeDir, ".cache", "openkanban-status")
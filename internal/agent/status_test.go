@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
 )
 
 func TestMapOpencodeStatus(t *testing.T) {
@@ -182,6 +183,41 @@ func TestDetectGenericAgentStatus(t *testing.T) {
 	}
 }
 
+func TestDetectCustomStatusPatterns(t *testing.T) {
+	d := NewStatusDetector()
+	d.SetStatusPatterns(map[string]config.AgentConfig{
+		"custom": {
+			StatusPatterns: &config.StatusPatterns{
+				Working: []string{`(?i)arbeite`},
+				Waiting: []string{`Bestätigen\?`, `[invalid`},
+				Error:   []string{`^FEHLER`},
+			},
+		},
+	})
+
+	tests := []struct {
+		name      string
+		agentType string
+		content   string
+		expected  board.AgentStatus
+	}{
+		{"custom working", "custom", "ARBEITE an Aufgabe", board.AgentWorking},
+		{"custom waiting wins over working", "custom", "arbeite\nBestätigen?", board.AgentWaiting},
+		{"custom error", "custom", "FEHLER beim Build", board.AgentError},
+		{"falls back to built-in", "custom", "processing", board.AgentWorking},
+		{"other agents unaffected", "claude", "arbeite", board.AgentNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := d.detectFromTerminalContent(tt.agentType, tt.content)
+			if result != tt.expected {
+				t.Errorf("detectFromTerminalContent(%q, %q) = %q; want %q", tt.agentType, tt.content, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestDetectStatusWithPort_NotRunning(t *testing.T) {
 	d := NewStatusDetector()
 
@@ -232,3 +268,37 @@ func TestReadStatusFile_FromDisk(t *testing.T) {
 		t.Errorf("readStatusFile should return AgentWorking; got %q", result)
 	}
 }
+
+func TestStatusSmoother(t *testing.T) {
+	s := NewStatusSmoother(2)
+	id := board.TicketID("t1")
+
+	steps := []struct {
+		observed board.AgentStatus
+		want     board.AgentStatus
+	}{
+		{board.AgentWorking, board.AgentWorking},     // first status shows immediately
+		{board.AgentIdle, board.AgentWorking},        // single blip is held back
+		{board.AgentWorking, board.AgentWorking},     // and forgotten
+		{board.AgentWaiting, board.AgentWorking},     // new status pending
+		{board.AgentWaiting, board.AgentWaiting},     // seen twice, shown
+		{board.AgentCompleted, board.AgentCompleted}, // completion is immediate
+		{board.AgentNone, board.AgentNone},           // so is stopping
+		{board.AgentIdle, board.AgentIdle},           // restart shows immediately
+	}
+	for i, step := range steps {
+		if got := s.Observe(id, step.observed); got != step.want {
+			t.Errorf("step %d: Observe(%q) = %q, want %q", i, step.observed, got, step.want)
+		}
+	}
+}
+
+func TestStatusSmoother_Disabled(t *testing.T) {
+	s := NewStatusSmoother(0)
+	id := board.TicketID("t1")
+
+	s.Observe(id, board.AgentWorking)
+	if got := s.Observe(id, board.AgentIdle); got != board.AgentIdle {
+		t.Errorf("Observe() = %q, want %q with smoothing disabled", got, board.AgentIdle)
+	}
+}
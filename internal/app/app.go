@@ -1,14 +1,25 @@
 package app
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
 	"syscall"
+	"text/tabwriter"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/techdufus/openkanban/internal/agent"
+	"github.com/techdufus/openkanban/internal/board"
 	"github.com/techdufus/openkanban/internal/config"
 	"github.com/techdufus/openkanban/internal/git"
 	"github.com/techdufus/openkanban/internal/project"
@@ -16,7 +27,9 @@ import (
 	"github.com/techdufus/openkanban/internal/update"
 )
 
-func Run(cfg *config.Config, filterPath, version string) error {
+// Run starts the TUI. validation is the result of validating the config
+// file at configPath, shown in the settings' Config Health view.
+func Run(cfg *config.Config, validation *config.ValidationResult, configPath, filterPath, version string, readOnly bool) error {
 	registry, err := project.LoadRegistry()
 	if err != nil {
 		return fmt.Errorf("failed to load project registry: %w", err)
@@ -27,10 +40,6 @@ func Run(cfg *config.Config, filterPath, version string) error {
 		return fmt.Errorf("failed to load tickets: %w", err)
 	}
 
-	if !globalStore.HasProjects() {
-		return fmt.Errorf("no projects registered. Create one with: openkanban new")
-	}
-
 	var filterProjectID string
 	if filterPath != "" {
 		absPath, _ := filepath.Abs(filterPath)
@@ -42,37 +51,73 @@ func Run(cfg *config.Config, filterPath, version string) error {
 
 	agentMgr := agent.NewManager(cfg)
 
-	opencodeServer := agent.NewOpencodeServer(cfg)
+	opencodeServer := agentMgr.OpencodeServer()
+	// The server may also be started on demand by an opencode spawn.
+	defer opencodeServer.Stop()
 
 	// Only auto-start server if default agent is opencode
 	if cfg.Defaults.DefaultAgent == "opencode" {
 		if err := opencodeServer.Start(); err != nil {
 			return fmt.Errorf("failed to start opencode server: %w", err)
 		}
-		defer opencodeServer.Stop()
 	}
 
 	updateChecker := update.NewChecker(version)
-	model := ui.NewModel(cfg, globalStore, registry, agentMgr, opencodeServer, filterProjectID, updateChecker)
+	model := ui.NewModel(cfg, globalStore, registry, agentMgr, opencodeServer, filterProjectID, updateChecker, readOnly)
+	model.SetConfigHealth(configPath, validation)
 
 	defer model.Cleanup()
 
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	// Bubble Tea reads ctrl+c as a key, so these signals come from outside:
+	// kill, a supervisor, or the terminal closing. Its own handler would
+	// quit without stopping agents, so it is disabled.
+	sigChan := make(chan os.Signal, 2)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
 
-	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseAllMotion())
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseAllMotion(), tea.WithoutSignalHandler())
 
+	done := make(chan struct{})
+	defer close(done)
+	signalled := make(chan struct{})
 	go func() {
-		<-sigChan
-		model.Cleanup()
-		program.Quit()
+		select {
+		case <-sigChan:
+		case <-done:
+			return
+		}
+		close(signalled)
+		// Stop agents through the model so it isn't racing the update
+		// loop; a second signal or a stuck shutdown kills the program,
+		// and the deferred Cleanup still stops whatever is left.
+		program.Send(ui.ShutdownMsg{})
+		select {
+		case <-sigChan:
+		case <-time.After(signalShutdownTimeout):
+		case <-done:
+			return
+		}
+		program.Kill()
 	}()
 
 	_, err = program.Run()
+	select {
+	case <-signalled:
+		if errors.Is(err, tea.ErrProgramKilled) {
+			return nil
+		}
+	default:
+	}
 	return err
 }
 
-func CreateProject(cfg *config.Config, name, repoPath string) error {
+// signalShutdownTimeout bounds how long a signal waits for agents to stop
+// before the program is killed.
+const signalShutdownTimeout = 10 * time.Second
+
+// CreateProject registers repoPath as a project. When fromRef names an
+// existing project, its settings are copied to the new one.
+func CreateProject(cfg *config.Config, name, repoPath, fromRef string) error {
 	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err != nil {
 		return fmt.Errorf("not a git repository: %s", repoPath)
 	}
@@ -89,6 +134,13 @@ func CreateProject(cfg *config.Config, name, repoPath string) error {
 	p := project.NewProject(name, repoPath)
 	// Project settings only store explicit user overrides.
 	// Empty values cascade to global config defaults at runtime.
+	if fromRef != "" {
+		src := findProject(registry, fromRef)
+		if src == nil {
+			return fmt.Errorf("project not found: %s", fromRef)
+		}
+		p.CopySettingsFrom(src)
+	}
 
 	if err := registry.Add(p); err != nil {
 		return fmt.Errorf("failed to save project: %w", err)
@@ -96,6 +148,9 @@ func CreateProject(cfg *config.Config, name, repoPath string) error {
 
 	fmt.Printf("Created project '%s' for %s\n", name, repoPath)
 	fmt.Printf("Project ID: %s\n", p.ID)
+	if fromRef != "" {
+		fmt.Printf("Settings copied from '%s'\n", fromRef)
+	}
 	return nil
 }
 
@@ -138,14 +193,7 @@ func DeleteProject(nameOrID string) error {
 		return err
 	}
 
-	var target *project.Project
-	for _, p := range registry.List() {
-		if p.Name == nameOrID || p.ID == nameOrID || (len(p.ID) >= 8 && p.ID[:8] == nameOrID) {
-			target = p
-			break
-		}
-	}
-
+	target := findProject(registry, nameOrID)
 	if target == nil {
 		return fmt.Errorf("project not found: %s", nameOrID)
 	}
@@ -157,3 +205,775 @@ func DeleteProject(nameOrID string) error {
 	fmt.Printf("Deleted project '%s' (%s)\n", target.Name, target.RepoPath)
 	return nil
 }
+
+// ExportTickets writes every ticket as a JSON array to w, optionally limited
+// to one project (name, ID, ID prefix or repository path) and one status.
+// Tickets are sorted by project name, then creation time. A status that
+// none of the exported projects' columns use is an error.
+func ExportTickets(w io.Writer, projectRef, status string) error {
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load project registry: %w", err)
+	}
+
+	globalStore, err := project.LoadGlobalTicketStore(registry)
+	if err != nil {
+		return fmt.Errorf("failed to load tickets: %w", err)
+	}
+
+	filter := project.NewFilter("export")
+	projects := registry.List()
+	if projectRef != "" {
+		target, err := resolveProject(registry, projectRef)
+		if err != nil {
+			return err
+		}
+		filter.ProjectIDs = []string{target.ID}
+		projects = []*project.Project{target}
+	}
+	if status != "" {
+		if valid := exportStatuses(projects); !slices.Contains(valid, status) {
+			return fmt.Errorf("unknown status %q; valid statuses: %s", status, strings.Join(valid, ", "))
+		}
+		filter.Statuses = []string{status}
+	}
+
+	projectNames := make(map[string]string)
+	for _, p := range registry.List() {
+		projectNames[p.ID] = p.Name
+	}
+
+	tickets := []*board.Ticket{}
+	for _, t := range globalStore.All() {
+		if filter.Matches(t) {
+			tickets = append(tickets, t)
+		}
+	}
+	sort.SliceStable(tickets, func(i, j int) bool {
+		a, b := tickets[i], tickets[j]
+		if projectNames[a.ProjectID] != projectNames[b.ProjectID] {
+			return projectNames[a.ProjectID] < projectNames[b.ProjectID]
+		}
+		if a.ProjectID != b.ProjectID {
+			return a.ProjectID < b.ProjectID
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tickets)
+}
+
+// exportStatuses returns the statuses of the projects' columns in board
+// order, followed by archived.
+func exportStatuses(projects []*project.Project) []string {
+	sets := [][]board.Column{board.DefaultColumns()}
+	for _, p := range projects {
+		sets = append(sets, p.GetColumns())
+	}
+	var statuses []string
+	for _, col := range append(board.MergeColumns(sets...), board.ArchivedColumn()) {
+		statuses = append(statuses, string(col.Status))
+	}
+	return statuses
+}
+
+// ReportAgentTime writes a table of every ticket that has had an agent
+// spawned to w, with how long it has been since the first spawn (until the
+// ticket was completed, or now), followed by per-project and overall
+// totals. An empty projectRef reports on all projects.
+func ReportAgentTime(w io.Writer, projectRef string) error {
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load project registry: %w", err)
+	}
+
+	globalStore, err := project.LoadGlobalTicketStore(registry)
+	if err != nil {
+		return fmt.Errorf("failed to load tickets: %w", err)
+	}
+
+	var target *project.Project
+	if projectRef != "" {
+		if target, err = resolveProject(registry, projectRef); err != nil {
+			return err
+		}
+	}
+
+	projectNames := make(map[string]string)
+	for _, p := range registry.List() {
+		projectNames[p.ID] = p.Name
+	}
+
+	var tickets []*board.Ticket
+	for _, t := range globalStore.All() {
+		if t.AgentSpawnedAt != nil && (target == nil || t.ProjectID == target.ID) {
+			tickets = append(tickets, t)
+		}
+	}
+	if len(tickets) == 0 {
+		_, err := fmt.Fprintln(w, "No agent sessions recorded.")
+		return err
+	}
+	sort.SliceStable(tickets, func(i, j int) bool {
+		a, b := tickets[i], tickets[j]
+		if projectNames[a.ProjectID] != projectNames[b.ProjectID] {
+			return projectNames[a.ProjectID] < projectNames[b.ProjectID]
+		}
+		return a.AgentSpawnedAt.Before(*b.AgentSpawnedAt)
+	})
+
+	now := time.Now()
+	var projectOrder []string
+	projectTotals := make(map[string]time.Duration)
+	projectCounts := make(map[string]int)
+	var total time.Duration
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROJECT\tTICKET\tAGENT\tSTATUS\tSPAWNED\tELAPSED")
+	for _, t := range tickets {
+		end := now
+		if t.CompletedAt != nil && t.CompletedAt.After(*t.AgentSpawnedAt) {
+			end = *t.CompletedAt
+		}
+		elapsed := end.Sub(*t.AgentSpawnedAt)
+
+		name := projectNames[t.ProjectID]
+		if _, seen := projectCounts[name]; !seen {
+			projectOrder = append(projectOrder, name)
+		}
+		projectCounts[name]++
+		projectTotals[name] += elapsed
+		total += elapsed
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", name, t.Title, t.AgentType, t.Status,
+			t.AgentSpawnedAt.Local().Format("2006-01-02 15:04"), formatElapsed(elapsed))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	for _, name := range projectOrder {
+		fmt.Fprintf(w, "%s: %d %s, %s\n", name, projectCounts[name], pluralTickets(projectCounts[name]), formatElapsed(projectTotals[name]))
+	}
+	if len(projectOrder) > 1 {
+		fmt.Fprintf(w, "Total: %d %s, %s\n", len(tickets), pluralTickets(len(tickets)), formatElapsed(total))
+	}
+	return nil
+}
+
+// formatElapsed renders d as hours and minutes, e.g. "2h05m" or "12m".
+func formatElapsed(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func pluralTickets(n int) string {
+	if n == 1 {
+		return "ticket"
+	}
+	return "tickets"
+}
+
+// statsStatuses are the statuses ProjectStats counts, in table order.
+var statsStatuses = []board.TicketStatus{
+	board.StatusBacklog, board.StatusInProgress, board.StatusDone, board.StatusArchived,
+}
+
+// projectStats summarizes one project's tickets for ProjectStats.
+type projectStats struct {
+	Project   string                     `json:"project"`
+	ProjectID string                     `json:"project_id,omitempty"`
+	Statuses  map[board.TicketStatus]int `json:"statuses"`
+	WIP       int                        `json:"wip"`
+	WIPLimit  int                        `json:"wip_limit,omitempty"`
+	// AvgInProgressSeconds averages the time completed tickets spent in
+	// progress; 0 when there are none.
+	AvgInProgressSeconds int64 `json:"avg_in_progress_seconds"`
+	CompletedThisWeek    int   `json:"completed_this_week"`
+
+	inProgressTotal time.Duration
+	timed           int
+}
+
+func newProjectStats(name, id string) *projectStats {
+	s := &projectStats{Project: name, ProjectID: id, Statuses: make(map[board.TicketStatus]int)}
+	for _, status := range statsStatuses {
+		s.Statuses[status] = 0
+	}
+	return s
+}
+
+// add counts t, completed this week if it was completed at or after
+// weekStart. A done or archived ticket's time in progress comes from its
+// History, or from StartedAt to CompletedAt for tickets moved before
+// History was kept.
+func (s *projectStats) add(t *board.Ticket, weekStart, now time.Time) {
+	s.Statuses[t.Status]++
+	if t.Status == board.StatusInProgress {
+		s.WIP++
+	}
+	if t.CompletedAt != nil && !t.CompletedAt.Before(weekStart) {
+		s.CompletedThisWeek++
+	}
+	if t.Status != board.StatusDone && t.Status != board.StatusArchived {
+		return
+	}
+	d, ok := t.TimeInStatus(board.StatusInProgress, now)
+	if !ok && t.StartedAt != nil && t.CompletedAt != nil && t.CompletedAt.After(*t.StartedAt) {
+		d, ok = t.CompletedAt.Sub(*t.StartedAt), true
+	}
+	if ok {
+		s.inProgressTotal += d
+		s.timed++
+		s.AvgInProgressSeconds = int64((s.inProgressTotal / time.Duration(s.timed)).Seconds())
+	}
+}
+
+// startOfWeek returns midnight on the Monday of t's week, in t's location.
+func startOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location())
+}
+
+// ProjectStats writes per-project ticket statistics to w: counts by status,
+// tickets in progress against the In Progress WIP limit, the average time
+// completed tickets spent in progress, and tickets completed since Monday.
+// With asJSON it writes them as a JSON array instead of a table. An empty
+// projectRef reports on all projects.
+func ProjectStats(w io.Writer, projectRef string, asJSON bool) error {
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load project registry: %w", err)
+	}
+
+	globalStore, err := project.LoadGlobalTicketStore(registry)
+	if err != nil {
+		return fmt.Errorf("failed to load tickets: %w", err)
+	}
+
+	projects := registry.List()
+	if projectRef != "" {
+		target, err := resolveProject(registry, projectRef)
+		if err != nil {
+			return err
+		}
+		projects = []*project.Project{target}
+	}
+
+	now := time.Now()
+	weekStart := startOfWeek(now)
+	stats := []*projectStats{}
+	byID := make(map[string]*projectStats)
+	for _, p := range projects {
+		s := newProjectStats(p.Name, p.ID)
+		for _, col := range p.GetColumns() {
+			if col.Status == board.StatusInProgress {
+				s.WIPLimit = col.Limit
+			}
+		}
+		stats = append(stats, s)
+		byID[p.ID] = s
+	}
+	for _, t := range globalStore.All() {
+		if s := byID[t.ProjectID]; s != nil {
+			s.add(t, weekStart, now)
+		}
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	if len(stats) == 0 {
+		_, err := fmt.Fprintln(w, "No projects registered.")
+		return err
+	}
+
+	rows := stats
+	if len(stats) > 1 {
+		total := newProjectStats("Total", "")
+		for _, s := range stats {
+			for status, n := range s.Statuses {
+				total.Statuses[status] += n
+			}
+			total.WIP += s.WIP
+			total.CompletedThisWeek += s.CompletedThisWeek
+			total.inProgressTotal += s.inProgressTotal
+			total.timed += s.timed
+		}
+		if total.timed > 0 {
+			total.AvgInProgressSeconds = int64((total.inProgressTotal / time.Duration(total.timed)).Seconds())
+		}
+		rows = append(rows, total)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROJECT\tBACKLOG\tIN PROGRESS\tDONE\tARCHIVED\tWIP\tAVG IN PROGRESS\tDONE THIS WEEK")
+	for _, s := range rows {
+		wip := fmt.Sprint(s.WIP)
+		if s.WIPLimit > 0 {
+			wip = fmt.Sprintf("%d/%d", s.WIP, s.WIPLimit)
+		}
+		avg := "-"
+		if s.timed > 0 {
+			avg = formatElapsed(time.Duration(s.AvgInProgressSeconds) * time.Second)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s\t%s\t%d\n", s.Project,
+			s.Statuses[board.StatusBacklog], s.Statuses[board.StatusInProgress],
+			s.Statuses[board.StatusDone], s.Statuses[board.StatusArchived],
+			wip, avg, s.CompletedThisWeek)
+	}
+	return tw.Flush()
+}
+
+// ShowBoard writes the board to w as plain text, one column per status,
+// optionally limited to one project (name, ID, ID prefix or repository
+// path). width is the total line width; columns share it evenly.
+func ShowBoard(w io.Writer, projectRef string, width int) error {
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load project registry: %w", err)
+	}
+
+	globalStore, err := project.LoadGlobalTicketStore(registry)
+	if err != nil {
+		return fmt.Errorf("failed to load tickets: %w", err)
+	}
+
+	var columns []board.Column
+	var target *project.Project
+	if projectRef != "" {
+		if target, err = resolveProject(registry, projectRef); err != nil {
+			return err
+		}
+		columns = target.GetColumns()
+	} else {
+		sets := [][]board.Column{board.DefaultColumns()}
+		for _, p := range registry.List() {
+			sets = append(sets, p.GetColumns())
+		}
+		columns = board.MergeColumns(sets...)
+	}
+
+	const sep = " | "
+	colWidth := max((width-len(sep)*(len(columns)-1))/len(columns), showMinColumnWidth)
+
+	cells := make([][]string, len(columns))
+	rows := 0
+	for i, col := range columns {
+		var tickets []*board.Ticket
+		for _, t := range globalStore.GetByStatus(col.Status) {
+			if target == nil || t.ProjectID == target.ID {
+				tickets = append(tickets, t)
+			}
+		}
+		board.SortTickets(tickets)
+
+		cells[i] = append(cells[i], fmt.Sprintf("%s (%d)", col.Name, len(tickets)), strings.Repeat("-", colWidth))
+		for _, t := range tickets {
+			cells[i] = append(cells[i], "- "+t.Title)
+		}
+		rows = max(rows, len(cells[i]))
+	}
+
+	for row := range rows {
+		line := make([]string, len(columns))
+		for i := range columns {
+			var cell string
+			if row < len(cells[i]) {
+				cell = cells[i][row]
+			}
+			line[i] = padCell(cell, colWidth)
+		}
+		if _, err := fmt.Fprintln(w, strings.TrimRight(strings.Join(line, sep), " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// showMinColumnWidth keeps ShowBoard columns readable on narrow widths.
+const showMinColumnWidth = 12
+
+// padCell truncates s to width runes, marking the cut with "...", and pads
+// it with spaces to exactly width.
+func padCell(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		runes = append(runes[:width-3], []rune("...")...)
+	}
+	return string(runes) + strings.Repeat(" ", width-len(runes))
+}
+
+// ImportTickets adds backlog tickets to a project from a file holding either
+// a JSON array of tickets (as written by ExportTickets) or a markdown
+// checklist, where each "- [ ] task" line becomes a ticket. Tickets whose
+// title already exists in the project are skipped. An empty projectRef means
+// the project for the current directory.
+func ImportTickets(w io.Writer, path, projectRef string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	entries, err := parseImportFile(path, data)
+	if err != nil {
+		return err
+	}
+
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load project registry: %w", err)
+	}
+
+	if projectRef == "" {
+		projectRef, _ = os.Getwd()
+	}
+	target, err := resolveProject(registry, projectRef)
+	if err != nil {
+		return err
+	}
+
+	store, err := project.LoadTicketStore(target)
+	if err != nil {
+		return fmt.Errorf("failed to load tickets: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range store.All() {
+		seen[importKey(t.Title)] = true
+	}
+
+	imported, skipped := 0, 0
+	for _, entry := range entries {
+		key := importKey(entry.Title)
+		if key == "" {
+			continue
+		}
+		if seen[key] {
+			skipped++
+			continue
+		}
+		seen[key] = true
+
+		ticket := board.NewTicket(strings.TrimSpace(entry.Title), target.ID)
+		ticket.Description = entry.Description
+		if len(entry.Labels) > 0 {
+			ticket.Labels = entry.Labels
+		}
+		if entry.Priority >= 1 && entry.Priority <= 5 {
+			ticket.Priority = entry.Priority
+		}
+		store.Add(ticket)
+		imported++
+	}
+
+	if imported > 0 {
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save tickets: %w", err)
+		}
+	}
+
+	fmt.Fprintf(w, "Imported %d ticket(s) into '%s', skipped %d duplicate(s)\n", imported, target.Name, skipped)
+	return nil
+}
+
+var checklistItemRegex = regexp.MustCompile(`^\s*[-*+] \[ \] (.+)$`)
+
+// parseImportFile reads import entries from JSON when the file has a .json
+// extension or starts with '[', and from a markdown checklist otherwise.
+func parseImportFile(path string, data []byte) ([]board.Ticket, error) {
+	trimmed := bytes.TrimSpace(data)
+	if strings.EqualFold(filepath.Ext(path), ".json") || bytes.HasPrefix(trimmed, []byte("[")) {
+		var entries []board.Ticket
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return entries, nil
+	}
+
+	var entries []board.Ticket
+	for _, line := range strings.Split(string(data), "\n") {
+		if match := checklistItemRegex.FindStringSubmatch(strings.TrimRight(line, "\r")); match != nil {
+			entries = append(entries, board.Ticket{Title: match[1]})
+		}
+	}
+	return entries, nil
+}
+
+func importKey(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+// ListWorktrees writes every git worktree of each project's repository (or
+// just projectRef's) with the ticket using it, its branch and its size on
+// disk. Worktrees no ticket points at are flagged as orphans.
+func ListWorktrees(w io.Writer, projectRef string) error {
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load project registry: %w", err)
+	}
+
+	globalStore, err := project.LoadGlobalTicketStore(registry)
+	if err != nil {
+		return fmt.Errorf("failed to load tickets: %w", err)
+	}
+
+	projects, err := selectProjects(registry, projectRef)
+	if err != nil {
+		return err
+	}
+
+	type worktreeRow struct {
+		project, ticket, branch, size, path string
+		bytes                               int64
+		orphan                              bool
+	}
+	var rows []worktreeRow
+	var failures []string
+	for _, p := range projects {
+		tickets := globalStore.ProjectTickets(p.ID)
+		mgr := git.NewWorktreeManager(p)
+		worktrees, err := mgr.ListWorktrees()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", p.Name, err))
+			continue
+		}
+		for _, wt := range worktrees {
+			if filepath.Clean(wt.Path) == filepath.Clean(p.RepoPath) {
+				continue
+			}
+
+			row := worktreeRow{project: p.Name, branch: wt.Branch, path: wt.Path, size: "-"}
+			if row.branch == "" {
+				row.branch = "(detached)"
+			}
+			if t := worktreeTicket(tickets, wt); t != nil {
+				row.ticket = t.Title
+				if key := p.TicketKey(t.Number); key != "" {
+					row.ticket = key + " " + t.Title
+				}
+			} else {
+				row.ticket = "(orphan)"
+				row.orphan = true
+			}
+			if size, err := mgr.WorktreeSize(wt.Path); err == nil {
+				row.bytes = size
+				row.size = formatSize(size)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No worktrees found.")
+	} else {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "PROJECT\tTICKET\tBRANCH\tSIZE\tPATH")
+		var total, orphanTotal int64
+		orphans := 0
+		for _, r := range rows {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.project, r.ticket, r.branch, r.size, r.path)
+			total += r.bytes
+			if r.orphan {
+				orphans++
+				orphanTotal += r.bytes
+			}
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "%d %s, %s", len(rows), pluralWorktrees(len(rows)), formatSize(total))
+		if orphans > 0 {
+			fmt.Fprintf(w, " (%d %s, %s)", orphans, pluralOrphans(orphans), formatSize(orphanTotal))
+		}
+		fmt.Fprintln(w)
+	}
+
+	for _, f := range failures {
+		fmt.Fprintf(w, "Skipped %s\n", f)
+	}
+	return nil
+}
+
+// PruneWorktrees removes worktrees that no ticket points at, as listed by
+// ListWorktrees, after running git worktree prune to drop entries whose
+// directories are already gone. Only worktrees inside the project's worktree
+// directory are removed, and ones with uncommitted changes are kept. With
+// dryRun set nothing is changed; it reports what would be removed.
+func PruneWorktrees(w io.Writer, projectRef string, dryRun bool) error {
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load project registry: %w", err)
+	}
+
+	globalStore, err := project.LoadGlobalTicketStore(registry)
+	if err != nil {
+		return fmt.Errorf("failed to load tickets: %w", err)
+	}
+
+	projects, err := selectProjects(registry, projectRef)
+	if err != nil {
+		return err
+	}
+
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+	}
+	removed := 0
+	var reclaimed int64
+	for _, p := range projects {
+		mgr := git.NewWorktreeManager(p)
+
+		stale, err := mgr.PruneWorktrees(dryRun)
+		if err != nil {
+			fmt.Fprintf(w, "Skipped %s: %v\n", p.Name, err)
+			continue
+		}
+		for _, line := range stale {
+			fmt.Fprintf(w, "%s: %s\n", p.Name, line)
+		}
+
+		worktrees, err := mgr.ListWorktrees()
+		if err != nil {
+			fmt.Fprintf(w, "Skipped %s: %v\n", p.Name, err)
+			continue
+		}
+		tickets := globalStore.ProjectTickets(p.ID)
+		worktreeDir := filepath.Clean(p.GetWorktreeDir()) + string(filepath.Separator)
+		for _, wt := range worktrees {
+			if filepath.Clean(wt.Path) == filepath.Clean(p.RepoPath) || worktreeTicket(tickets, wt) != nil {
+				continue
+			}
+			if !strings.HasPrefix(filepath.Clean(wt.Path), worktreeDir) {
+				fmt.Fprintf(w, "%s: kept %s (outside %s)\n", p.Name, wt.Path, p.GetWorktreeDir())
+				continue
+			}
+			if dirty, err := mgr.HasUncommittedChanges(wt.Path); err != nil || dirty {
+				fmt.Fprintf(w, "%s: kept %s (uncommitted changes)\n", p.Name, wt.Path)
+				continue
+			}
+
+			size, _ := mgr.WorktreeSize(wt.Path)
+			if !dryRun {
+				if err := mgr.RemoveWorktree(wt.Path); err != nil {
+					fmt.Fprintf(w, "%s: %v\n", p.Name, err)
+					continue
+				}
+			}
+			removed++
+			reclaimed += size
+			fmt.Fprintf(w, "%s: %s %s (%s, %s)\n", p.Name, strings.ToLower(verb), wt.Path, wt.Branch, formatSize(size))
+		}
+	}
+
+	if removed == 0 {
+		fmt.Fprintln(w, "No orphaned worktrees to remove.")
+		return nil
+	}
+	fmt.Fprintf(w, "\n%s %d orphaned %s, %s.", verb, removed, pluralWorktrees(removed), formatSize(reclaimed))
+	if dryRun {
+		fmt.Fprint(w, " Run with --dry-run=false to remove them.")
+	} else {
+		fmt.Fprint(w, " Their branches were kept.")
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// worktreeTicket returns the ticket whose worktree path or branch is wt's.
+func worktreeTicket(tickets []*board.Ticket, wt git.Worktree) *board.Ticket {
+	for _, t := range tickets {
+		if t.WorktreePath != "" && filepath.Clean(t.WorktreePath) == filepath.Clean(wt.Path) {
+			return t
+		}
+	}
+	if wt.Branch == "" {
+		return nil
+	}
+	for _, t := range tickets {
+		if t.BranchName == wt.Branch {
+			return t
+		}
+	}
+	return nil
+}
+
+// selectProjects returns the project named by ref, or every project sorted
+// by name when ref is empty.
+func selectProjects(registry *project.ProjectRegistry, ref string) ([]*project.Project, error) {
+	if ref != "" {
+		p, err := resolveProject(registry, ref)
+		if err != nil {
+			return nil, err
+		}
+		return []*project.Project{p}, nil
+	}
+	projects := registry.List()
+	sort.SliceStable(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects, nil
+}
+
+// formatSize renders n bytes with a binary unit, e.g. "512 B" or "1.5 GiB".
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func pluralWorktrees(n int) string {
+	if n == 1 {
+		return "worktree"
+	}
+	return "worktrees"
+}
+
+func pluralOrphans(n int) string {
+	if n == 1 {
+		return "orphan"
+	}
+	return "orphans"
+}
+
+// resolveProject finds a project by name or ID, falling back to treating
+// ref as a path inside the project's repository.
+func resolveProject(registry *project.ProjectRegistry, ref string) (*project.Project, error) {
+	if p := findProject(registry, ref); p != nil {
+		return p, nil
+	}
+	absPath, _ := filepath.Abs(ref)
+	if p, err := registry.FindByPath(git.ResolveMainRepo(absPath)); err == nil {
+		return p, nil
+	}
+	return nil, fmt.Errorf("project not found: %s", ref)
+}
+
+// findProject looks a project up by name, full ID or short (8 character) ID.
+func findProject(registry *project.ProjectRegistry, nameOrID string) *project.Project {
+	for _, p := range registry.List() {
+		if p.Name == nameOrID || p.ID == nameOrID || (len(p.ID) >= 8 && p.ID[:8] == nameOrID) {
+			return p
+		}
+	}
+	return nil
+}
@@ -3,10 +3,18 @@
 package app_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/techdufus/openkanban/internal/app"
 	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
 	"github.com/techdufus/openkanban/internal/project"
 	"github.com/techdufus/openkanban/internal/testutil"
 )
@@ -179,6 +187,386 @@ func TestIntegration_MultipleProjects(t *testing.T) {
 	}
 }
 
+func TestIntegration_ExportTickets(t *testing.T) {
+	env := testutil.NewTestEnv(t)
+	env.InitGitRepo()
+
+	p := env.CreateProject("export-test")
+	store, err := project.LoadTicketStore(p)
+	if err != nil {
+		t.Fatalf("failed to load ticket store: %v", err)
+	}
+
+	first := board.NewTicket("First", p.ID)
+	second := board.NewTicket("Second", p.ID)
+	second.CreatedAt = first.CreatedAt.Add(time.Minute)
+	second.Status = board.StatusInProgress
+	store.Add(second)
+	store.Add(first)
+	if err := store.Save(); err != nil {
+		t.Fatalf("failed to save tickets: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := app.ExportTickets(&buf, "export-test", ""); err != nil {
+		t.Fatalf("ExportTickets() error = %v", err)
+	}
+	var exported []board.Ticket
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+		t.Fatalf("export is not valid JSON: %v", err)
+	}
+	if len(exported) != 2 || exported[0].Title != "First" || exported[1].Title != "Second" {
+		t.Fatalf("exported %d tickets in wrong order: %+v", len(exported), exported)
+	}
+
+	buf.Reset()
+	if err := app.ExportTickets(&buf, "", string(board.StatusInProgress)); err != nil {
+		t.Fatalf("ExportTickets() error = %v", err)
+	}
+	exported = nil
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+		t.Fatalf("export is not valid JSON: %v", err)
+	}
+	if len(exported) != 1 || exported[0].Title != "Second" {
+		t.Errorf("status filter exported %+v; want only Second", exported)
+	}
+
+	if err := app.ExportTickets(&buf, "missing", ""); err == nil {
+		t.Error("ExportTickets() with unknown project should fail")
+	}
+	err = app.ExportTickets(&buf, "", "in-progress")
+	if err == nil || !strings.Contains(err.Error(), "in_progress") {
+		t.Errorf("ExportTickets() with unknown status = %v; want an error listing the valid statuses", err)
+	}
+}
+
+func TestIntegration_ShowBoard(t *testing.T) {
+	env := testutil.NewTestEnv(t)
+	env.InitGitRepo()
+
+	p := env.CreateProject("show-test")
+	store, err := project.LoadTicketStore(p)
+	if err != nil {
+		t.Fatalf("failed to load ticket store: %v", err)
+	}
+
+	queued := board.NewTicket("Queued work", p.ID)
+	started := board.NewTicket("A ticket with a rather long title", p.ID)
+	started.Status = board.StatusInProgress
+	store.Add(queued)
+	store.Add(started)
+	if err := store.Save(); err != nil {
+		t.Fatalf("failed to save tickets: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := app.ShowBoard(&buf, "show-test", 60); err != nil {
+		t.Fatalf("ShowBoard() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("ShowBoard() printed %d lines; want 3:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "Backlog (1)") || !strings.Contains(lines[0], "In Progress (1)") {
+		t.Errorf("header = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[2], "- Queued work") || !strings.Contains(lines[2], "- A ticket with...") {
+		t.Errorf("ticket row = %q", lines[2])
+	}
+	if strings.Contains(buf.String(), "\x1b") {
+		t.Error("ShowBoard() output contains escape sequences")
+	}
+}
+
+func TestIntegration_ReportAgentTime(t *testing.T) {
+	env := testutil.NewTestEnv(t)
+	env.InitGitRepo()
+
+	p := env.CreateProject("report-test")
+	store, err := project.LoadTicketStore(p)
+	if err != nil {
+		t.Fatalf("failed to load ticket store: %v", err)
+	}
+
+	spawned := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	completed := spawned.Add(2*time.Hour + 5*time.Minute)
+	done := board.NewTicket("Finished work", p.ID)
+	done.AgentType = "claude"
+	done.Status = board.StatusDone
+	done.AgentSpawnedAt = &spawned
+	done.CompletedAt = &completed
+	store.Add(done)
+	store.Add(board.NewTicket("Never started", p.ID))
+	if err := store.Save(); err != nil {
+		t.Fatalf("failed to save tickets: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := app.ReportAgentTime(&buf, "report-test"); err != nil {
+		t.Fatalf("ReportAgentTime() error = %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "Finished work") || !strings.Contains(out, "2h05m") {
+		t.Errorf("report missing the finished ticket:\n%s", out)
+	}
+	if strings.Contains(out, "Never started") {
+		t.Errorf("report lists a ticket without an agent:\n%s", out)
+	}
+	if !strings.Contains(out, "report-test: 1 ticket, 2h05m") {
+		t.Errorf("report missing project total:\n%s", out)
+	}
+}
+
+func TestIntegration_ProjectStats(t *testing.T) {
+	env := testutil.NewTestEnv(t)
+	env.InitGitRepo()
+
+	p := env.CreateProject("stats-test")
+	store, err := project.LoadTicketStore(p)
+	if err != nil {
+		t.Fatalf("failed to load ticket store: %v", err)
+	}
+
+	completed := time.Now()
+	started := completed.Add(-(3*time.Hour + 30*time.Minute))
+	done := board.NewTicket("Finished work", p.ID)
+	done.Status = board.StatusDone
+	done.StartedAt = &started
+	done.CompletedAt = &completed
+	store.Add(done)
+
+	working := board.NewTicket("In flight", p.ID)
+	working.Status = board.StatusInProgress
+	store.Add(working)
+	store.Add(board.NewTicket("Later", p.ID))
+	store.Add(board.NewTicket("Much later", p.ID))
+	if err := store.Save(); err != nil {
+		t.Fatalf("failed to save tickets: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := app.ProjectStats(&buf, "stats-test", false); err != nil {
+		t.Fatalf("ProjectStats() error = %v", err)
+	}
+	fields := strings.Fields(strings.Split(buf.String(), "\n")[1])
+	want := []string{"stats-test", "2", "1", "1", "0", "1/3", "3h30m", "1"}
+	if strings.Join(fields, " ") != strings.Join(want, " ") {
+		t.Errorf("stats row = %v, want %v\n%s", fields, want, buf.String())
+	}
+
+	buf.Reset()
+	if err := app.ProjectStats(&buf, "stats-test", true); err != nil {
+		t.Fatalf("ProjectStats() JSON error = %v", err)
+	}
+	var stats []struct {
+		Project              string         `json:"project"`
+		Statuses             map[string]int `json:"statuses"`
+		WIP                  int            `json:"wip"`
+		WIPLimit             int            `json:"wip_limit"`
+		AvgInProgressSeconds int64          `json:"avg_in_progress_seconds"`
+		CompletedThisWeek    int            `json:"completed_this_week"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(stats) != 1 {
+		t.Fatalf("got %d projects, want 1", len(stats))
+	}
+	s := stats[0]
+	if s.Project != "stats-test" || s.Statuses["backlog"] != 2 || s.WIP != 1 || s.WIPLimit != 3 ||
+		s.AvgInProgressSeconds != int64((3*time.Hour+30*time.Minute).Seconds()) || s.CompletedThisWeek != 1 {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+}
+
+func TestIntegration_ListWorktrees(t *testing.T) {
+	env := testutil.NewTestEnv(t)
+	env.InitGitRepo()
+	commit := exec.Command("git", "commit", "-q", "--allow-empty", "-m", "initial")
+	commit.Dir = env.RepoDir
+	if out, err := commit.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %s: %v", out, err)
+	}
+
+	p := env.CreateProject("worktrees-test")
+	mgr := git.NewWorktreeManager(p)
+	tracked, err := mgr.CreateWorktree("task/tracked", "HEAD")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tracked, "data.bin"), make([]byte, 2048), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.CreateWorktree("task/lost", "HEAD"); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+
+	store, err := project.LoadTicketStore(p)
+	if err != nil {
+		t.Fatalf("failed to load ticket store: %v", err)
+	}
+	ticket := board.NewTicket("Tracked work", p.ID)
+	ticket.WorktreePath = tracked
+	ticket.BranchName = "task/tracked"
+	store.Add(ticket)
+	if err := store.Save(); err != nil {
+		t.Fatalf("failed to save tickets: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := app.ListWorktrees(&buf, "worktrees-test"); err != nil {
+		t.Fatalf("ListWorktrees() error = %v", err)
+	}
+	out := buf.String()
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.Contains(line, "task/tracked"):
+			if !strings.Contains(line, "Tracked work") || !strings.Contains(line, "2.0 KiB") {
+				t.Errorf("tracked worktree row = %q", line)
+			}
+		case strings.Contains(line, "task/lost"):
+			if !strings.Contains(line, "(orphan)") {
+				t.Errorf("untracked worktree not flagged as orphan: %q", line)
+			}
+		}
+	}
+	if !strings.Contains(out, "2 worktrees") || !strings.Contains(out, "(1 orphan,") {
+		t.Errorf("summary missing:\n%s", out)
+	}
+}
+
+func TestIntegration_PruneWorktrees(t *testing.T) {
+	env := testutil.NewTestEnv(t)
+	env.InitGitRepo()
+	commit := exec.Command("git", "commit", "-q", "--allow-empty", "-m", "initial")
+	commit.Dir = env.RepoDir
+	if out, err := commit.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %s: %v", out, err)
+	}
+
+	p := env.CreateProject("prune-test")
+	mgr := git.NewWorktreeManager(p)
+	paths := make(map[string]string)
+	for _, branch := range []string{"task/tracked", "task/lost", "task/dirty"} {
+		path, err := mgr.CreateWorktree(branch, "HEAD")
+		if err != nil {
+			t.Fatalf("CreateWorktree(%s) error = %v", branch, err)
+		}
+		paths[branch] = path
+	}
+	if err := os.WriteFile(filepath.Join(paths["task/dirty"], "wip.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := project.LoadTicketStore(p)
+	if err != nil {
+		t.Fatalf("failed to load ticket store: %v", err)
+	}
+	ticket := board.NewTicket("Tracked work", p.ID)
+	ticket.BranchName = "task/tracked"
+	store.Add(ticket)
+	if err := store.Save(); err != nil {
+		t.Fatalf("failed to save tickets: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := app.PruneWorktrees(&buf, "prune-test", true); err != nil {
+		t.Fatalf("PruneWorktrees(dry run) error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "would remove "+paths["task/lost"]) {
+		t.Errorf("dry run does not report the orphan:\n%s", buf.String())
+	}
+	for branch, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("dry run removed %s", branch)
+		}
+	}
+
+	buf.Reset()
+	if err := app.PruneWorktrees(&buf, "prune-test", false); err != nil {
+		t.Fatalf("PruneWorktrees() error = %v", err)
+	}
+	out := buf.String()
+	if _, err := os.Stat(paths["task/lost"]); !os.IsNotExist(err) {
+		t.Errorf("orphaned worktree not removed:\n%s", out)
+	}
+	if _, err := os.Stat(paths["task/tracked"]); err != nil {
+		t.Errorf("worktree matched by branch was removed:\n%s", out)
+	}
+	if _, err := os.Stat(paths["task/dirty"]); err != nil || !strings.Contains(out, "uncommitted changes") {
+		t.Errorf("dirty orphan not kept:\n%s", out)
+	}
+	if !mgr.BranchExists("task/lost") {
+		t.Error("prune deleted the orphan's branch")
+	}
+}
+
+func TestIntegration_ImportTickets(t *testing.T) {
+	env := testutil.NewTestEnv(t)
+	env.InitGitRepo()
+
+	p := env.CreateProject("import-test")
+	store, err := project.LoadTicketStore(p)
+	if err != nil {
+		t.Fatalf("failed to load ticket store: %v", err)
+	}
+	store.Add(board.NewTicket("Existing task", p.ID))
+	if err := store.Save(); err != nil {
+		t.Fatalf("failed to save tickets: %v", err)
+	}
+
+	todo := filepath.Join(t.TempDir(), "TODO.md")
+	content := "# Tasks\n\n- [ ] Write docs\n- [x] Already done\n- [ ] existing task\n  * [ ] Nested item\n"
+	if err := os.WriteFile(todo, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := app.ImportTickets(&out, todo, "import-test"); err != nil {
+		t.Fatalf("ImportTickets() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Imported 2 ticket(s)") || !strings.Contains(out.String(), "skipped 1") {
+		t.Errorf("ImportTickets() output = %q", out.String())
+	}
+
+	titles := importedTitles(t, p)
+	if len(titles) != 3 || titles["Nested item"] == nil || titles["Write docs"] == nil {
+		t.Fatalf("tickets after markdown import = %v", titles)
+	}
+	if titles["Nested item"].Status != board.StatusBacklog {
+		t.Errorf("imported status = %s; want %s", titles["Nested item"].Status, board.StatusBacklog)
+	}
+
+	jsonFile := filepath.Join(t.TempDir(), "tickets.json")
+	if err := os.WriteFile(jsonFile, []byte(`[{"title": "From JSON", "priority": 1}, {"title": "Write docs"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := app.ImportTickets(&out, jsonFile, p.ID); err != nil {
+		t.Fatalf("ImportTickets() error = %v", err)
+	}
+	titles = importedTitles(t, p)
+	if len(titles) != 4 || titles["From JSON"] == nil {
+		t.Fatalf("tickets after JSON import = %v", titles)
+	}
+	if titles["From JSON"].Priority != 1 {
+		t.Errorf("imported priority = %d; want 1", titles["From JSON"].Priority)
+	}
+}
+
+func importedTitles(t *testing.T, p *project.Project) map[string]*board.Ticket {
+	t.Helper()
+	store, err := project.LoadTicketStore(p)
+	if err != nil {
+		t.Fatalf("failed to load tickets: %v", err)
+	}
+	titles := make(map[string]*board.Ticket)
+	for _, ticket := range store.All() {
+		titles[ticket.Title] = ticket
+	}
+	return titles
+}
+
 func initGitRepo(dir string) error {
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
@@ -1,7 +1,10 @@
 package board
 
 import (
+	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
@@ -67,8 +70,11 @@ const (
 )
 
 type Ticket struct {
-	ID          TicketID     `json:"id"`
-	ProjectID   string       `json:"project_id"`
+	ID        TicketID `json:"id"`
+	ProjectID string   `json:"project_id"`
+	// Number is the ticket's sequence number within its project, shown as
+	// its short ID (e.g. ENG-12); 0 until the ticket is added to a store.
+	Number      int          `json:"number,omitempty"`
 	Title       string       `json:"title"`
 	Description string       `json:"description,omitempty"`
 	Status      TicketStatus `json:"status"`
@@ -77,21 +83,40 @@ type Ticket struct {
 	WorktreePath string `json:"worktree_path,omitempty"`
 	BranchName   string `json:"branch_name,omitempty"`
 	BaseBranch   string `json:"base_branch,omitempty"`
+	// WorktreeExternal marks a worktree attached by hand rather than created
+	// by OpenKanban; cleanup never removes it.
+	WorktreeExternal bool `json:"worktree_external,omitempty"`
 
 	AgentType      string      `json:"agent_type,omitempty"`
 	AgentStatus    AgentStatus `json:"agent_status"`
 	AgentSpawnedAt *time.Time  `json:"agent_spawned_at,omitempty"`
 	AgentPort      int         `json:"agent_port,omitempty"`
 	AgentSessionID string      `json:"agent_session_id,omitempty"`
+	// AgentOverride is the agent type picked for the next spawn of a ticket
+	// whose agent already ran. That spawn starts a new session and makes it
+	// the AgentType.
+	AgentOverride string `json:"agent_override,omitempty"`
+	// AgentArgs are appended to the configured agent args at spawn time.
+	AgentArgs []string `json:"agent_args,omitempty"`
+	// ContextFiles are repo-relative paths listed in the agent's init prompt.
+	ContextFiles []string `json:"context_files,omitempty"`
 
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 	StartedAt   *time.Time `json:"started_at,omitempty"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	// SnoozedUntil hides the ticket from the board until then.
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+	// History lists the ticket's status changes, oldest first.
+	History []StatusChange `json:"history,omitempty"`
 
 	Labels   []string          `json:"labels,omitempty"`
 	Priority int               `json:"priority,omitempty"`
 	Meta     map[string]string `json:"meta,omitempty"`
+	// Order is the ticket's hand-set position among tickets of the same
+	// priority, 1 first; 0 means never reordered.
+	Order int `json:"order,omitempty"`
 
 	// Dependencies - tickets that block this one (informational only, no enforcement)
 	BlockedBy []TicketID `json:"blocked_by,omitempty"`
@@ -106,7 +131,7 @@ func NewTicket(title, projectID string) *Ticket {
 		Status:      StatusBacklog,
 		AgentStatus: AgentNone,
 		UseWorktree: true,
-		Priority:    3,
+		Priority:    DefaultPriority,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 		Labels:      []string{},
@@ -118,8 +143,18 @@ func (t *Ticket) Touch() {
 	t.UpdatedAt = time.Now()
 }
 
+// StatusChange records a ticket moving from one status to another.
+type StatusChange struct {
+	From TicketStatus `json:"from"`
+	To   TicketStatus `json:"to"`
+	At   time.Time    `json:"at"`
+}
+
 func (t *Ticket) SetStatus(status TicketStatus) {
 	now := time.Now()
+	if status != t.Status {
+		t.History = append(t.History, StatusChange{From: t.Status, To: status, At: now})
+	}
 	t.Status = status
 	t.UpdatedAt = now
 
@@ -131,12 +166,208 @@ func (t *Ticket) SetStatus(status TicketStatus) {
 	}
 }
 
+// TimeInStatus sums the time the ticket has spent in status according to
+// History, counting a stay that hasn't ended yet up to now. ok is false when
+// History doesn't record the ticket entering status.
+func (t *Ticket) TimeInStatus(status TicketStatus, now time.Time) (d time.Duration, ok bool) {
+	var since time.Time
+	in := false
+	for _, c := range t.History {
+		if in && c.From == status {
+			d += c.At.Sub(since)
+			in = false
+		}
+		if c.To == status {
+			since, in, ok = c.At, true, true
+		}
+	}
+	if in {
+		d += now.Sub(since)
+	}
+	return d, ok
+}
+
+// DueSoonWindow is how close a due date must be for a ticket to count as
+// due soon.
+const DueSoonWindow = 24 * time.Hour
+
+// IsSnoozed reports whether the ticket is snoozed past now.
+func (t *Ticket) IsSnoozed(now time.Time) bool {
+	return t.SnoozedUntil != nil && now.Before(*t.SnoozedUntil)
+}
+
+// IsOverdue reports whether the ticket has a due date in the past and is
+// not yet done or archived.
+func (t *Ticket) IsOverdue(now time.Time) bool {
+	if t.DueAt == nil || t.Status == StatusDone || t.Status == StatusArchived {
+		return false
+	}
+	return now.After(*t.DueAt)
+}
+
+// IsDueSoon reports whether the ticket is due within DueSoonWindow but not
+// yet overdue.
+func (t *Ticket) IsDueSoon(now time.Time) bool {
+	if t.DueAt == nil || t.Status == StatusDone || t.Status == StatusArchived {
+		return false
+	}
+	return !now.After(*t.DueAt) && t.DueAt.Sub(now) <= DueSoonWindow
+}
+
+// ParseDueDate parses a due date as either an absolute date ("2024-06-01",
+// due at the end of that day) or an offset from now ("+3d", "+2w", "+12h").
+// An empty string clears the due date and returns nil.
+func ParseDueDate(s string, now time.Time) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(s, "+") {
+		if len(s) < 3 {
+			return nil, fmt.Errorf("invalid due date %q: use +Nd, +Nw or +Nh", s)
+		}
+		unit := s[len(s)-1]
+		n, err := strconv.Atoi(s[1 : len(s)-1])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid due date %q", s)
+		}
+		var d time.Duration
+		switch unit {
+		case 'h':
+			d = time.Duration(n) * time.Hour
+		case 'd':
+			d = time.Duration(n) * 24 * time.Hour
+		case 'w':
+			d = time.Duration(n) * 7 * 24 * time.Hour
+		default:
+			return nil, fmt.Errorf("invalid due date %q: unit must be h, d or w", s)
+		}
+		due := now.Add(d)
+		return &due, nil
+	}
+
+	day, err := time.ParseInLocation(DueDateLayout, s, now.Location())
+	if err != nil {
+		return nil, fmt.Errorf("invalid due date %q: use YYYY-MM-DD or +Nd", s)
+	}
+	due := day.Add(24*time.Hour - time.Second)
+	return &due, nil
+}
+
+// ParseSnoozeTime parses when a snooze ends: an offset from now ("+3d",
+// "+2w", "+12h"), a date ("2024-06-01", from the start of that day) or a
+// date and time ("2024-06-01 14:00"). The time must be in the future.
+func ParseSnoozeTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	var until time.Time
+	switch {
+	case strings.HasPrefix(s, "+"):
+		at, err := ParseDueDate(s, now)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid snooze time %q: unit must be h, d or w", s)
+		}
+		until = *at
+	default:
+		var err error
+		until, err = time.ParseInLocation(DueDateLayout+" 15:04", s, now.Location())
+		if err != nil {
+			until, err = time.ParseInLocation(DueDateLayout, s, now.Location())
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid snooze time %q: use +Nd, YYYY-MM-DD or YYYY-MM-DD HH:MM", s)
+		}
+	}
+	if !until.After(now) {
+		return time.Time{}, fmt.Errorf("snooze time %q is not in the future", s)
+	}
+	return until, nil
+}
+
+// DueDateLayout is the layout used to show and enter absolute due dates.
+const DueDateLayout = "2006-01-02"
+
+// DefaultPriority is the priority given to new tickets; lower is more urgent.
+const DefaultPriority = 3
+
+// SortTickets orders tickets by priority (1 first), then by hand-set Order,
+// then oldest first, so columns keep a stable order between refreshes. Unset
+// priorities sort as DefaultPriority; tickets never reordered come after
+// those that were.
+func SortTickets(tickets []*Ticket) {
+	sort.SliceStable(tickets, func(i, j int) bool {
+		pi, pj := sortPriority(tickets[i].Priority), sortPriority(tickets[j].Priority)
+		if pi != pj {
+			return pi < pj
+		}
+		oi, oj := tickets[i].Order, tickets[j].Order
+		if oi != oj {
+			if oi == 0 || oj == 0 {
+				return oj == 0
+			}
+			return oi < oj
+		}
+		if !tickets[i].CreatedAt.Equal(tickets[j].CreatedAt) {
+			return tickets[i].CreatedAt.Before(tickets[j].CreatedAt)
+		}
+		return tickets[i].ID < tickets[j].ID
+	})
+}
+
+// NextAgentType returns the agent type the next spawn uses: the pending
+// AgentOverride, else AgentType. It is empty when neither is set.
+func (t *Ticket) NextAgentType() string {
+	if t.AgentOverride != "" {
+		return t.AgentOverride
+	}
+	return t.AgentType
+}
+
+// MetaEnvPrefix marks Meta entries that are set as environment variables
+// in the ticket's agent process.
+const MetaEnvPrefix = "env."
+
+// AgentEnv returns the ticket's Meta entries prefixed with MetaEnvPrefix,
+// keyed by the variable name without the prefix. It is nil when there are
+// none.
+func (t *Ticket) AgentEnv() map[string]string {
+	var env map[string]string
+	for key, value := range t.Meta {
+		name, ok := strings.CutPrefix(key, MetaEnvPrefix)
+		if !ok || name == "" {
+			continue
+		}
+		if env == nil {
+			env = map[string]string{}
+		}
+		env[name] = value
+	}
+	return env
+}
+
+// EffectivePriority returns the ticket's priority, counting an unset one as
+// DefaultPriority.
+func (t *Ticket) EffectivePriority() int {
+	return sortPriority(t.Priority)
+}
+
+func sortPriority(p int) int {
+	if p <= 0 {
+		return DefaultPriority
+	}
+	return p
+}
+
 type Column struct {
 	ID     string       `json:"id"`
 	Name   string       `json:"name"`
 	Status TicketStatus `json:"status"`
 	Color  string       `json:"color"`
 	Limit  int          `json:"limit"`
+	// EmptyText and EmptyIcon replace the placeholder shown when the column
+	// has no tickets.
+	EmptyText string `json:"empty_text,omitempty"`
+	EmptyIcon string `json:"empty_icon,omitempty"`
 }
 
 func DefaultColumns() []Column {
@@ -147,6 +378,80 @@ func DefaultColumns() []Column {
 	}
 }
 
+// ArchivedColumn is the column for archived tickets. It is hidden unless
+// archived tickets are toggled on.
+func ArchivedColumn() Column {
+	return Column{
+		ID:        "archived",
+		Name:      "Archived",
+		Status:    StatusArchived,
+		Color:     "#6c7086",
+		EmptyText: "Press A to archive a ticket",
+		EmptyIcon: "▣",
+	}
+}
+
+// ValidateColumns checks a custom column list. Every column needs a status,
+// statuses must be unique, and the backlog, in_progress and done statuses the
+// app moves tickets between must all be present. Archived is reserved for
+// the optional Archived column.
+func ValidateColumns(columns []Column) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	seen := make(map[TicketStatus]bool, len(columns))
+	for i, col := range columns {
+		if col.Status == "" {
+			return fmt.Errorf("column %d (%q) has no status", i+1, col.Name)
+		}
+		if col.Status == StatusArchived {
+			return fmt.Errorf("column %q: status %q is reserved", col.Name, StatusArchived)
+		}
+		if seen[col.Status] {
+			return fmt.Errorf("duplicate column status %q", col.Status)
+		}
+		seen[col.Status] = true
+	}
+
+	for _, required := range []TicketStatus{StatusBacklog, StatusInProgress, StatusDone} {
+		if !seen[required] {
+			return fmt.Errorf("columns must include status %q", required)
+		}
+	}
+	return nil
+}
+
+// MergeColumns combines column sets, keeping the first definition of each
+// status. A status only found in a later set is inserted after the column
+// that precedes it there, so custom columns keep their relative place.
+func MergeColumns(sets ...[]Column) []Column {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	merged := append([]Column(nil), sets[0]...)
+	for _, set := range sets[1:] {
+		insertAt := 0
+		for _, col := range set {
+			idx := -1
+			for i, existing := range merged {
+				if existing.Status == col.Status {
+					idx = i
+					break
+				}
+			}
+			if idx >= 0 {
+				insertAt = idx + 1
+				continue
+			}
+			merged = append(merged[:insertAt], append([]Column{col}, merged[insertAt:]...)...)
+			insertAt++
+		}
+	}
+	return merged
+}
+
 var (
 	ErrTicketNotFound = &BoardError{Message: "ticket not found"}
 )
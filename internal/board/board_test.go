@@ -254,6 +254,52 @@ func TestTicket_SetStatus(t *testing.T) {
 			t.Error("SetStatus should update UpdatedAt")
 		}
 	})
+
+	t.Run("records status changes in History", func(t *testing.T) {
+		ticket := NewTicket("Test", "project-1")
+		ticket.SetStatus(StatusInProgress)
+		ticket.SetStatus(StatusInProgress)
+		ticket.SetStatus(StatusDone)
+
+		want := []StatusChange{
+			{From: StatusBacklog, To: StatusInProgress},
+			{From: StatusInProgress, To: StatusDone},
+		}
+		if len(ticket.History) != len(want) {
+			t.Fatalf("len(History) = %d; want %d", len(ticket.History), len(want))
+		}
+		for i, c := range ticket.History {
+			if c.From != want[i].From || c.To != want[i].To {
+				t.Errorf("History[%d] = %s -> %s; want %s -> %s", i, c.From, c.To, want[i].From, want[i].To)
+			}
+			if c.At.IsZero() {
+				t.Errorf("History[%d].At is not set", i)
+			}
+		}
+	})
+}
+
+func TestTicket_TimeInStatus(t *testing.T) {
+	start := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+
+	ticket := NewTicket("Test", "project-1")
+	ticket.History = []StatusChange{
+		{From: StatusBacklog, To: StatusInProgress, At: at(0)},
+		{From: StatusInProgress, To: StatusBacklog, At: at(2)},
+		{From: StatusBacklog, To: StatusInProgress, At: at(5)},
+		{From: StatusInProgress, To: StatusDone, At: at(6)},
+	}
+
+	if d, ok := ticket.TimeInStatus(StatusInProgress, at(10)); !ok || d != 3*time.Hour {
+		t.Errorf("TimeInStatus(in_progress) = %v, %v; want 3h, true", d, ok)
+	}
+	if d, ok := ticket.TimeInStatus(StatusDone, at(10)); !ok || d != 4*time.Hour {
+		t.Errorf("TimeInStatus(done) = %v, %v; want 4h (still done), true", d, ok)
+	}
+	if _, ok := ticket.TimeInStatus(StatusArchived, at(10)); ok {
+		t.Error("TimeInStatus(archived) ok = true for a ticket never archived")
+	}
 }
 
 func TestDefaultColumns(t *testing.T) {
@@ -289,6 +335,253 @@ func TestDefaultColumns(t *testing.T) {
 	}
 }
 
+func TestArchivedColumn(t *testing.T) {
+	col := ArchivedColumn()
+	if col.Status != StatusArchived || col.Name != "Archived" {
+		t.Errorf("ArchivedColumn() = %+v; want the Archived status column", col)
+	}
+	for _, def := range DefaultColumns() {
+		if def.Status == StatusArchived {
+			t.Error("DefaultColumns() should not include the Archived column")
+		}
+	}
+}
+
+func TestValidateColumns(t *testing.T) {
+	review := Column{ID: "review", Name: "Review", Status: "review"}
+
+	tests := []struct {
+		name    string
+		columns []Column
+		wantErr bool
+	}{
+		{"empty uses defaults", nil, false},
+		{"defaults", DefaultColumns(), false},
+		{"with review", append(DefaultColumns(), review), false},
+		{"duplicate status", append(DefaultColumns(), Column{Name: "Todo", Status: StatusBacklog}), true},
+		{"missing status", append(DefaultColumns(), Column{Name: "Blank"}), true},
+		{"missing done", DefaultColumns()[:2], true},
+		{"reserved archived", append(DefaultColumns(), ArchivedColumn()), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateColumns(tt.columns)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateColumns() error = %v; wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeColumns(t *testing.T) {
+	defaults := DefaultColumns()
+	custom := []Column{
+		defaults[0],
+		defaults[1],
+		{ID: "review", Name: "Review", Status: "review"},
+		defaults[2],
+	}
+
+	merged := MergeColumns(defaults, custom)
+
+	want := []TicketStatus{StatusBacklog, StatusInProgress, "review", StatusDone}
+	if len(merged) != len(want) {
+		t.Fatalf("MergeColumns() returned %d columns; want %d", len(merged), len(want))
+	}
+	for i, status := range want {
+		if merged[i].Status != status {
+			t.Errorf("merged[%d].Status = %q; want %q", i, merged[i].Status, status)
+		}
+	}
+	if len(defaults) != 3 {
+		t.Errorf("MergeColumns modified its first argument")
+	}
+}
+
+func TestSortTickets(t *testing.T) {
+	base := time.Now()
+	tickets := []*Ticket{
+		{ID: "low", Priority: 5, CreatedAt: base},
+		{ID: "newer-default", Priority: 3, CreatedAt: base.Add(2 * time.Minute)},
+		{ID: "unset", Priority: 0, CreatedAt: base.Add(time.Minute)},
+		{ID: "urgent", Priority: 1, CreatedAt: base.Add(3 * time.Minute)},
+		{ID: "older-default", Priority: 3, CreatedAt: base},
+	}
+
+	SortTickets(tickets)
+
+	want := []TicketID{"urgent", "older-default", "unset", "newer-default", "low"}
+	for i, id := range want {
+		if tickets[i].ID != id {
+			t.Errorf("tickets[%d].ID = %q; want %q", i, tickets[i].ID, id)
+		}
+	}
+}
+
+func TestSortTickets_Order(t *testing.T) {
+	base := time.Now()
+	tickets := []*Ticket{
+		{ID: "unordered", CreatedAt: base},
+		{ID: "second", Order: 2, CreatedAt: base.Add(time.Minute)},
+		{ID: "urgent", Priority: 1, CreatedAt: base.Add(2 * time.Minute)},
+		{ID: "first", Order: 1, CreatedAt: base.Add(3 * time.Minute)},
+	}
+
+	SortTickets(tickets)
+
+	want := []TicketID{"urgent", "first", "second", "unordered"}
+	for i, id := range want {
+		if tickets[i].ID != id {
+			t.Errorf("tickets[%d].ID = %q; want %q", i, tickets[i].ID, id)
+		}
+	}
+}
+
+func TestParseDueDate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input   string
+		want    *time.Time
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: "+3d", want: ptrTime(now.Add(72 * time.Hour))},
+		{input: "+1w", want: ptrTime(now.Add(7 * 24 * time.Hour))},
+		{input: "+12h", want: ptrTime(now.Add(12 * time.Hour))},
+		{input: "2024-06-05", want: ptrTime(time.Date(2024, 6, 5, 23, 59, 59, 0, time.UTC))},
+		{input: "+3m", wantErr: true},
+		{input: "+", wantErr: true},
+		{input: "+d", wantErr: true},
+		{input: "tomorrow", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDueDate(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDueDate(%q) error = %v; wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("ParseDueDate(%q) = %v; want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSnoozeTime(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{input: "+3d", want: now.Add(72 * time.Hour)},
+		{input: "+12h", want: now.Add(12 * time.Hour)},
+		{input: "2024-06-05", want: time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC)},
+		{input: "2024-06-01 14:30", want: time.Date(2024, 6, 1, 14, 30, 0, 0, time.UTC)},
+		{input: "2024-06-01", wantErr: true},
+		{input: "2024-05-30 09:00", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "+3m", wantErr: true},
+		{input: "+", wantErr: true},
+		{input: "+d", wantErr: true},
+		{input: "later", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSnoozeTime(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSnoozeTime(%q) error = %v; wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("ParseSnoozeTime(%q) = %v; want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	until := now.Add(time.Hour)
+	ticket := &Ticket{SnoozedUntil: &until}
+	if !ticket.IsSnoozed(now) || ticket.IsSnoozed(until) {
+		t.Error("IsSnoozed should hold until SnoozedUntil and end there")
+	}
+}
+
+func TestTicketDueState(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	soon := now.Add(2 * time.Hour)
+	later := now.Add(72 * time.Hour)
+
+	overdue := &Ticket{Status: StatusBacklog, DueAt: &past}
+	if !overdue.IsOverdue(now) || overdue.IsDueSoon(now) {
+		t.Error("ticket due an hour ago should be overdue only")
+	}
+
+	dueSoon := &Ticket{Status: StatusInProgress, DueAt: &soon}
+	if dueSoon.IsOverdue(now) || !dueSoon.IsDueSoon(now) {
+		t.Error("ticket due in two hours should be due soon only")
+	}
+
+	dueLater := &Ticket{Status: StatusBacklog, DueAt: &later}
+	if dueLater.IsOverdue(now) || dueLater.IsDueSoon(now) {
+		t.Error("ticket due in three days should be neither overdue nor due soon")
+	}
+
+	done := &Ticket{Status: StatusDone, DueAt: &past}
+	if done.IsOverdue(now) {
+		t.Error("done ticket should never be overdue")
+	}
+}
+
+func TestTicket_NextAgentType(t *testing.T) {
+	ticket := &Ticket{}
+	if got := ticket.NextAgentType(); got != "" {
+		t.Errorf("NextAgentType() = %q; want empty", got)
+	}
+
+	ticket.AgentType = "gemini"
+	if got := ticket.NextAgentType(); got != "gemini" {
+		t.Errorf("NextAgentType() = %q; want gemini", got)
+	}
+
+	ticket.AgentOverride = "claude"
+	if got := ticket.NextAgentType(); got != "claude" {
+		t.Errorf("NextAgentType() with override = %q; want claude", got)
+	}
+}
+
+func TestTicket_AgentEnv(t *testing.T) {
+	ticket := NewTicket("test", "proj")
+	if env := ticket.AgentEnv(); env != nil {
+		t.Errorf("AgentEnv() = %v; want nil", env)
+	}
+
+	ticket.Meta["env.API_BASE"] = "http://localhost:8080"
+	ticket.Meta["env.FEATURE_X"] = "1"
+	ticket.Meta["env."] = "ignored"
+	ticket.Meta["owner"] = "alice"
+
+	env := ticket.AgentEnv()
+	want := map[string]string{"API_BASE": "http://localhost:8080", "FEATURE_X": "1"}
+	if len(env) != len(want) {
+		t.Fatalf("AgentEnv() = %v; want %v", env, want)
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("AgentEnv()[%q] = %q; want %q", k, env[k], v)
+		}
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
 func TestBoardError(t *testing.T) {
 	err := &BoardError{Message: "test error"}
 
@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
 )
 
 const defaultGlobalPrompt = `You have been spawned by OpenKanban to work on a ticket.
@@ -137,9 +141,63 @@ You are now working on a specific ticket. This ticket represents a discrete unit
 
 Begin by analyzing the ticket requirements and proposing your approach.`
 
+const defaultQwenPrompt = `You have been spawned by OpenKanban, a kanban board system for managing development tasks.
+
+## Your Assignment
+
+You are now working on a specific ticket. This ticket represents a discrete unit of work that needs to be completed.
+
+**Ticket Title:** {{.Title}}
+
+**Ticket Description:**
+{{.Description}}
+
+## Technical Context
+
+- **Git Branch:** {{.BranchName}}
+- **Base Branch:** {{.BaseBranch}}
+- **Working Directory:** This session is scoped to an isolated git worktree for this ticket
+
+## Expectations
+
+1. Focus exclusively on completing the work described in this ticket
+2. The ticket description above is your primary specification - implement what it describes
+3. If the description is unclear or incomplete, ask clarifying questions before proceeding
+4. Make commits as appropriate for the work being done
+5. When the work is complete, summarize what was accomplished
+
+Begin by analyzing the ticket requirements and proposing your approach.`
+
+const defaultCursorPrompt = `You have been spawned by OpenKanban, a kanban board system for managing development tasks.
+
+## Your Assignment
+
+You are now working on a specific ticket. This ticket represents a discrete unit of work that needs to be completed.
+
+**Ticket Title:** {{.Title}}
+
+**Ticket Description:**
+{{.Description}}
+
+## Technical Context
+
+- **Git Branch:** {{.BranchName}}
+- **Base Branch:** {{.BaseBranch}}
+- **Working Directory:** This session is scoped to an isolated git worktree for this ticket
+
+## Expectations
+
+1. Focus exclusively on completing the work described in this ticket
+2. The ticket description above is your primary specification - implement what it describes
+3. If the description is unclear or incomplete, ask clarifying questions before proceeding
+4. Make commits as appropriate for the work being done
+5. When the work is complete, summarize what was accomplished
+
+Begin by analyzing the ticket requirements and proposing your approach.`
+
 // AgentPriority defines the order in which agents are preferred when auto-detecting.
 // The first available agent in this list becomes the default.
-var AgentPriority = []string{"opencode", "claude", "gemini", "codex", "aider"}
+var AgentPriority = []string{"opencode", "claude", "gemini", "codex", "aider", "qwen", "cursor-agent"}
 
 // DetectAvailableAgent returns the first agent from the priority list
 // whose command is available in PATH. Falls back to the first priority
@@ -163,6 +221,7 @@ type Config struct {
 	UI       UIConfig               `json:"ui"`
 	Cleanup  CleanupSettings        `json:"cleanup"`
 	Behavior BehaviorSettings       `json:"behavior"`
+	Logging  LoggingSettings        `json:"logging"`
 	Opencode OpencodeSettings       `json:"opencode"`
 	Keys     map[string]string      `json:"keys,omitempty"`
 }
@@ -186,15 +245,34 @@ type BoardSettings struct {
 	BranchTemplate   string `json:"branch_template"` // e.g., "{prefix}{slug}"
 	SlugMaxLength    int    `json:"slug_max_length"` // default: 40
 	InitPrompt       string `json:"init_prompt"`
+	// TicketTemplates are named description templates offered in the
+	// create form. A template may reference {{.Title}}.
+	TicketTemplates map[string]string `json:"ticket_templates,omitempty"`
 }
 
 // AgentConfig defines how to spawn and monitor an AI agent
 type AgentConfig struct {
-	Command    string            `json:"command"`
-	Args       []string          `json:"args"`
-	Env        map[string]string `json:"env"`
-	StatusFile string            `json:"status_file"`
-	InitPrompt string            `json:"init_prompt"`
+	Command        string            `json:"command"`
+	Args           []string          `json:"args"`
+	Env            map[string]string `json:"env"`
+	StatusFile     string            `json:"status_file"`
+	InitPrompt     string            `json:"init_prompt"`
+	StatusPatterns *StatusPatterns   `json:"status_patterns,omitempty"`
+	Color          string            `json:"color,omitempty"` // Agent badge background; hex or ANSI 0-255, theme primary when empty
+	// PollIntervalSeconds overrides opencode.poll_interval for how often this
+	// agent's status is checked; 0 uses the global interval.
+	PollIntervalSeconds int `json:"poll_interval_seconds,omitempty"`
+	// ScrollbackLines overrides ui.scrollback_lines for this agent's panes;
+	// 0 uses the global setting.
+	ScrollbackLines int `json:"scrollback_lines,omitempty"`
+}
+
+// StatusPatterns holds regular expressions matched against an agent's recent
+// terminal output before the built-in status phrases.
+type StatusPatterns struct {
+	Working []string `json:"working,omitempty"`
+	Waiting []string `json:"waiting,omitempty"`
+	Error   []string `json:"error,omitempty"`
 }
 
 // UIConfig holds UI-related preferences
@@ -206,7 +284,17 @@ type UIConfig struct {
 	ColumnWidth     int          `json:"column_width"`
 	TicketHeight    int          `json:"ticket_height"`
 	SidebarVisible  bool         `json:"sidebar_visible"`
+	ShowArchived    bool         `json:"show_archived"`
+	ShowSnoozed     bool         `json:"show_snoozed"`
 	ScrollbackLines int          `json:"scrollback_lines"`
+
+	// MaxVisibleColumns caps how many columns are shown side by side before
+	// paging, even when more would fit; 0 fits as many as the width allows.
+	MaxVisibleColumns int `json:"max_visible_columns,omitempty"`
+
+	// CompactCards draws each ticket as a single line. Small terminals get
+	// compact cards even when this is off.
+	CompactCards bool `json:"compact_cards,omitempty"`
 }
 
 // CleanupSettings controls cleanup behavior when deleting tickets
@@ -214,11 +302,44 @@ type CleanupSettings struct {
 	DeleteWorktree       bool `json:"delete_worktree"`        // Remove git worktree on ticket delete
 	DeleteBranch         bool `json:"delete_branch"`          // Delete git branch after worktree removal
 	ForceWorktreeRemoval bool `json:"force_worktree_removal"` // Force removal even with uncommitted changes
+	KeepUnmergedBranches bool `json:"keep_unmerged_branches"` // Skip branch deletion when the branch isn't merged into its base
+}
+
+// LoggingSettings controls the agent output log written with
+// behavior.log_agent_output
+type LoggingSettings struct {
+	TimestampLog bool `json:"timestamp_log"` // Prefix each line of the agent output log with when it arrived
 }
 
 // BehaviorSettings controls application behavior preferences
 type BehaviorSettings struct {
 	ConfirmQuitWithAgents bool `json:"confirm_quit_with_agents"` // Prompt before quitting with running agents
+	EnforceBlockers       bool `json:"enforce_blockers"`         // Refuse to start tickets whose blockers aren't done
+	AutoMoveOnComplete    bool `json:"auto_move_on_complete"`    // Move to Done and stop the agent when it reports completion
+	ConfirmBranchName     bool `json:"confirm_branch_name"`      // Preview and edit the generated branch name before creating it
+	EnforceWIPLimits      bool `json:"enforce_wip_limits"`       // Refuse moves into columns that are at their limit
+	ConfirmParallelAgents bool `json:"confirm_parallel_agents"`  // Prompt before spawning a second agent in the same project
+	EnablePRCreation      bool `json:"enable_pr_creation"`       // Allow pushing a ticket's branch and opening a PR with gh
+	MaxConcurrentAgents   int  `json:"max_concurrent_agents"`    // Cap on running agents; extra spawns are queued (0 = unlimited)
+	StatusStablePolls     int  `json:"status_stable_polls"`      // Consecutive polls a new agent status must be seen before it's shown (0 or 1 = immediately)
+	ConfirmQuit           bool `json:"confirm_quit"`             // Prompt before every quit, even with no agents running
+	LogAgentOutput        bool `json:"log_agent_output"`         // Append each ticket agent's raw terminal output to a log file
+	AgentPortBase         int  `json:"agent_port_base"`          // First port handed to agents that need one (opencode)
+	AgentPortRange        int  `json:"agent_port_range"`         // Number of ports from agent_port_base that agents may use
+	ConfirmSpawnPrompt    bool `json:"confirm_spawn_prompt"`     // Preview and edit the init prompt before spawning an agent
+	DesktopNotifications  bool `json:"desktop_notifications"`    // Show a desktop notification when an agent starts waiting or fails
+
+	// AlertSound is played when an agent finishes or starts waiting for
+	// input: "bell" rings the terminal bell, "command" runs AlertCommand,
+	// and "none" or empty plays nothing.
+	AlertSound   string `json:"alert_sound,omitempty"`
+	AlertCommand string `json:"alert_command,omitempty"`
+
+	// QuitKey quits from the board; empty leaves only ctrl+c.
+	QuitKey string `json:"quit_key"`
+	// EditorCommand opens a ticket's worktree with o, which is appended as
+	// the last argument; $EDITOR is used when empty.
+	EditorCommand string `json:"editor_command,omitempty"`
 }
 
 func defaultAgents() map[string]AgentConfig {
@@ -232,7 +353,7 @@ func defaultAgents() map[string]AgentConfig {
 		},
 		"opencode": {
 			Command:    "opencode",
-			Args:       []string{},
+			Args:       []string{"{worktree}", "--port", "{port}"},
 			Env:        map[string]string{},
 			StatusFile: ".opencode/status.json",
 			InitPrompt: defaultOpencodePrompt,
@@ -258,7 +379,32 @@ func defaultAgents() map[string]AgentConfig {
 			StatusFile: "",
 			InitPrompt: defaultCodexPrompt,
 		},
+		"qwen": {
+			Command:    "qwen",
+			Args:       []string{"--yolo"},
+			Env:        map[string]string{},
+			StatusFile: ".qwen/status.json",
+			InitPrompt: defaultQwenPrompt,
+		},
+		"cursor-agent": {
+			Command:    "cursor-agent",
+			Args:       []string{"--force"},
+			Env:        map[string]string{},
+			StatusFile: ".cursor/status.json",
+			InitPrompt: defaultCursorPrompt,
+		},
+	}
+}
+
+// DefaultAgentArgs returns the built-in args for a known agent type, or nil.
+// Used as a fallback when a user config leaves args empty for agents whose
+// integration depends on them (e.g. opencode's --port).
+func DefaultAgentArgs(agentType string) []string {
+	cfg, ok := defaultAgents()[agentType]
+	if !ok {
+		return nil
 	}
+	return cfg.Args
 }
 
 // DefaultConfig returns the default configuration
@@ -293,6 +439,10 @@ func DefaultConfig() *Config {
 		},
 		Behavior: BehaviorSettings{
 			ConfirmQuitWithAgents: true,
+			StatusStablePolls:     2,
+			AgentPortBase:         4097,
+			AgentPortRange:        100,
+			QuitKey:               "q",
 		},
 		Opencode: OpencodeSettings{
 			ServerEnabled:  true,
@@ -387,6 +537,40 @@ func (c *Config) GetEffectiveInitPrompt(agentType string) string {
 	return defaultGlobalPrompt
 }
 
+// TicketTemplateNames returns the names of the ticket description
+// templates, sorted.
+func (c *Config) TicketTemplateNames() []string {
+	return slices.Sorted(maps.Keys(c.Defaults.TicketTemplates))
+}
+
+// RenderTicketTemplate expands the named ticket description template for a
+// ticket with the given title.
+func (c *Config) RenderTicketTemplate(name, title string) (string, error) {
+	body, ok := c.Defaults.TicketTemplates[name]
+	if !ok {
+		return "", fmt.Errorf("ticket template %q not found", name)
+	}
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, struct{ Title string }{Title: title}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// GetScrollbackLines returns the scrollback buffer size for an agent's
+// panes: the agent's own setting, else ui.scrollback_lines. 0 leaves the
+// terminal default.
+func (c *Config) GetScrollbackLines(agentType string) int {
+	if agentCfg, ok := c.Agents[agentType]; ok && agentCfg.ScrollbackLines > 0 {
+		return agentCfg.ScrollbackLines
+	}
+	return c.UI.ScrollbackLines
+}
+
 func (c *Config) GetTheme() Theme {
 	return GetTheme(c.UI.Theme, c.UI.CustomColors)
 }
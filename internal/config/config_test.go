@@ -4,15 +4,16 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
 func TestDefaultConfig(t *testing.T) {
 	cfg := DefaultConfig()
 
-	knownAgents := map[string]bool{"opencode": true, "claude": true, "gemini": true, "codex": true, "aider": true}
+	knownAgents := map[string]bool{"opencode": true, "claude": true, "gemini": true, "codex": true, "aider": true, "qwen": true, "cursor-agent": true}
 	if !knownAgents[cfg.Defaults.DefaultAgent] {
-		t.Errorf("Defaults.DefaultAgent = %q; want one of opencode, claude, gemini, codex, aider", cfg.Defaults.DefaultAgent)
+		t.Errorf("Defaults.DefaultAgent = %q; want one of opencode, claude, gemini, codex, aider, qwen, cursor-agent", cfg.Defaults.DefaultAgent)
 	}
 
 	if cfg.Defaults.BranchPrefix != "task/" {
@@ -368,6 +369,30 @@ func TestMergeAgentDefaults(t *testing.T) {
 	}
 }
 
+func TestMergeAgentDefaults_NewAgents(t *testing.T) {
+	cfg := &Config{
+		Agents: map[string]AgentConfig{
+			"qwen":         {Command: "qwen"},
+			"cursor-agent": {Command: "cursor-agent"},
+		},
+	}
+
+	cfg.mergeAgentDefaults()
+
+	want := map[string]string{
+		"qwen":         ".qwen/status.json",
+		"cursor-agent": ".cursor/status.json",
+	}
+	for name, statusFile := range want {
+		if cfg.Agents[name].StatusFile != statusFile {
+			t.Errorf("%s.StatusFile = %q; want %q", name, cfg.Agents[name].StatusFile, statusFile)
+		}
+		if cfg.Agents[name].Env == nil {
+			t.Errorf("%s.Env should not be nil after merge", name)
+		}
+	}
+}
+
 func TestConfigStructure(t *testing.T) {
 	cfg := DefaultConfig()
 
@@ -484,7 +509,7 @@ func TestAgentPriority(t *testing.T) {
 		t.Errorf("AgentPriority[0] = %q; want %q", AgentPriority[0], "opencode")
 	}
 
-	expected := []string{"opencode", "claude", "gemini", "codex", "aider"}
+	expected := []string{"opencode", "claude", "gemini", "codex", "aider", "qwen", "cursor-agent"}
 	if len(AgentPriority) != len(expected) {
 		t.Errorf("AgentPriority has %d items; want %d", len(AgentPriority), len(expected))
 	}
@@ -600,3 +625,45 @@ func TestLoadWithValidation_InvalidConfig(t *testing.T) {
 		t.Error("validation result should have errors for invalid config")
 	}
 }
+
+func TestGetScrollbackLines(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.UI.ScrollbackLines = 5000
+	claude := cfg.Agents["claude"]
+	claude.ScrollbackLines = 50000
+	cfg.Agents["claude"] = claude
+
+	if got := cfg.GetScrollbackLines("claude"); got != 50000 {
+		t.Errorf("GetScrollbackLines(\"claude\") = %d; want 50000", got)
+	}
+	if got := cfg.GetScrollbackLines("opencode"); got != 5000 {
+		t.Errorf("GetScrollbackLines(\"opencode\") = %d; want the global 5000", got)
+	}
+	if got := cfg.GetScrollbackLines("unknown"); got != 5000 {
+		t.Errorf("GetScrollbackLines(\"unknown\") = %d; want the global 5000", got)
+	}
+}
+
+func TestRenderTicketTemplate(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Defaults.TicketTemplates = map[string]string{
+		"feature": "## {{.Title}}\n\nAcceptance criteria:\n- ",
+		"bug":     "Steps to reproduce:",
+	}
+
+	if got, want := cfg.TicketTemplateNames(), []string{"bug", "feature"}; !slices.Equal(got, want) {
+		t.Errorf("TicketTemplateNames() = %v; want %v", got, want)
+	}
+
+	got, err := cfg.RenderTicketTemplate("feature", "Add login")
+	if err != nil {
+		t.Fatalf("RenderTicketTemplate() error = %v", err)
+	}
+	if want := "## Add login\n\nAcceptance criteria:\n- "; got != want {
+		t.Errorf("RenderTicketTemplate() = %q; want %q", got, want)
+	}
+
+	if _, err := cfg.RenderTicketTemplate("missing", "x"); err == nil {
+		t.Error("RenderTicketTemplate() for a missing template should fail")
+	}
+}
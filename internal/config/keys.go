@@ -0,0 +1,198 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// KeyAction is a board action whose keys can be changed with Config.Keys.
+type KeyAction struct {
+	Name string
+	// Keys are the action's default keys, named as Bubble Tea reports them.
+	Keys []string
+}
+
+// KeyActions lists the remappable board actions with their default keys.
+var KeyActions = []KeyAction{
+	{"left", []string{"h", "left"}},
+	{"right", []string{"l", "right"}},
+	{"down", []string{"j", "down"}},
+	{"up", []string{"k", "up"}},
+	{"half_page_down", []string{"ctrl+d"}},
+	{"half_page_up", []string{"ctrl+u"}},
+	{"page_down", []string{"pgdown"}},
+	{"page_up", []string{"pgup"}},
+	{"first", []string{"g"}},
+	{"last", []string{"G"}},
+	{"sidebar_focus", []string{"tab"}},
+	{"sidebar_toggle", []string{"["}},
+	{"new", []string{"n"}},
+	{"edit", []string{"e"}},
+	{"attach", []string{"enter"}},
+	{"delete", []string{"d"}},
+	{"move_forward", []string{" "}},
+	{"move_backward", []string{"-", "backspace"}},
+	{"undo", []string{"u"}},
+	{"spawn", []string{"s"}},
+	{"stop", []string{"S"}},
+	{"respawn", []string{"R"}},
+	{"restart", []string{"ctrl+r"}},
+	{"spawn_all", []string{"ctrl+s"}},
+	{"worktrees", []string{"W"}},
+	{"saved_filters", []string{"F"}},
+	{"archive", []string{"A"}},
+	{"agent_type", []string{"a"}},
+	{"priority_up", []string{"+", "="}},
+	{"priority_down", []string{"_"}},
+	{"reorder_down", []string{"J", "shift+down"}},
+	{"reorder_up", []string{"K", "shift+up"}},
+	{"pause", []string{"p"}},
+	{"merge", []string{"m"}},
+	{"pull_request", []string{"P"}},
+	{"command", []string{":"}},
+	{"filter", []string{"/"}},
+	{"settings", []string{"O"}},
+	{"effective_config", []string{"C"}},
+	{"mark", []string{"v"}},
+	{"group_by_project", []string{"V"}},
+	{"copy_path", []string{"y"}},
+	{"editor", []string{"o"}},
+	{"quick_agent", []string{"c"}},
+	{"snooze", []string{"z"}},
+	{"status_detail", []string{"i"}},
+	{"dashboard", []string{"D"}},
+}
+
+// reservedKeys are handled before board actions and can't be rebound.
+var reservedKeys = map[string]bool{"ctrl+c": true, "esc": true, "?": true}
+
+// ParseKeys splits a Keys value into key names. Keys are separated by
+// commas, "space" names the space bar and "comma" the comma key. An empty
+// value yields no keys.
+func ParseKeys(value string) []string {
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+		switch key {
+		case "":
+			continue
+		case "space":
+			key = " "
+		case "comma":
+			key = ","
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// KeyLabel returns key as it is written in Keys and shown in help.
+func KeyLabel(key string) string {
+	switch key {
+	case " ":
+		return "space"
+	case ",":
+		return "comma"
+	}
+	return key
+}
+
+// ActionKeys returns the keys bound to each board action: its default
+// keys, or those set for it in Keys. Unknown action names are ignored.
+func (c *Config) ActionKeys() map[string][]string {
+	actionKeys := make(map[string][]string, len(KeyActions))
+	for _, a := range KeyActions {
+		if value, ok := c.Keys[a.Name]; ok {
+			actionKeys[a.Name] = ParseKeys(value)
+		} else {
+			actionKeys[a.Name] = a.Keys
+		}
+	}
+	return actionKeys
+}
+
+// KeyBindings returns the board action bound to each key. Keys set in
+// Keys take precedence over default keys; otherwise, when a key is bound to
+// several actions, the one listed first in KeyActions keeps it. Validate
+// reports such conflicts.
+func (c *Config) KeyBindings() map[string]string {
+	actionKeys := c.ActionKeys()
+	bindings := make(map[string]string)
+	for _, overridden := range []bool{true, false} {
+		for _, a := range KeyActions {
+			if _, ok := c.Keys[a.Name]; ok != overridden {
+				continue
+			}
+			for _, key := range actionKeys[a.Name] {
+				if _, taken := bindings[key]; !taken && !reservedKeys[key] {
+					bindings[key] = a.Name
+				}
+			}
+		}
+	}
+	return bindings
+}
+
+// validateKeys reports unknown actions in Keys, reserved keys in Keys or
+// as the quit key, and keys set for more than one action. A key set in Keys that takes over another
+// action's default key, or is shadowed by the quit key, is only a warning.
+func (c *Config) validateKeys(r *ValidationResult) {
+	known := make(map[string]bool, len(KeyActions))
+	for _, a := range KeyActions {
+		known[a.Name] = true
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Keys)) {
+		if !known[name] {
+			r.AddError("keys", name, "is not a known action", c.Keys[name])
+		}
+	}
+
+	owner := make(map[string]string)
+	for _, a := range KeyActions {
+		value, ok := c.Keys[a.Name]
+		if !ok {
+			continue
+		}
+		for _, key := range ParseKeys(value) {
+			switch {
+			case reservedKeys[key]:
+				r.AddError("keys", a.Name,
+					fmt.Sprintf("key %q is reserved and can't be rebound", KeyLabel(key)),
+					value)
+			case owner[key] != "":
+				r.AddError("keys", a.Name,
+					fmt.Sprintf("key %q is also set for %s", KeyLabel(key), owner[key]),
+					value)
+			default:
+				owner[key] = a.Name
+			}
+		}
+	}
+	for _, a := range KeyActions {
+		if _, ok := c.Keys[a.Name]; ok {
+			continue
+		}
+		for _, key := range a.Keys {
+			if other := owner[key]; other != "" {
+				r.AddWarning("keys", other,
+					fmt.Sprintf("key %q takes over %s's default key", KeyLabel(key), a.Name),
+					c.Keys[other])
+				continue
+			}
+			owner[key] = a.Name
+		}
+	}
+
+	quit := c.Behavior.QuitKey
+	if reservedKeys[quit] {
+		r.AddError("behavior", "quit_key",
+			fmt.Sprintf("key %q is reserved and can't quit", KeyLabel(quit)),
+			quit)
+	} else if quit != "" && owner[quit] != "" {
+		r.AddWarning("behavior", "quit_key",
+			fmt.Sprintf("shadows the %s action's key", owner[quit]),
+			quit)
+	}
+}
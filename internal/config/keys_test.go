@@ -0,0 +1,123 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"c", []string{"c"}},
+		{"n, down", []string{"n", "down"}},
+		{"space", []string{" "}},
+		{"comma,x", []string{",", "x"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseKeys(tt.value); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseKeys(%q) = %q; want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestKeyBindings_Defaults(t *testing.T) {
+	cfg := DefaultConfig()
+	bindings := cfg.KeyBindings()
+
+	for key, want := range map[string]string{"n": "new", "j": "down", "down": "down", " ": "move_forward", "c": "quick_agent"} {
+		if got := bindings[key]; got != want {
+			t.Errorf("KeyBindings()[%q] = %q; want %q", key, got, want)
+		}
+	}
+	result := cfg.Validate()
+	for _, issue := range append(result.Errors, result.Warnings...) {
+		if issue.Section == "keys" || issue.Field == "quit_key" {
+			t.Errorf("default keys should validate cleanly, got %v", issue)
+		}
+	}
+}
+
+func TestKeyBindings_Override(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Keys = map[string]string{"new": "c", "down": "t, down"}
+	bindings := cfg.KeyBindings()
+
+	if bindings["c"] != "new" {
+		t.Errorf("c = %q; want new to take over quick_agent's key", bindings["c"])
+	}
+	if _, ok := bindings["n"]; ok {
+		t.Error("n should no longer be bound")
+	}
+	if bindings["t"] != "down" || bindings["down"] != "down" {
+		t.Errorf("t, down = %q, %q; want down", bindings["t"], bindings["down"])
+	}
+	if _, ok := bindings["j"]; ok {
+		t.Error("j should no longer be bound")
+	}
+
+	result := cfg.Validate()
+	if result.HasErrors() {
+		t.Errorf("unexpected errors: %v", result.Errors)
+	}
+	found := false
+	for _, w := range result.Warnings {
+		if w.Section == "keys" && w.Field == "new" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a warning that new takes over quick_agent's key")
+	}
+}
+
+func TestValidate_KeyConflicts(t *testing.T) {
+	tests := []struct {
+		name  string
+		keys  map[string]string
+		field string
+	}{
+		{"unknown action", map[string]string{"fly": "f"}, "fly"},
+		{"reserved key", map[string]string{"new": "esc"}, "new"},
+		{"same key twice", map[string]string{"new": "x", "edit": "x"}, "edit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Keys = tt.keys
+
+			result := cfg.Validate()
+
+			found := false
+			for _, e := range result.Errors {
+				if e.Section == "keys" && e.Field == tt.field {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected error for keys.%s, got %v", tt.field, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidate_ReservedQuitKey(t *testing.T) {
+	for _, key := range []string{"esc", "?", "ctrl+c"} {
+		cfg := DefaultConfig()
+		cfg.Behavior.QuitKey = key
+
+		result := cfg.Validate()
+
+		found := false
+		for _, e := range result.Errors {
+			if e.Section == "behavior" && e.Field == "quit_key" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("quit_key %q: expected an error, got %v", key, result.Errors)
+		}
+	}
+}
@@ -3,8 +3,11 @@ package config
 import (
 	"fmt"
 	"os/exec"
+	"regexp"
 	"strings"
 	"text/template"
+
+	"github.com/techdufus/openkanban/internal/notify"
 )
 
 // ValidationError represents a single config validation issue
@@ -94,6 +97,8 @@ func (c *Config) Validate() *ValidationResult {
 	c.validateAgents(result)
 	c.validateUI(result)
 	c.validateOpencode(result)
+	c.validateBehavior(result)
+	c.validateKeys(result)
 	return result
 }
 
@@ -126,9 +131,10 @@ func (c *Config) validateDefaults(r *ValidationResult) {
 	// BranchTemplate should contain placeholders (warning only)
 	if c.Defaults.BranchTemplate != "" {
 		if !strings.Contains(c.Defaults.BranchTemplate, "{slug}") &&
-			!strings.Contains(c.Defaults.BranchTemplate, "{prefix}") {
+			!strings.Contains(c.Defaults.BranchTemplate, "{prefix}") &&
+			!strings.Contains(c.Defaults.BranchTemplate, "{id}") {
 			r.AddWarning("defaults", "branch_template",
-				"should contain {slug} or {prefix} placeholder",
+				"should contain {slug}, {prefix} or {id} placeholder",
 				c.Defaults.BranchTemplate)
 		}
 	}
@@ -141,6 +147,14 @@ func (c *Config) validateDefaults(r *ValidationResult) {
 				nil)
 		}
 	}
+
+	for _, name := range c.TicketTemplateNames() {
+		if err := validateTemplate(c.Defaults.TicketTemplates[name]); err != nil {
+			r.AddError("defaults", "ticket_templates."+name,
+				fmt.Sprintf("invalid Go template syntax: %v", err),
+				nil)
+		}
+	}
 }
 
 func (c *Config) validateAgents(r *ValidationResult) {
@@ -164,6 +178,49 @@ func (c *Config) validateAgents(r *ValidationResult) {
 					nil)
 			}
 		}
+
+		if agent.Color != "" && !isValidColor(agent.Color) {
+			r.AddWarning(section, "color",
+				"should be a hex color (#rgb or #rrggbb) or an ANSI color number (0-255)",
+				agent.Color)
+		}
+
+		if agent.PollIntervalSeconds < 0 {
+			r.AddError(section, "poll_interval_seconds",
+				"must be zero (use opencode.poll_interval) or a positive number",
+				agent.PollIntervalSeconds)
+		}
+
+		if agent.ScrollbackLines < 0 {
+			r.AddError(section, "scrollback_lines",
+				"must be zero (use ui.scrollback_lines) or a positive number",
+				agent.ScrollbackLines)
+		}
+
+		if p := agent.StatusPatterns; p != nil {
+			validatePatterns(r, section, "status_patterns.working", p.Working)
+			validatePatterns(r, section, "status_patterns.waiting", p.Waiting)
+			validatePatterns(r, section, "status_patterns.error", p.Error)
+		}
+	}
+}
+
+var colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6}|[0-9]{1,2}|1[0-9]{2}|2[0-4][0-9]|25[0-5])$`)
+
+// isValidColor reports whether color is a hex or ANSI 256 color lipgloss
+// understands
+func isValidColor(color string) bool {
+	return colorPattern.MatchString(color)
+}
+
+// validatePatterns reports each pattern that is not a valid regular expression
+func validatePatterns(r *ValidationResult, section, field string, patterns []string) {
+	for i, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			r.AddError(section, fmt.Sprintf("%s[%d]", field, i),
+				fmt.Sprintf("invalid regular expression: %v", err),
+				pattern)
+		}
 	}
 }
 
@@ -193,6 +250,60 @@ func (c *Config) validateUI(r *ValidationResult) {
 			"must be a positive number",
 			c.UI.RefreshInterval)
 	}
+
+	if c.UI.MaxVisibleColumns < 0 {
+		r.AddError("ui", "max_visible_columns",
+			"must be 0 (fit to width) or a positive number",
+			c.UI.MaxVisibleColumns)
+	}
+}
+
+// validateBehavior validates the behavior section
+func (c *Config) validateBehavior(r *ValidationResult) {
+	if c.Behavior.MaxConcurrentAgents < 0 {
+		r.AddError("behavior", "max_concurrent_agents",
+			"must be zero (unlimited) or a positive number",
+			c.Behavior.MaxConcurrentAgents)
+	}
+	if c.Behavior.StatusStablePolls < 0 {
+		r.AddError("behavior", "status_stable_polls",
+			"must be zero or a positive number",
+			c.Behavior.StatusStablePolls)
+	}
+	if c.Behavior.AgentPortBase < 1 || c.Behavior.AgentPortBase > 65535 {
+		r.AddError("behavior", "agent_port_base",
+			"must be between 1 and 65535",
+			c.Behavior.AgentPortBase)
+	}
+	if c.Behavior.AgentPortRange < 1 {
+		r.AddError("behavior", "agent_port_range",
+			"must be a positive number",
+			c.Behavior.AgentPortRange)
+	} else if c.Behavior.AgentPortBase+c.Behavior.AgentPortRange-1 > 65535 {
+		r.AddError("behavior", "agent_port_range",
+			fmt.Sprintf("runs past port 65535 from agent_port_base %d", c.Behavior.AgentPortBase),
+			c.Behavior.AgentPortRange)
+	}
+	validSound := map[string]bool{"": true, "none": true, "bell": true, "command": true}
+	if !validSound[c.Behavior.AlertSound] {
+		r.AddError("behavior", "alert_sound",
+			fmt.Sprintf("must be one of: none, bell, command (got %q)", c.Behavior.AlertSound),
+			c.Behavior.AlertSound)
+	} else if c.Behavior.AlertSound == "command" {
+		if fields := strings.Fields(c.Behavior.AlertCommand); len(fields) == 0 {
+			r.AddError("behavior", "alert_command",
+				"is required when alert_sound is \"command\"", nil)
+		} else if _, err := exec.LookPath(fields[0]); err != nil {
+			r.AddWarning("behavior", "alert_command",
+				fmt.Sprintf("executable %q not found in PATH", fields[0]),
+				c.Behavior.AlertCommand)
+		}
+	}
+	if c.Behavior.DesktopNotifications && !notify.Available() {
+		r.AddWarning("behavior", "desktop_notifications",
+			notify.ErrUnavailable.Error(),
+			c.Behavior.DesktopNotifications)
+	}
 }
 
 // validateOpencode validates the opencode server settings
@@ -201,6 +201,61 @@ func TestValidate_InvalidTemplatePrompt(t *testing.T) {
 	}
 }
 
+func TestValidate_InvalidStatusPattern(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents["custom"] = AgentConfig{
+		Command: "echo",
+		StatusPatterns: &StatusPatterns{
+			Working: []string{`(?i)arbeite`},
+			Waiting: []string{`ok`, `[unclosed`},
+		},
+	}
+
+	result := cfg.Validate()
+
+	var fields []string
+	for _, e := range result.Errors {
+		if e.Section == "agents.custom" {
+			fields = append(fields, e.Field)
+		}
+	}
+	if len(fields) != 1 || fields[0] != "status_patterns.waiting[1]" {
+		t.Errorf("expected one error for status_patterns.waiting[1], got %v", fields)
+	}
+}
+
+func TestValidate_AgentColor(t *testing.T) {
+	tests := []struct {
+		color string
+		valid bool
+	}{
+		{"#f5a97f", true},
+		{"#FFF", true},
+		{"212", true},
+		{"0", true},
+		{"256", false},
+		{"orange", false},
+		{"#12345", false},
+	}
+
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Agents["custom"] = AgentConfig{Command: "echo", Color: tt.color}
+
+		result := cfg.Validate()
+
+		found := false
+		for _, w := range result.Warnings {
+			if w.Section == "agents.custom" && w.Field == "color" {
+				found = true
+			}
+		}
+		if found == tt.valid {
+			t.Errorf("color %q: got warning=%v, want valid=%v", tt.color, found, tt.valid)
+		}
+	}
+}
+
 func TestValidate_InvalidDefaultsInitPrompt(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Defaults.InitPrompt = "{{.Broken"
@@ -339,6 +394,84 @@ func TestValidate_NegativePollInterval(t *testing.T) {
 	}
 }
 
+func TestValidate_NegativeMaxVisibleColumns(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.UI.MaxVisibleColumns = -1
+
+	result := cfg.Validate()
+
+	found := false
+	for _, e := range result.Errors {
+		if e.Section == "ui" && e.Field == "max_visible_columns" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected error for ui.max_visible_columns")
+	}
+}
+
+func TestValidate_NegativeMaxConcurrentAgents(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Behavior.MaxConcurrentAgents = -1
+
+	result := cfg.Validate()
+
+	found := false
+	for _, e := range result.Errors {
+		if e.Section == "behavior" && e.Field == "max_concurrent_agents" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected error for behavior.max_concurrent_agents")
+	}
+
+	cfg.Behavior.MaxConcurrentAgents = 3
+	if cfg.Validate().HasErrors() {
+		t.Error("expected positive max_concurrent_agents to be valid")
+	}
+}
+
+func TestValidate_AgentPortRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  int
+		size  int
+		field string
+	}{
+		{"zero base", 0, 100, "agent_port_base"},
+		{"base too high", 70000, 1, "agent_port_base"},
+		{"zero range", 4097, 0, "agent_port_range"},
+		{"range past max port", 65500, 100, "agent_port_range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Behavior.AgentPortBase = tt.base
+			cfg.Behavior.AgentPortRange = tt.size
+
+			found := false
+			for _, e := range cfg.Validate().Errors {
+				if e.Section == "behavior" && e.Field == tt.field {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected error for behavior.%s", tt.field)
+			}
+		})
+	}
+
+	cfg := DefaultConfig()
+	cfg.Behavior.AgentPortBase = 65000
+	cfg.Behavior.AgentPortRange = 536
+	if cfg.Validate().HasErrors() {
+		t.Error("expected a range ending at 65535 to be valid")
+	}
+}
+
 func TestValidationResult_FormatErrors(t *testing.T) {
 	r := &ValidationResult{}
 	r.AddError("defaults", "branch_naming", "must be valid", "invalid")
@@ -471,3 +604,119 @@ func TestValidate_ValidTemplatePrompt(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate_NegativeAgentPollInterval(t *testing.T) {
+	cfg := DefaultConfig()
+	claude := cfg.Agents["claude"]
+	claude.PollIntervalSeconds = -1
+	cfg.Agents["claude"] = claude
+
+	result := cfg.Validate()
+
+	found := false
+	for _, e := range result.Errors {
+		if e.Section == "agents.claude" && e.Field == "poll_interval_seconds" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected error for agents.claude.poll_interval_seconds")
+	}
+}
+
+func TestValidate_NegativeAgentScrollback(t *testing.T) {
+	cfg := DefaultConfig()
+	claude := cfg.Agents["claude"]
+	claude.ScrollbackLines = -1
+	cfg.Agents["claude"] = claude
+
+	result := cfg.Validate()
+
+	found := false
+	for _, e := range result.Errors {
+		if e.Section == "agents.claude" && e.Field == "scrollback_lines" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected error for agents.claude.scrollback_lines")
+	}
+}
+
+func TestValidate_InvalidTicketTemplate(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Defaults.TicketTemplates = map[string]string{
+		"good": "{{.Title}}",
+		"bad":  "{{.Title",
+	}
+
+	result := cfg.Validate()
+
+	var fields []string
+	for _, e := range result.Errors {
+		if e.Section == "defaults" {
+			fields = append(fields, e.Field)
+		}
+	}
+	if len(fields) != 1 || fields[0] != "ticket_templates.bad" {
+		t.Errorf("errors for defaults = %v; want only ticket_templates.bad", fields)
+	}
+}
+
+func TestValidate_DesktopNotificationsWithoutNotifier(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	cfg := DefaultConfig()
+	cfg.Behavior.DesktopNotifications = true
+
+	result := cfg.Validate()
+
+	found := false
+	for _, w := range result.Warnings {
+		if w.Section == "behavior" && w.Field == "desktop_notifications" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected warning for behavior.desktop_notifications")
+	}
+}
+
+func TestValidate_AlertSound(t *testing.T) {
+	tests := []struct {
+		name      string
+		sound     string
+		command   string
+		wantField string
+	}{
+		{name: "unknown sound", sound: "chime", wantField: "alert_sound"},
+		{name: "command without alert_command", sound: "command", wantField: "alert_command"},
+		{name: "bell", sound: "bell"},
+		{name: "none", sound: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Behavior.AlertSound = tt.sound
+			cfg.Behavior.AlertCommand = tt.command
+
+			result := cfg.Validate()
+
+			var fields []string
+			for _, e := range result.Errors {
+				if e.Section == "behavior" && strings.HasPrefix(e.Field, "alert_") {
+					fields = append(fields, e.Field)
+				}
+			}
+			if tt.wantField == "" {
+				if len(fields) > 0 {
+					t.Errorf("unexpected errors for %v", fields)
+				}
+				return
+			}
+			if len(fields) != 1 || fields[0] != tt.wantField {
+				t.Errorf("errors for %v, want behavior.%s", fields, tt.wantField)
+			}
+		})
+	}
+}
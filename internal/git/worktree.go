@@ -2,10 +2,12 @@ package git
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/techdufus/openkanban/internal/project"
 )
@@ -13,6 +15,10 @@ import (
 type WorktreeManager struct {
 	repoPath string
 	baseDir  string
+
+	// defaultBranch caches GetDefaultBranch until RefreshDefaultBranch.
+	mu            sync.Mutex
+	defaultBranch string
 }
 
 func NewWorktreeManager(p *project.Project) *WorktreeManager {
@@ -71,6 +77,32 @@ func (m *WorktreeManager) isValidWorktree(path string) bool {
 	return !info.IsDir()
 }
 
+// ValidateExternalWorktree checks that path is an existing git worktree
+// belonging to this manager's repository, so it can be attached to a ticket
+// in place of one OpenKanban would create.
+func (m *WorktreeManager) ValidateExternalWorktree(path string) error {
+	if !m.isValidWorktree(path) {
+		return fmt.Errorf("%s is not a git worktree", path)
+	}
+	if filepath.Clean(ResolveMainRepo(path)) != filepath.Clean(m.repoPath) {
+		return fmt.Errorf("%s is not a worktree of %s", path, m.repoPath)
+	}
+	return nil
+}
+
+// CurrentBranch returns the branch checked out in worktreePath.
+func CurrentBranch(worktreePath string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = worktreePath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read current branch: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
 func (m *WorktreeManager) RemoveWorktree(worktreePath string) error {
 	cmd := exec.Command("git", "worktree", "remove", worktreePath, "--force")
 	cmd.Dir = m.repoPath
@@ -90,6 +122,36 @@ func (m *WorktreeManager) RemoveWorktree(worktreePath string) error {
 	return nil
 }
 
+// WorktreeSize returns the total size in bytes of the files under
+// worktreePath. The .git entry is skipped, so objects shared with the main
+// repository are not counted. Symlinks are counted as links, not followed.
+func (m *WorktreeManager) WorktreeSize(worktreePath string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(worktreePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Name() == ".git" && path != worktreePath {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure worktree: %w", err)
+	}
+	return size, nil
+}
+
 func (m *WorktreeManager) ListWorktrees() ([]Worktree, error) {
 	cmd := exec.Command("git", "worktree", "list", "--porcelain")
 	cmd.Dir = m.repoPath
@@ -102,6 +164,31 @@ func (m *WorktreeManager) ListWorktrees() ([]Worktree, error) {
 	return parseWorktreeList(string(output)), nil
 }
 
+// PruneWorktrees runs git worktree prune, dropping the administrative
+// entries of worktrees whose directories no longer exist. It returns git's
+// description of each entry pruned, or that would be with dryRun.
+func (m *WorktreeManager) PruneWorktrees(dryRun bool) ([]string, error) {
+	args := []string{"worktree", "prune", "--verbose"}
+	if dryRun {
+		args = append(args, "--dry-run")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = m.repoPath
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune worktrees: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+
+	var pruned []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			pruned = append(pruned, line)
+		}
+	}
+	return pruned, nil
+}
+
 type Worktree struct {
 	Path   string
 	HEAD   string
@@ -132,26 +219,45 @@ func parseWorktreeList(output string) []Worktree {
 	return worktrees
 }
 
+// GetDefaultBranch returns the repository's default branch: origin's HEAD,
+// else main or master, whichever exists. The result is cached; call
+// RefreshDefaultBranch to detect it again.
 func (m *WorktreeManager) GetDefaultBranch() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.defaultBranch == "" {
+		m.defaultBranch = m.detectDefaultBranch()
+	}
+	return m.defaultBranch, nil
+}
+
+// RefreshDefaultBranch drops the cached default branch.
+func (m *WorktreeManager) RefreshDefaultBranch() {
+	m.mu.Lock()
+	m.defaultBranch = ""
+	m.mu.Unlock()
+}
+
+func (m *WorktreeManager) detectDefaultBranch() string {
 	cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
 	cmd.Dir = m.repoPath
 
 	output, err := cmd.Output()
 	if err == nil {
 		branch := strings.TrimSpace(string(output))
-		branch = strings.TrimPrefix(branch, "refs/remotes/origin/")
-		return branch, nil
+		return strings.TrimPrefix(branch, "refs/remotes/origin/")
 	}
 
 	for _, branch := range []string{"main", "master"} {
 		cmd := exec.Command("git", "rev-parse", "--verify", branch)
 		cmd.Dir = m.repoPath
 		if err := cmd.Run(); err == nil {
-			return branch, nil
+			return branch
 		}
 	}
 
-	return "main", nil
+	return "main"
 }
 
 func (m *WorktreeManager) DeleteBranch(branchName string) error {
@@ -171,6 +277,115 @@ func (m *WorktreeManager) BranchExists(branchName string) bool {
 	return cmd.Run() == nil
 }
 
+// ListBranches returns the repository's local branch names, sorted.
+func (m *WorktreeManager) ListBranches() ([]string, error) {
+	return m.listRefs("refs/heads")
+}
+
+// ListRemoteBranches returns the repository's remote-tracking branch names,
+// such as "origin/main", sorted. Remotes' HEAD refs are left out.
+func (m *WorktreeManager) ListRemoteBranches() ([]string, error) {
+	refs, err := m.listRefs("refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+	var branches []string
+	for _, ref := range refs {
+		if strings.Contains(ref, "/") && !strings.HasSuffix(ref, "/HEAD") {
+			branches = append(branches, ref)
+		}
+	}
+	return branches, nil
+}
+
+func (m *WorktreeManager) listRefs(prefix string) ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--sort=refname", "--format=%(refname:short)", prefix)
+	cmd.Dir = m.repoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	var refs []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if ref := strings.TrimSpace(line); ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+// AttachWorktree returns a worktree with the existing branch checked out,
+// reusing one that already has it and otherwise adding one under the
+// manager's base directory.
+func (m *WorktreeManager) AttachWorktree(branchName string) (string, error) {
+	if !m.BranchExists(branchName) {
+		return "", fmt.Errorf("branch %s does not exist", branchName)
+	}
+
+	worktrees, err := m.ListWorktrees()
+	if err != nil {
+		return "", err
+	}
+	for _, wt := range worktrees {
+		if wt.Branch != branchName {
+			continue
+		}
+		if filepath.Clean(wt.Path) == filepath.Clean(m.repoPath) {
+			return "", fmt.Errorf("branch %s is checked out in the main repository", branchName)
+		}
+		return wt.Path, nil
+	}
+
+	if err := os.MkdirAll(m.baseDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create worktree base directory: %w", err)
+	}
+	worktreePath := filepath.Join(m.baseDir, sanitizeBranchName(branchName))
+	if _, err := os.Stat(worktreePath); err == nil {
+		return "", fmt.Errorf("%s already exists", worktreePath)
+	}
+
+	cmd := exec.Command("git", "worktree", "add", worktreePath, branchName)
+	cmd.Dir = m.repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to create worktree: %s: %w", string(output), err)
+	}
+	return worktreePath, nil
+}
+
+// IsBranchMerged reports whether every commit on branch is reachable from
+// base, per git branch --merged.
+func (m *WorktreeManager) IsBranchMerged(branch, base string) (bool, error) {
+	cmd := exec.Command("git", "branch", "--merged", base, "--format=%(refname:short)")
+	cmd.Dir = m.repoPath
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("failed to list merged branches: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) == branch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ValidateBranchName reports whether name is acceptable to git as a new
+// branch name.
+func ValidateBranchName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("branch name is empty")
+	}
+	cmd := exec.Command("git", "check-ref-format", "--branch", name)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("invalid branch name %q: %s", name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 func (m *WorktreeManager) CreateBranch(branchName, baseBranch string) error {
 	cmd := exec.Command("git", "branch", branchName, baseBranch)
 	cmd.Dir = m.repoPath
@@ -202,6 +417,202 @@ func (m *WorktreeManager) SetupBranch(branchName, baseBranch string) error {
 	return m.CheckoutBranch(branchName)
 }
 
+// CommitsAhead returns how many commits branch has that base does not.
+func (m *WorktreeManager) CommitsAhead(branch, base string) (int, error) {
+	cmd := exec.Command("git", "rev-list", "--count", base+".."+branch)
+	cmd.Dir = m.repoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits: %w", err)
+	}
+
+	var count int
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(output)), "%d", &count); err != nil {
+		return 0, fmt.Errorf("failed to count commits: %w", err)
+	}
+	return count, nil
+}
+
+// AheadBehind reports how many commits the worktree's HEAD has that
+// baseBranch lacks, and how many baseBranch has gained since they diverged.
+func (m *WorktreeManager) AheadBehind(worktreePath, baseBranch string) (ahead, behind int, err error) {
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", "HEAD..."+baseBranch)
+	cmd.Dir = worktreePath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare with %s: %w", baseBranch, err)
+	}
+
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(output)), "%d %d", &ahead, &behind); err != nil {
+		return 0, 0, fmt.Errorf("failed to compare with %s: %w", baseBranch, err)
+	}
+	return ahead, behind, nil
+}
+
+// MergeConflictError is returned by MergeBranch when the merge stopped on
+// conflicts. The merge has already been aborted.
+type MergeConflictError struct {
+	Files []string
+}
+
+func (e *MergeConflictError) Error() string {
+	return "merge conflict in " + strings.Join(e.Files, ", ")
+}
+
+// MergeBranch merges branch into base in the main repository with a merge
+// commit. The main repository must already have base checked out. On
+// conflicts the merge is aborted and a *MergeConflictError lists the
+// conflicting files.
+func (m *WorktreeManager) MergeBranch(branch, base string) error {
+	current, err := CurrentBranch(m.repoPath)
+	if err != nil {
+		return err
+	}
+	if current != base {
+		return fmt.Errorf("main repo is on %s, not %s", current, base)
+	}
+
+	cmd := exec.Command("git", "merge", "--no-ff", "--no-edit", branch)
+	cmd.Dir = m.repoPath
+	output, mergeErr := cmd.CombinedOutput()
+	if mergeErr == nil {
+		return nil
+	}
+
+	cmd = exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+	cmd.Dir = m.repoPath
+	conflicts, _ := cmd.Output()
+	files := strings.Fields(string(conflicts))
+	if len(files) == 0 {
+		return fmt.Errorf("failed to merge %s: %s: %w", branch, strings.TrimSpace(string(output)), mergeErr)
+	}
+
+	cmd = exec.Command("git", "merge", "--abort")
+	cmd.Dir = m.repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to abort merge: %s: %w", string(output), err)
+	}
+	return &MergeConflictError{Files: files}
+}
+
+// RebaseBranch rebases the branch checked out in worktreePath onto base. On
+// conflicts the rebase is aborted and a *MergeConflictError lists the
+// conflicting files.
+func (m *WorktreeManager) RebaseBranch(worktreePath, base string) error {
+	cmd := exec.Command("git", "rebase", base)
+	cmd.Dir = worktreePath
+	output, rebaseErr := cmd.CombinedOutput()
+	if rebaseErr == nil {
+		return nil
+	}
+
+	cmd = exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+	cmd.Dir = worktreePath
+	conflicts, _ := cmd.Output()
+	files := strings.Fields(string(conflicts))
+
+	abort := exec.Command("git", "rebase", "--abort")
+	abort.Dir = worktreePath
+	abortOutput, abortErr := abort.CombinedOutput()
+	if len(files) == 0 {
+		return fmt.Errorf("failed to rebase onto %s: %s: %w", base, strings.TrimSpace(string(output)), rebaseErr)
+	}
+	if abortErr != nil {
+		return fmt.Errorf("failed to abort rebase: %s: %w", string(abortOutput), abortErr)
+	}
+	return &MergeConflictError{Files: files}
+}
+
+// CommitAll stages every change in worktreePath, untracked files included,
+// and commits them with message.
+func (m *WorktreeManager) CommitAll(worktreePath, message string) error {
+	cmd := exec.Command("git", "add", "-A")
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage changes: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+
+	cmd = exec.Command("git", "commit", "-q", "-m", message)
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to commit: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// PushBranch pushes branch to origin and sets it as the upstream.
+func (m *WorktreeManager) PushBranch(branch string) error {
+	cmd := exec.Command("git", "push", "--set-upstream", "origin", branch)
+	cmd.Dir = m.repoPath
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to push %s: %s: %w", branch, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// GHAvailable reports whether the GitHub CLI is on PATH.
+func GHAvailable() bool {
+	_, err := exec.LookPath("gh")
+	return err == nil
+}
+
+// CreatePullRequest opens a pull request for head with gh and returns its
+// URL. An empty base lets gh use the repository's default branch.
+func (m *WorktreeManager) CreatePullRequest(title, body, head, base string) (string, error) {
+	args := []string{"pr", "create", "--title", title, "--body", body, "--head", head}
+	if base != "" {
+		args = append(args, "--base", base)
+	}
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = m.repoPath
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to create pull request: %s: %w", strings.TrimSpace(stderr.String()), err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// RenameWorktreeBranch renames the branch checked out in worktreePath and
+// moves the worktree to where CreateWorktree would put the new branch. The
+// branch rename is undone if the move fails. It returns the new path.
+func (m *WorktreeManager) RenameWorktreeBranch(worktreePath, oldBranch, newBranch string) (string, error) {
+	newPath := filepath.Join(m.baseDir, sanitizeBranchName(newBranch))
+	if newPath != worktreePath {
+		if _, err := os.Stat(newPath); err == nil {
+			return "", fmt.Errorf("%s already exists", newPath)
+		}
+	}
+
+	cmd := exec.Command("git", "branch", "-m", oldBranch, newBranch)
+	cmd.Dir = m.repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to rename branch: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+
+	if newPath == worktreePath {
+		return worktreePath, nil
+	}
+
+	cmd = exec.Command("git", "worktree", "move", worktreePath, newPath)
+	cmd.Dir = m.repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		undo := exec.Command("git", "branch", "-m", newBranch, oldBranch)
+		undo.Dir = m.repoPath
+		undo.Run()
+		return "", fmt.Errorf("failed to move worktree: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+
+	return newPath, nil
+}
+
 func (m *WorktreeManager) HasUncommittedChanges(worktreePath string) (bool, error) {
 	cmd := exec.Command("git", "status", "--porcelain")
 	cmd.Dir = worktreePath
@@ -214,6 +625,54 @@ func (m *WorktreeManager) HasUncommittedChanges(worktreePath string) (bool, erro
 	return len(strings.TrimSpace(string(output))) > 0, nil
 }
 
+// CountUncommittedFiles returns how many files in the worktree are modified,
+// staged or untracked.
+func (m *WorktreeManager) CountUncommittedFiles(worktreePath string) (int, error) {
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = worktreePath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check git status: %w", err)
+	}
+
+	count := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Diff returns the uncommitted changes in worktreePath relative to HEAD,
+// covering staged and unstaged edits and untracked files that aren't
+// ignored. Untracked files are marked with "git add -N" in a temporary
+// index, so the worktree's own index is left alone.
+func Diff(worktreePath string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "openkanban-diff-")
+	if err != nil {
+		return "", fmt.Errorf("failed to get git diff: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	env := append(os.Environ(), "GIT_INDEX_FILE="+filepath.Join(tmpDir, "index"))
+
+	var output []byte
+	for _, args := range [][]string{
+		{"read-tree", "HEAD"},
+		{"add", "--intent-to-add", "--", "."},
+		{"diff", "--no-color", "--no-ext-diff", "HEAD"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = worktreePath
+		cmd.Env = env
+		if output, err = cmd.Output(); err != nil {
+			return "", fmt.Errorf("failed to get git diff: %w", err)
+		}
+	}
+	return string(output), nil
+}
+
 func sanitizeBranchName(name string) string {
 	name = strings.TrimPrefix(name, "refs/heads/")
 	name = strings.TrimPrefix(name, "agent/")
@@ -1,8 +1,12 @@
 package git
 
 import (
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -227,6 +231,47 @@ func TestResolveMainRepo(t *testing.T) {
 	})
 }
 
+func TestValidateExternalWorktree(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoPath := filepath.Join(tmpDir, "main-repo")
+	mgr := NewWorktreeManagerFromPaths(repoPath, repoPath+"-worktrees")
+
+	makeWorktree := func(name, gitdir string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, ".git"), []byte("gitdir: "+gitdir), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("worktree of the project repo", func(t *testing.T) {
+		path := makeWorktree("ours", repoPath+"/.git/worktrees/ours")
+		if err := mgr.ValidateExternalWorktree(path); err != nil {
+			t.Errorf("ValidateExternalWorktree(%q) = %v; want nil", path, err)
+		}
+	})
+
+	t.Run("worktree of another repo", func(t *testing.T) {
+		path := makeWorktree("theirs", "/elsewhere/repo/.git/worktrees/theirs")
+		if err := mgr.ValidateExternalWorktree(path); err == nil {
+			t.Errorf("ValidateExternalWorktree(%q) = nil; want error", path)
+		}
+	})
+
+	t.Run("not a worktree", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "plain")
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := mgr.ValidateExternalWorktree(path); err == nil {
+			t.Errorf("ValidateExternalWorktree(%q) = nil; want error", path)
+		}
+	})
+}
+
 func TestNewWorktreeManagerFromPaths(t *testing.T) {
 	mgr := NewWorktreeManagerFromPaths("/repo/path", "/worktrees/path")
 
@@ -237,3 +282,443 @@ func TestNewWorktreeManagerFromPaths(t *testing.T) {
 		t.Errorf("baseDir = %q; want %q", mgr.baseDir, "/worktrees/path")
 	}
 }
+
+func TestValidateBranchName(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{"agent/fix-the-thing", false},
+		{"feature-123", false},
+		{"", true},
+		{"fix the thing", true},
+		{"fix..thing", true},
+		{"fix-thing/", true},
+		{"-leading-dash", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateBranchName(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateBranchName(%q) error = %v; wantErr %v", tt.input, err, tt.wantErr)
+		}
+	}
+}
+
+// newTestRepo creates a git repository on branch main with one empty commit
+// in a "repo" directory under a new temporary directory, returning both. It
+// skips the test when git isn't installed.
+func newTestRepo(t *testing.T) (tmpDir, repo string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	tmpDir = t.TempDir()
+	repo = filepath.Join(tmpDir, "repo")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "init", "-q", "-b", "main")
+	runGit(t, repo, "config", "user.name", "test")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "initial")
+	return tmpDir, repo
+}
+
+// runGit runs git with args in dir, failing the test if it fails, and
+// returns its output.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %s: %v", args, out, err)
+	}
+	return string(out)
+}
+
+func TestRenameWorktreeBranch(t *testing.T) {
+	tmpDir, repo := newTestRepo(t)
+
+	mgr := NewWorktreeManagerFromPaths(repo, filepath.Join(tmpDir, "worktrees"))
+	oldPath, err := mgr.CreateWorktree("task/fix-the-thing", "main")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+
+	if ahead, err := mgr.CommitsAhead("task/fix-the-thing", "main"); err != nil || ahead != 0 {
+		t.Fatalf("CommitsAhead() = %d, %v; want 0, nil", ahead, err)
+	}
+
+	newPath, err := mgr.RenameWorktreeBranch(oldPath, "task/fix-the-thing", "task/fix-login")
+	if err != nil {
+		t.Fatalf("RenameWorktreeBranch() error = %v", err)
+	}
+	if want := filepath.Join(tmpDir, "worktrees", "task-fix-login"); newPath != want {
+		t.Errorf("RenameWorktreeBranch() path = %q; want %q", newPath, want)
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Errorf("old worktree path %q still exists", oldPath)
+	}
+	if branch, err := CurrentBranch(newPath); err != nil || branch != "task/fix-login" {
+		t.Errorf("CurrentBranch() = %q, %v; want %q", branch, err, "task/fix-login")
+	}
+
+	runGit(t, newPath, "commit", "-q", "--allow-empty", "-m", "work")
+	if ahead, err := mgr.CommitsAhead("task/fix-login", "main"); err != nil || ahead != 1 {
+		t.Errorf("CommitsAhead() after commit = %d, %v; want 1, nil", ahead, err)
+	}
+}
+
+func TestAttachWorktree(t *testing.T) {
+	tmpDir, repo := newTestRepo(t)
+	runGit(t, repo, "branch", "feature/login")
+
+	mgr := NewWorktreeManagerFromPaths(repo, filepath.Join(tmpDir, "worktrees"))
+	branches, err := mgr.ListBranches()
+	if err != nil {
+		t.Fatalf("ListBranches() error = %v", err)
+	}
+	if want := []string{"feature/login", "main"}; !reflect.DeepEqual(branches, want) {
+		t.Errorf("ListBranches() = %v; want %v", branches, want)
+	}
+
+	path, err := mgr.AttachWorktree("feature/login")
+	if err != nil {
+		t.Fatalf("AttachWorktree() error = %v", err)
+	}
+	if want := filepath.Join(tmpDir, "worktrees", "login"); path != want {
+		t.Errorf("AttachWorktree() path = %q; want %q", path, want)
+	}
+	if branch, err := CurrentBranch(path); err != nil || branch != "feature/login" {
+		t.Errorf("CurrentBranch() = %q, %v; want %q", branch, err, "feature/login")
+	}
+
+	again, err := mgr.AttachWorktree("feature/login")
+	if err != nil || again != path {
+		t.Errorf("AttachWorktree() again = %q, %v; want existing %q", again, err, path)
+	}
+
+	if _, err := mgr.AttachWorktree("main"); err == nil {
+		t.Error("AttachWorktree(main) succeeded for the main repository's branch")
+	}
+	if _, err := mgr.AttachWorktree("missing"); err == nil {
+		t.Error("AttachWorktree(missing) succeeded for a missing branch")
+	}
+}
+
+func TestIsBranchMerged(t *testing.T) {
+	tmpDir, repo := newTestRepo(t)
+
+	mgr := NewWorktreeManagerFromPaths(repo, filepath.Join(tmpDir, "worktrees"))
+	path, err := mgr.CreateWorktree("task/work", "main")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	if merged, err := mgr.IsBranchMerged("task/work", "main"); err != nil || !merged {
+		t.Errorf("IsBranchMerged() with no commits = %v, %v; want true, nil", merged, err)
+	}
+
+	runGit(t, path, "commit", "-q", "--allow-empty", "-m", "work")
+	if merged, err := mgr.IsBranchMerged("task/work", "main"); err != nil || merged {
+		t.Errorf("IsBranchMerged() with new commit = %v, %v; want false, nil", merged, err)
+	}
+
+	runGit(t, repo, "merge", "-q", "--no-ff", "-m", "merge", "task/work")
+	if merged, err := mgr.IsBranchMerged("task/work", "main"); err != nil || !merged {
+		t.Errorf("IsBranchMerged() after merge = %v, %v; want true, nil", merged, err)
+	}
+
+	if _, err := mgr.IsBranchMerged("task/work", "missing"); err == nil {
+		t.Error("IsBranchMerged() with a missing base succeeded")
+	}
+}
+
+func TestAheadBehind(t *testing.T) {
+	tmpDir, repo := newTestRepo(t)
+
+	mgr := NewWorktreeManagerFromPaths(repo, filepath.Join(tmpDir, "worktrees"))
+	path, err := mgr.CreateWorktree("task/drift", "main")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+
+	runGit(t, path, "commit", "-q", "--allow-empty", "-m", "one")
+	runGit(t, path, "commit", "-q", "--allow-empty", "-m", "two")
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "base moved")
+
+	ahead, behind, err := mgr.AheadBehind(path, "main")
+	if err != nil {
+		t.Fatalf("AheadBehind() error = %v", err)
+	}
+	if ahead != 2 || behind != 1 {
+		t.Errorf("AheadBehind() = %d, %d; want 2, 1", ahead, behind)
+	}
+
+	if _, _, err := mgr.AheadBehind(path, "no-such-branch"); err == nil {
+		t.Error("AheadBehind() with missing base should fail")
+	}
+}
+
+func TestCountUncommittedFiles(t *testing.T) {
+	tmpDir, repo := newTestRepo(t)
+
+	mgr := NewWorktreeManagerFromPaths(repo, filepath.Join(tmpDir, "worktrees"))
+	path, err := mgr.CreateWorktree("task/count", "main")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(path, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("tracked.txt", "one")
+	runGit(t, path, "add", "tracked.txt")
+	runGit(t, path, "commit", "-q", "-m", "add tracked")
+	if n, err := mgr.CountUncommittedFiles(path); err != nil || n != 0 {
+		t.Errorf("CountUncommittedFiles() on clean worktree = %d, %v; want 0, nil", n, err)
+	}
+
+	write("untracked.txt", "x")
+	if n, err := mgr.CountUncommittedFiles(path); err != nil || n != 1 {
+		t.Errorf("CountUncommittedFiles() with an untracked file = %d, %v; want 1, nil", n, err)
+	}
+
+	write("tracked.txt", "two")
+	if n, err := mgr.CountUncommittedFiles(path); err != nil || n != 2 {
+		t.Errorf("CountUncommittedFiles() with a modified file = %d, %v; want 2, nil", n, err)
+	}
+
+	write("staged.txt", "y")
+	runGit(t, path, "add", "staged.txt")
+	if n, err := mgr.CountUncommittedFiles(path); err != nil || n != 3 {
+		t.Errorf("CountUncommittedFiles() with a staged file = %d, %v; want 3, nil", n, err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	_, repo := newTestRepo(t)
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("tracked.txt", "one\n")
+	write(".gitignore", "ignored.txt\n")
+	runGit(t, repo, "add", "tracked.txt", ".gitignore")
+	runGit(t, repo, "commit", "-q", "-m", "add files")
+
+	write("tracked.txt", "two\n")
+	write("untracked.txt", "new\n")
+	write("ignored.txt", "secret\n")
+
+	diff, err := Diff(repo)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	for _, want := range []string{"+two", "+++ b/untracked.txt", "+new"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("Diff() missing %q:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, "ignored.txt") {
+		t.Errorf("Diff() includes an ignored file:\n%s", diff)
+	}
+	if status := runGit(t, repo, "status", "--porcelain"); !strings.Contains(status, "?? untracked.txt") {
+		t.Errorf("Diff() changed the worktree's index; status = %q", status)
+	}
+}
+
+func TestMergeBranch(t *testing.T) {
+	tmpDir, repo := newTestRepo(t)
+	write := func(dir, name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(repo, "shared.txt", "base\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "shared")
+
+	mgr := NewWorktreeManagerFromPaths(repo, filepath.Join(tmpDir, "worktrees"))
+
+	clean, err := mgr.CreateWorktree("task/clean", "main")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	write(clean, "new.txt", "feature\n")
+	runGit(t, clean, "add", ".")
+	runGit(t, clean, "commit", "-q", "-m", "feature")
+
+	if err := mgr.MergeBranch("task/clean", "main"); err != nil {
+		t.Fatalf("MergeBranch() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "new.txt")); err != nil {
+		t.Errorf("merged file missing from main repo: %v", err)
+	}
+
+	conflicting, err := mgr.CreateWorktree("task/conflict", "main")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	write(conflicting, "shared.txt", "from branch\n")
+	runGit(t, conflicting, "commit", "-q", "-am", "branch change")
+	write(repo, "shared.txt", "from main\n")
+	runGit(t, repo, "commit", "-q", "-am", "main change")
+
+	err = mgr.MergeBranch("task/conflict", "main")
+	var conflictErr *MergeConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("MergeBranch() error = %v; want *MergeConflictError", err)
+	}
+	if len(conflictErr.Files) != 1 || conflictErr.Files[0] != "shared.txt" {
+		t.Errorf("conflict files = %v; want [shared.txt]", conflictErr.Files)
+	}
+	if dirty, err := mgr.HasUncommittedChanges(repo); err != nil || dirty {
+		t.Errorf("main repo left dirty after aborted merge: dirty=%v err=%v", dirty, err)
+	}
+
+	if err := mgr.MergeBranch("task/clean", "other"); err == nil {
+		t.Error("MergeBranch() into a branch that isn't checked out should fail")
+	}
+}
+
+func TestRebaseBranch(t *testing.T) {
+	tmpDir, repo := newTestRepo(t)
+	write := func(dir, name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(repo, "shared.txt", "base\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "shared")
+
+	mgr := NewWorktreeManagerFromPaths(repo, filepath.Join(tmpDir, "worktrees"))
+	path, err := mgr.CreateWorktree("task/rebase", "main")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	write(path, "feature.txt", "feature\n")
+	if err := mgr.CommitAll(path, "feature"); err != nil {
+		t.Fatalf("CommitAll() error = %v", err)
+	}
+	if dirty, err := mgr.HasUncommittedChanges(path); err != nil || dirty {
+		t.Errorf("HasUncommittedChanges() after CommitAll = %v, %v; want false, nil", dirty, err)
+	}
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "base moved")
+
+	if err := mgr.RebaseBranch(path, "main"); err != nil {
+		t.Fatalf("RebaseBranch() error = %v", err)
+	}
+	if ahead, behind, err := mgr.AheadBehind(path, "main"); err != nil || ahead != 1 || behind != 0 {
+		t.Errorf("AheadBehind() after rebase = %d, %d, %v; want 1, 0, nil", ahead, behind, err)
+	}
+
+	write(path, "shared.txt", "from branch\n")
+	if err := mgr.CommitAll(path, "branch change"); err != nil {
+		t.Fatalf("CommitAll() error = %v", err)
+	}
+	write(repo, "shared.txt", "from main\n")
+	runGit(t, repo, "commit", "-q", "-am", "main change")
+
+	err = mgr.RebaseBranch(path, "main")
+	var conflictErr *MergeConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("RebaseBranch() error = %v; want *MergeConflictError", err)
+	}
+	if len(conflictErr.Files) != 1 || conflictErr.Files[0] != "shared.txt" {
+		t.Errorf("conflict files = %v; want [shared.txt]", conflictErr.Files)
+	}
+	if dirty, err := mgr.HasUncommittedChanges(path); err != nil || dirty {
+		t.Errorf("worktree left dirty after aborted rebase: dirty=%v err=%v", dirty, err)
+	}
+}
+
+func TestPushBranch(t *testing.T) {
+	tmpDir, repo := newTestRepo(t)
+	remote := filepath.Join(tmpDir, "remote.git")
+	runGit(t, tmpDir, "init", "-q", "--bare", remote)
+	runGit(t, repo, "remote", "add", "origin", remote)
+
+	mgr := NewWorktreeManagerFromPaths(repo, filepath.Join(tmpDir, "worktrees"))
+	if _, err := mgr.CreateWorktree("task/push-me", "main"); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+
+	if err := mgr.PushBranch("task/push-me"); err != nil {
+		t.Fatalf("PushBranch() error = %v", err)
+	}
+	if out := runGit(t, remote, "branch", "--list", "task/push-me"); !strings.Contains(out, "task/push-me") {
+		t.Errorf("remote branches = %q; want task/push-me", out)
+	}
+	if branches, err := mgr.ListRemoteBranches(); err != nil || !reflect.DeepEqual(branches, []string{"origin/task/push-me"}) {
+		t.Errorf("ListRemoteBranches() = %v, %v; want [origin/task/push-me]", branches, err)
+	}
+
+	if err := mgr.PushBranch("no-such-branch"); err == nil {
+		t.Error("PushBranch() of a missing branch should fail")
+	}
+}
+
+func TestGetDefaultBranchCached(t *testing.T) {
+	tmpDir, repo := newTestRepo(t)
+
+	mgr := NewWorktreeManagerFromPaths(repo, filepath.Join(tmpDir, "worktrees"))
+	if branch, err := mgr.GetDefaultBranch(); err != nil || branch != "main" {
+		t.Fatalf("GetDefaultBranch() = %q, %v; want main", branch, err)
+	}
+
+	runGit(t, repo, "branch", "-m", "main", "master")
+	if branch, _ := mgr.GetDefaultBranch(); branch != "main" {
+		t.Errorf("GetDefaultBranch() after rename = %q; want cached main", branch)
+	}
+
+	mgr.RefreshDefaultBranch()
+	if branch, _ := mgr.GetDefaultBranch(); branch != "master" {
+		t.Errorf("GetDefaultBranch() after refresh = %q; want master", branch)
+	}
+}
+
+func TestWorktreeSize(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr := NewWorktreeManagerFromPaths(tmpDir, tmpDir)
+
+	wt := filepath.Join(tmpDir, "wt")
+	files := map[string]int{
+		"main.go":            100,
+		"pkg/util.go":        50,
+		".git/objects/blob":  1000,
+		"pkg/.git":           7,
+		"pkg/deep/notes.txt": 25,
+	}
+	for name, size := range files {
+		path := filepath.Join(wt, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	size, err := mgr.WorktreeSize(wt)
+	if err != nil {
+		t.Fatalf("WorktreeSize() error = %v", err)
+	}
+	if size != 175 {
+		t.Errorf("WorktreeSize() = %d; want 175", size)
+	}
+
+	if _, err := mgr.WorktreeSize(filepath.Join(tmpDir, "missing")); err == nil {
+		t.Error("WorktreeSize() of a missing path should fail")
+	}
+}
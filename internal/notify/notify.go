@@ -0,0 +1,68 @@
+// Package notify shows desktop notifications using whichever notifier the
+// system has: terminal-notifier or osascript on macOS, notify-send elsewhere.
+package notify
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnavailable is returned by Send when no notifier is installed.
+var ErrUnavailable = errors.New("no desktop notifier found (install notify-send or terminal-notifier)")
+
+// Available reports whether Send can show notifications on this system.
+func Available() bool {
+	_, _, ok := command(runtime.GOOS, "", "", exec.LookPath)
+	return ok
+}
+
+// Send shows a desktop notification with title and message. It returns once
+// the notifier has started, without waiting for it to finish.
+func Send(title, message string) error {
+	name, args, ok := command(runtime.GOOS, title, message, exec.LookPath)
+	if !ok {
+		return ErrUnavailable
+	}
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// command returns the notifier to run on goos for title and message,
+// preferring terminal-notifier over osascript on macOS. ok is false when
+// none of them is found with lookPath.
+func command(goos, title, message string, lookPath func(string) (string, error)) (name string, args []string, ok bool) {
+	found := func(name string) bool {
+		_, err := lookPath(name)
+		return err == nil
+	}
+
+	if goos == "darwin" {
+		if found("terminal-notifier") {
+			return "terminal-notifier", []string{"-title", title, "-message", message}, true
+		}
+		if found("osascript") {
+			script := "display notification " + appleScriptString(message) +
+				" with title " + appleScriptString(title)
+			return "osascript", []string{"-e", script}, true
+		}
+		return "", nil, false
+	}
+
+	if found("notify-send") {
+		return "notify-send", []string{"--app-name=OpenKanban", title, message}, true
+	}
+	return "", nil, false
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
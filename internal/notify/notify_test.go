@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func lookPathFor(installed ...string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		for _, n := range installed {
+			if n == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		goos      string
+		installed []string
+		wantName  string
+		wantArgs  []string
+	}{
+		{
+			name:      "linux uses notify-send",
+			goos:      "linux",
+			installed: []string{"notify-send"},
+			wantName:  "notify-send",
+			wantArgs:  []string{"--app-name=OpenKanban", "Title", "Body"},
+		},
+		{
+			name:      "darwin prefers terminal-notifier",
+			goos:      "darwin",
+			installed: []string{"terminal-notifier", "osascript"},
+			wantName:  "terminal-notifier",
+			wantArgs:  []string{"-title", "Title", "-message", "Body"},
+		},
+		{
+			name:      "darwin falls back to osascript",
+			goos:      "darwin",
+			installed: []string{"osascript"},
+			wantName:  "osascript",
+			wantArgs:  []string{"-e", `display notification "Body" with title "Title"`},
+		},
+		{
+			name:      "darwin ignores notify-send",
+			goos:      "darwin",
+			installed: []string{"notify-send"},
+		},
+		{
+			name: "nothing installed",
+			goos: "linux",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args, ok := command(tt.goos, "Title", "Body", lookPathFor(tt.installed...))
+			if ok != (tt.wantName != "") {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantName != "")
+			}
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("command() = %q %q, want %q %q", name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestAppleScriptString(t *testing.T) {
+	got := appleScriptString(`say "hi" \ bye`)
+	want := `"say \"hi\" \\ bye"`
+	if got != want {
+		t.Errorf("appleScriptString() = %s, want %s", got, want)
+	}
+}
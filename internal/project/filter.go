@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/google/uuid"
 	"github.com/techdufus/openkanban/internal/board"
@@ -16,7 +17,10 @@ type SavedFilter struct {
 	ProjectIDs []string `json:"project_ids,omitempty"`
 	Statuses   []string `json:"statuses,omitempty"`
 	Labels     []string `json:"labels,omitempty"`
-	IsDefault  bool     `json:"is_default"`
+	// Query is board search text applied along with the filter; Matches
+	// leaves it to the caller.
+	Query     string `json:"query,omitempty"`
+	IsDefault bool   `json:"is_default"`
 }
 
 func NewFilter(name string) *SavedFilter {
@@ -159,15 +163,28 @@ func (r *FilterRegistry) GetDefault() *SavedFilter {
 	return nil
 }
 
+// SetDefault makes the filter with id the one loaded on startup, or clears
+// the default when id is empty.
+func (r *FilterRegistry) SetDefault(id string) error {
+	for _, f := range r.Filters {
+		f.IsDefault = f.ID == id
+	}
+	return r.Save()
+}
+
 func (r *FilterRegistry) Delete(id string) error {
 	delete(r.Filters, id)
 	return r.Save()
 }
 
+// List returns the saved filters sorted by name.
 func (r *FilterRegistry) List() []*SavedFilter {
 	result := make([]*SavedFilter, 0, len(r.Filters))
 	for _, f := range r.Filters {
 		result = append(result, f)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
 	return result
 }
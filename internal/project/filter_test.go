@@ -0,0 +1,53 @@
+package project
+
+import "testing"
+
+func TestFilterRegistry_SetDefaultPersists(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+
+	reg, err := LoadFilterRegistry()
+	if err != nil {
+		t.Fatalf("LoadFilterRegistry() error = %v", err)
+	}
+	bugs := NewFilter("bugs")
+	bugs.Labels = []string{"bug"}
+	api := NewFilter("api")
+	api.Query = "login"
+	if err := reg.Add(bugs); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := reg.Add(api); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if err := reg.SetDefault(bugs.ID); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+	if err := reg.SetDefault(api.ID); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+
+	loaded, err := LoadFilterRegistry()
+	if err != nil {
+		t.Fatalf("LoadFilterRegistry() reload error = %v", err)
+	}
+	def := loaded.GetDefault()
+	if def == nil || def.ID != api.ID || def.Query != "login" {
+		t.Fatalf("GetDefault() = %+v; want the api filter", def)
+	}
+	if loaded.Get(bugs.ID).IsDefault {
+		t.Error("previous default still marked default")
+	}
+
+	list := loaded.List()
+	if len(list) != 2 || list[0].Name != "api" || list[1].Name != "bugs" {
+		t.Errorf("List() not sorted by name: %v, %v", list[0].Name, list[1].Name)
+	}
+
+	if err := loaded.SetDefault(""); err != nil {
+		t.Fatalf("SetDefault(\"\") error = %v", err)
+	}
+	if loaded.GetDefault() != nil {
+		t.Error("SetDefault(\"\") left a default")
+	}
+}
@@ -1,9 +1,16 @@
 package project
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
+	"github.com/techdufus/openkanban/internal/board"
 )
 
 // Project represents a git repository registered with OpenKanban.
@@ -29,6 +36,13 @@ type ProjectSettings struct {
 	BranchNaming     string `json:"branch_naming,omitempty"`   // "template" | "ai" | "prompt"
 	BranchTemplate   string `json:"branch_template,omitempty"` // e.g., "{prefix}{slug}"
 	SlugMaxLength    int    `json:"slug_max_length,omitempty"` // default: 40
+	// TicketIDPrefix formats the tickets' short IDs: a prefix such as
+	// "ENG-" followed by the ticket number, or a format placing the number
+	// with {n} (e.g. "{n}"). Empty derives a prefix from the project name.
+	TicketIDPrefix string `json:"ticket_id_prefix,omitempty"`
+
+	// Columns replaces the default Backlog / In Progress / Done columns.
+	Columns []board.Column `json:"columns,omitempty"`
 }
 
 // NewProject creates a new project for a repository
@@ -62,6 +76,15 @@ func (p *Project) GetWorktreeDir() string {
 	return p.RepoPath + "-worktrees"
 }
 
+// GetColumns returns the project's columns, using the defaults if not set
+// or invalid
+func (p *Project) GetColumns() []board.Column {
+	if len(p.Settings.Columns) > 0 && board.ValidateColumns(p.Settings.Columns) == nil {
+		return p.Settings.Columns
+	}
+	return board.DefaultColumns()
+}
+
 // GetBranchPrefix returns the branch prefix, using default if not set
 func (p *Project) GetBranchPrefix() string {
 	if p.Settings.BranchPrefix != "" {
@@ -86,7 +109,93 @@ func (p *Project) GetSlugMaxLength() int {
 	return 40
 }
 
+// GetTicketIDPrefix returns the short ID prefix or format, deriving one
+// from the project name if not set or invalid: the initials of a multi-word
+// name, else its first three letters, uppercased ("Open Kanban" gives "OK-").
+func (p *Project) GetTicketIDPrefix() string {
+	if p.Settings.TicketIDPrefix != "" && ValidateTicketIDPrefix(p.Settings.TicketIDPrefix) == nil {
+		return p.Settings.TicketIDPrefix
+	}
+
+	words := strings.FieldsFunc(p.Name, func(r rune) bool {
+		return r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var abbrev string
+	switch {
+	case len(words) > 1:
+		for _, w := range words {
+			abbrev += w[:1]
+		}
+	case len(words) == 1:
+		abbrev = words[0][:min(3, len(words[0]))]
+	}
+	if abbrev == "" {
+		abbrev = "T"
+	}
+	return strings.ToUpper(abbrev) + "-"
+}
+
+// TicketKey returns the short ID of the project's ticket with the given
+// number, such as "ENG-12", or "" for an unnumbered ticket.
+func (p *Project) TicketKey(number int) string {
+	if number <= 0 {
+		return ""
+	}
+	return formatTicketKey(p.GetTicketIDPrefix(), number)
+}
+
+func formatTicketKey(prefix string, number int) string {
+	n := strconv.Itoa(number)
+	if strings.Contains(prefix, "{n}") {
+		return strings.ReplaceAll(prefix, "{n}", n)
+	}
+	return prefix + n
+}
+
+var ticketKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateTicketIDPrefix checks that a ticket ID prefix or format yields
+// IDs that are unique within a project and safe in branch and file names.
+func ValidateTicketIDPrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	if strings.Count(prefix, "{n}") > 1 {
+		return errors.New("ticket_id_prefix may contain {n} at most once")
+	}
+	if strings.ContainsAny(strings.ReplaceAll(prefix, "{n}", ""), "{}") {
+		return errors.New("ticket_id_prefix only supports the {n} placeholder")
+	}
+	if key := formatTicketKey(prefix, 1); !ticketKeyPattern.MatchString(key) {
+		return errors.New("ticket_id_prefix must give IDs of letters, digits, '.', '_' and '-' starting with a letter or digit, not " + strconv.Quote(key))
+	}
+	return nil
+}
+
+// ValidateSettings checks the project's columns and ticket ID prefix. The
+// settings are kept as written either way; GetColumns and GetTicketIDPrefix
+// fall back to the defaults while they are invalid.
+func (p *Project) ValidateSettings() error {
+	var errs []error
+	if err := board.ValidateColumns(p.Settings.Columns); err != nil {
+		errs = append(errs, fmt.Errorf("columns: %w", err))
+	}
+	if err := ValidateTicketIDPrefix(p.Settings.TicketIDPrefix); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
 // Touch updates the UpdatedAt timestamp
 func (p *Project) Touch() {
 	p.UpdatedAt = time.Now()
 }
+
+// CopySettingsFrom replaces the project's settings with a copy of src's, so
+// a new repo can start out configured like an existing one.
+func (p *Project) CopySettingsFrom(src *Project) {
+	p.Settings = src.Settings
+	if src.Settings.Columns != nil {
+		p.Settings.Columns = append([]board.Column(nil), src.Settings.Columns...)
+	}
+}
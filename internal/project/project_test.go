@@ -0,0 +1,113 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func TestProject_CopySettingsFrom(t *testing.T) {
+	src := NewProject("api", "/repos/api")
+	src.Settings.AutoSpawnAgent = false
+	src.Settings.BranchPrefix = "api/"
+	src.Settings.BranchTemplate = "{prefix}{slug}"
+	src.Settings.SlugMaxLength = 20
+	src.Settings.Columns = []board.Column{
+		{ID: "todo", Name: "Todo", Status: board.StatusBacklog},
+		{ID: "doing", Name: "Doing", Status: board.StatusInProgress},
+		{ID: "done", Name: "Done", Status: board.StatusDone},
+	}
+
+	dst := NewProject("web", "/repos/web")
+	dst.CopySettingsFrom(src)
+
+	if dst.Settings.AutoSpawnAgent || dst.GetBranchPrefix() != "api/" || dst.GetSlugMaxLength() != 20 {
+		t.Errorf("settings not copied: %+v", dst.Settings)
+	}
+	if dst.Name != "web" || dst.RepoPath != "/repos/web" || dst.GetWorktreeDir() != "/repos/web-worktrees" {
+		t.Errorf("copy changed project identity: %+v", dst)
+	}
+
+	dst.Settings.Columns[0].Name = "Inbox"
+	if src.Settings.Columns[0].Name != "Todo" {
+		t.Error("columns are shared between projects after copy")
+	}
+}
+
+func TestProject_TicketKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		want   string
+	}{
+		{"Open Kanban", "", "OK-7"},
+		{"openkanban", "", "OPE-7"},
+		{"my-api_server", "", "MAS-7"},
+		{"--", "", "T-7"},
+		{"api", "ENG-", "ENG-7"},
+		{"api", "{n}", "7"},
+		{"api", "JIRA-{n}-x", "JIRA-7-x"},
+	}
+	for _, tt := range tests {
+		p := NewProject(tt.name, "/repos/x")
+		p.Settings.TicketIDPrefix = tt.prefix
+		if got := p.TicketKey(7); got != tt.want {
+			t.Errorf("TicketKey(7) for %q/%q = %q; want %q", tt.name, tt.prefix, got, tt.want)
+		}
+		if got := p.TicketKey(0); got != "" {
+			t.Errorf("TicketKey(0) = %q; want empty", got)
+		}
+	}
+}
+
+func TestValidateTicketIDPrefix(t *testing.T) {
+	valid := []string{"", "ENG-", "{n}", "T{n}", "v1.{n}"}
+	for _, prefix := range valid {
+		if err := ValidateTicketIDPrefix(prefix); err != nil {
+			t.Errorf("ValidateTicketIDPrefix(%q) error = %v; want nil", prefix, err)
+		}
+	}
+	invalid := []string{"ENG/", "a b-", "-x", "{n}-{n}", "{id}-", "../"}
+	for _, prefix := range invalid {
+		if err := ValidateTicketIDPrefix(prefix); err == nil {
+			t.Errorf("ValidateTicketIDPrefix(%q) = nil; want error", prefix)
+		}
+	}
+}
+
+func TestLoadRegistry_KeepsInvalidSettings(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+
+	reg, err := LoadRegistry()
+	if err != nil {
+		t.Fatalf("LoadRegistry() error = %v", err)
+	}
+	columns := []board.Column{{Name: "Todo", Status: board.StatusBacklog}}
+	bad := NewProject("bad", "/repos/bad")
+	bad.Settings.Columns = columns
+	bad.Settings.TicketIDPrefix = "../"
+	if err := reg.Add(bad); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	reg, err = LoadRegistry()
+	if err != nil {
+		t.Fatalf("LoadRegistry() error = %v", err)
+	}
+	loaded := reg.Projects[bad.ID]
+	if loaded == nil {
+		t.Fatal("project with invalid settings was not loaded")
+	}
+	if len(loaded.Settings.Columns) != 1 || loaded.Settings.TicketIDPrefix != "../" {
+		t.Errorf("invalid settings not kept as written: columns %v, prefix %q", loaded.Settings.Columns, loaded.Settings.TicketIDPrefix)
+	}
+	if err := loaded.ValidateSettings(); err == nil {
+		t.Error("ValidateSettings() = nil; want an error")
+	}
+	if got := loaded.GetColumns(); len(got) != len(board.DefaultColumns()) {
+		t.Errorf("GetColumns() = %v; want the default columns", got)
+	}
+	if got := loaded.GetTicketIDPrefix(); got != "BAD-" {
+		t.Errorf("GetTicketIDPrefix() = %q; want %q", got, "BAD-")
+	}
+}
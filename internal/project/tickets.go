@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/techdufus/openkanban/internal/board"
@@ -28,6 +29,9 @@ type TicketStore struct {
 	ProjectID string                           `json:"project_id"`
 	Tickets   map[board.TicketID]*board.Ticket `json:"tickets"`
 	UpdatedAt time.Time                        `json:"updated_at"`
+	// NextNumber is the number the next added ticket gets. Numbers are not
+	// reused after a ticket is deleted.
+	NextNumber int `json:"next_number,omitempty"`
 
 	repoPath string
 }
@@ -83,6 +87,7 @@ func LoadTicketStore(project *Project) (*TicketStore, error) {
 		store.Tickets = make(map[board.TicketID]*board.Ticket)
 	}
 	store.repoPath = project.RepoPath
+	store.numberTickets()
 
 	return store, nil
 }
@@ -114,9 +119,42 @@ func (s *TicketStore) Save() error {
 
 func (s *TicketStore) Add(ticket *board.Ticket) {
 	ticket.ProjectID = s.ProjectID
+	if ticket.Number == 0 {
+		ticket.Number = s.PeekNumber()
+		s.NextNumber = ticket.Number + 1
+	}
 	s.Tickets[ticket.ID] = ticket
 }
 
+// PeekNumber returns the number the next added ticket will get.
+func (s *TicketStore) PeekNumber() int {
+	next := max(s.NextNumber, 1)
+	for _, t := range s.Tickets {
+		next = max(next, t.Number+1)
+	}
+	return next
+}
+
+// numberTickets numbers tickets saved before tickets had numbers, oldest
+// first, after the highest number in use.
+func (s *TicketStore) numberTickets() {
+	var unnumbered []*board.Ticket
+	for _, t := range s.Tickets {
+		if t.Number == 0 {
+			unnumbered = append(unnumbered, t)
+		}
+	}
+	sort.Slice(unnumbered, func(i, j int) bool {
+		if !unnumbered[i].CreatedAt.Equal(unnumbered[j].CreatedAt) {
+			return unnumbered[i].CreatedAt.Before(unnumbered[j].CreatedAt)
+		}
+		return unnumbered[i].ID < unnumbered[j].ID
+	})
+	for _, t := range unnumbered {
+		t.Number = s.PeekNumber()
+	}
+}
+
 func (s *TicketStore) Get(id board.TicketID) (*board.Ticket, error) {
 	t, ok := s.Tickets[id]
 	if !ok {
@@ -208,9 +246,52 @@ func LoadGlobalTicketStore(registry *ProjectRegistry) (*GlobalTicketStore, error
 		}
 	}
 
+	g.loadOrphanedStores()
+
 	return g, nil
 }
 
+// loadOrphanedStores picks up ticket files whose project was removed from
+// projects.json outside of OpenKanban. Their tickets stay visible (and
+// reassignable) instead of silently disappearing from the board.
+func (g *GlobalTicketStore) loadOrphanedStores() {
+	entries, err := os.ReadDir(ticketsDir())
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		projectID := strings.TrimSuffix(entry.Name(), ".json")
+		if _, known := g.ticketStores[projectID]; known {
+			continue
+		}
+
+		store := NewTicketStore(projectID, "")
+		data, err := os.ReadFile(store.filePath())
+		if err != nil {
+			continue
+		}
+		if err := json.Unmarshal(data, store); err != nil {
+			log.Printf("Skipping unreadable ticket file %s: %v", entry.Name(), err)
+			continue
+		}
+		if len(store.Tickets) == 0 {
+			continue
+		}
+
+		store.ProjectID = projectID
+		store.numberTickets()
+		g.ticketStores[projectID] = store
+		for id, ticket := range store.Tickets {
+			ticket.ProjectID = projectID
+			g.allTickets[id] = ticket
+		}
+	}
+}
+
 func (g *GlobalTicketStore) GetProject(id string) *Project {
 	return g.projects[id]
 }
@@ -219,6 +300,94 @@ func (g *GlobalTicketStore) GetProjectForTicket(ticket *board.Ticket) *Project {
 	return g.projects[ticket.ProjectID]
 }
 
+// IsOrphaned reports whether the ticket belongs to a project that is no
+// longer registered.
+func (g *GlobalTicketStore) IsOrphaned(ticket *board.Ticket) bool {
+	return g.projects[ticket.ProjectID] == nil
+}
+
+// OrphanedTickets returns all tickets whose project is no longer registered.
+func (g *GlobalTicketStore) OrphanedTickets() []*board.Ticket {
+	var result []*board.Ticket
+	for _, t := range g.allTickets {
+		if g.IsOrphaned(t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// ReassignTicket moves a ticket into another registered project. Worktree and
+// session state is cleared since it referred to the previous repository.
+func (g *GlobalTicketStore) ReassignTicket(id board.TicketID, projectID string) error {
+	ticket, ok := g.allTickets[id]
+	if !ok {
+		return board.ErrTicketNotFound
+	}
+
+	target := g.ticketStores[projectID]
+	if g.projects[projectID] == nil || target == nil {
+		return ErrProjectNotFound
+	}
+
+	oldProjectID := ticket.ProjectID
+	if oldProjectID == projectID {
+		return nil
+	}
+
+	// The target is saved before the ticket leaves the source, so a failed
+	// save never loses it; at worst it is briefly in both files.
+	moved := *ticket
+	// Numbers are per project; the ticket gets the next one in its new project.
+	moved.Number = 0
+	moved.WorktreePath = ""
+	moved.BaseBranch = ""
+	moved.AgentSessionID = ""
+	moved.AgentSpawnedAt = nil
+	moved.AgentPort = 0
+	moved.Touch()
+
+	nextNumber := target.NextNumber
+	target.Add(&moved)
+	if err := target.Save(); err != nil {
+		target.Delete(id)
+		target.NextNumber = nextNumber
+		return err
+	}
+	*ticket = moved
+	target.Tickets[id] = ticket
+
+	if source := g.ticketStores[oldProjectID]; source != nil {
+		source.Delete(id)
+		return g.saveOrDropStore(oldProjectID, source)
+	}
+	return nil
+}
+
+// saveOrDropStore persists a store, removing it entirely once an orphaned
+// store has no tickets left.
+func (g *GlobalTicketStore) saveOrDropStore(projectID string, store *TicketStore) error {
+	if g.projects[projectID] != nil || store.Count() > 0 {
+		return store.Save()
+	}
+
+	delete(g.ticketStores, projectID)
+	if err := os.Remove(store.filePath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// NextTicketNumber returns the number a ticket added to the project now
+// would get, or 0 for an unknown project.
+func (g *GlobalTicketStore) NextTicketNumber(projectID string) int {
+	store := g.ticketStores[projectID]
+	if store == nil {
+		return 0
+	}
+	return store.PeekNumber()
+}
+
 func (g *GlobalTicketStore) GetStoreForTicket(ticket *board.Ticket) *TicketStore {
 	return g.ticketStores[ticket.ProjectID]
 }
@@ -327,12 +496,62 @@ func (g *GlobalTicketStore) AddProject(p *Project) {
 	g.ticketStores[p.ID] = NewTicketStore(p.ID, p.RepoPath)
 }
 
+// ProjectTickets returns the tickets that belong to a project.
+func (g *GlobalTicketStore) ProjectTickets(id string) []*board.Ticket {
+	var result []*board.Ticket
+	for _, t := range g.allTickets {
+		if t.ProjectID == id {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// ReassignProjectTickets moves every ticket of a project into another
+// registered project, typically before the project is removed.
+func (g *GlobalTicketStore) ReassignProjectTickets(id, targetID string) error {
+	if g.projects[targetID] == nil {
+		return ErrProjectNotFound
+	}
+	for _, t := range g.ProjectTickets(id) {
+		if err := g.ReassignTicket(t.ID, targetID); err != nil {
+			return err
+		}
+	}
+	return removeTicketFile(id)
+}
+
+// DeleteProjectTickets permanently deletes every ticket of a project.
+func (g *GlobalTicketStore) DeleteProjectTickets(id string) error {
+	store := g.ticketStores[id]
+	if store == nil {
+		return ErrProjectNotFound
+	}
+	for _, t := range g.ProjectTickets(id) {
+		store.Delete(t.ID)
+		delete(g.allTickets, t.ID)
+	}
+	return removeTicketFile(id)
+}
+
+// removeTicketFile deletes a project's emptied ticket file so a later
+// RemoveProject has nothing to archive.
+func removeTicketFile(projectID string) error {
+	err := os.Remove(filepath.Join(ticketsDir(), projectID+".json"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// RemoveProject unregisters a project. Its ticket file, if any, is moved into
+// tickets/archived so the history is preserved; use ReassignProjectTickets or
+// DeleteProjectTickets first to handle the tickets differently.
 func (g *GlobalTicketStore) RemoveProject(id string) error {
 	if _, ok := g.projects[id]; !ok {
 		return ErrProjectNotFound
 	}
 
-	// Archive ticket file before removing
 	srcPath := filepath.Join(ticketsDir(), id+".json")
 	if _, err := os.Stat(srcPath); err == nil {
 		archivedDir := filepath.Join(ticketsDir(), "archived")
@@ -352,6 +571,9 @@ func (g *GlobalTicketStore) RemoveProject(id string) error {
 		log.Printf("Archived tickets to %s", dstPath)
 	}
 
+	for _, t := range g.ProjectTickets(id) {
+		delete(g.allTickets, t.ID)
+	}
 	delete(g.projects, id)
 	delete(g.ticketStores, id)
 
@@ -386,6 +608,40 @@ func (g *GlobalTicketStore) GetBlocks(ticketID board.TicketID) []*board.Ticket {
 	return blocks
 }
 
+// OpenBlockers returns the blockers of ticketID that are not yet finished.
+// Done and archived tickets no longer block.
+func (g *GlobalTicketStore) OpenBlockers(ticketID board.TicketID) []*board.Ticket {
+	var open []*board.Ticket
+	for _, blocker := range g.GetBlockedBy(ticketID) {
+		if blocker.Status != board.StatusDone && blocker.Status != board.StatusArchived {
+			open = append(open, blocker)
+		}
+	}
+	return open
+}
+
+// WouldCreateCycle reports whether making ticketID blocked by blockers would
+// introduce a dependency cycle.
+func (g *GlobalTicketStore) WouldCreateCycle(ticketID board.TicketID, blockers []board.TicketID) bool {
+	visited := make(map[board.TicketID]bool)
+	stack := append([]board.TicketID(nil), blockers...)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == ticketID {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		if t, ok := g.allTickets[id]; ok {
+			stack = append(stack, t.BlockedBy...)
+		}
+	}
+	return false
+}
+
 func (g *GlobalTicketStore) RemoveBlockerReferences(ticketID board.TicketID) {
 	for _, ticket := range g.allTickets {
 		if len(ticket.BlockedBy) == 0 {
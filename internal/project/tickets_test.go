@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/techdufus/openkanban/internal/board"
 )
@@ -300,6 +301,49 @@ func TestTicketStore_Migration(t *testing.T) {
 	}
 }
 
+func TestTicketStore_Numbers(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("OPENKANBAN_CONFIG_DIR", tmpDir)
+
+	p := &Project{ID: "project-1", Name: "Test", RepoPath: filepath.Join(tmpDir, "repo")}
+	store := NewTicketStore(p.ID, p.RepoPath)
+	first := board.NewTicket("First", p.ID)
+	second := board.NewTicket("Second", p.ID)
+	store.Add(first)
+	store.Add(second)
+	if first.Number != 1 || second.Number != 2 {
+		t.Fatalf("numbers = %d, %d; want 1, 2", first.Number, second.Number)
+	}
+
+	// Deleted numbers are not reused
+	store.Delete(second.ID)
+	third := board.NewTicket("Third", p.ID)
+	store.Add(third)
+	if third.Number != 3 {
+		t.Errorf("third.Number = %d; want 3", third.Number)
+	}
+
+	// Tickets saved before numbering get numbers after the highest, oldest first
+	legacy := board.NewTicket("Legacy", p.ID)
+	legacy.CreatedAt = first.CreatedAt.Add(-time.Hour)
+	store.Tickets[legacy.ID] = legacy
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadTicketStore(p)
+	if err != nil {
+		t.Fatalf("LoadTicketStore failed: %v", err)
+	}
+	got, _ := loaded.Get(legacy.ID)
+	if got.Number != 4 {
+		t.Errorf("legacy ticket Number = %d; want 4", got.Number)
+	}
+	if loaded.PeekNumber() != 5 {
+		t.Errorf("PeekNumber() = %d; want 5", loaded.PeekNumber())
+	}
+}
+
 func TestGlobalTicketStore_RemoveProjectArchive(t *testing.T) {
 	tmpDir := t.TempDir()
 	configDir := filepath.Join(tmpDir, "config")
@@ -346,4 +390,209 @@ func TestGlobalTicketStore_RemoveProjectArchive(t *testing.T) {
 	if _, err := os.Stat(ticketPath); !os.IsNotExist(err) {
 		t.Error("original ticket file should not exist after archiving")
 	}
+
+	if globalStore.Count() != 0 {
+		t.Errorf("archived tickets still loaded: Count() = %d", globalStore.Count())
+	}
+}
+
+func TestGlobalTicketStore_RemoveProjectTickets(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "config")
+	os.MkdirAll(configDir, 0755)
+	t.Setenv("OPENKANBAN_CONFIG_DIR", configDir)
+
+	registry := newRegistry()
+	globalStore := NewGlobalTicketStore(registry)
+	var projects []*Project
+	for _, id := range []string{"keep", "reassign", "delete"} {
+		p := &Project{ID: id, Name: id, RepoPath: filepath.Join(tmpDir, id)}
+		registry.Add(p)
+		globalStore.AddProject(p)
+		projects = append(projects, p)
+	}
+	keep, reassign, del := projects[0], projects[1], projects[2]
+
+	moved := board.NewTicket("Moved", reassign.ID)
+	moved.WorktreePath = "/old/worktree"
+	doomed := board.NewTicket("Doomed", del.ID)
+	for _, ticket := range []*board.Ticket{moved, doomed} {
+		if err := globalStore.Add(ticket); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if err := globalStore.SaveAll(); err != nil {
+		t.Fatalf("SaveAll failed: %v", err)
+	}
+
+	if err := globalStore.ReassignProjectTickets(reassign.ID, "missing"); err != ErrProjectNotFound {
+		t.Errorf("ReassignProjectTickets to unknown project error = %v; want ErrProjectNotFound", err)
+	}
+	if err := globalStore.ReassignProjectTickets(reassign.ID, keep.ID); err != nil {
+		t.Fatalf("ReassignProjectTickets failed: %v", err)
+	}
+	if err := globalStore.RemoveProject(reassign.ID); err != nil {
+		t.Fatalf("RemoveProject failed: %v", err)
+	}
+	if moved.ProjectID != keep.ID || moved.WorktreePath != "" {
+		t.Errorf("reassigned ticket = (%q, %q); want (%q, \"\")", moved.ProjectID, moved.WorktreePath, keep.ID)
+	}
+	if _, err := globalStore.Get(moved.ID); err != nil {
+		t.Errorf("reassigned ticket should still be loaded: %v", err)
+	}
+
+	if err := globalStore.DeleteProjectTickets(del.ID); err != nil {
+		t.Fatalf("DeleteProjectTickets failed: %v", err)
+	}
+	if err := globalStore.RemoveProject(del.ID); err != nil {
+		t.Fatalf("RemoveProject failed: %v", err)
+	}
+	if _, err := globalStore.Get(doomed.ID); err != board.ErrTicketNotFound {
+		t.Errorf("deleted ticket Get error = %v; want ErrTicketNotFound", err)
+	}
+
+	// Emptied projects leave nothing behind, not even an archive
+	for _, id := range []string{reassign.ID, del.ID} {
+		for _, path := range []string{
+			filepath.Join(configDir, "tickets", id+".json"),
+			filepath.Join(configDir, "tickets", "archived", id+".json"),
+		} {
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("%s should not exist", path)
+			}
+		}
+	}
+	if got := globalStore.Count(); got != 1 {
+		t.Errorf("Count() = %d; want 1", got)
+	}
+}
+
+func TestGlobalTicketStore_OrphanedTickets(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "config")
+	repoDir := filepath.Join(tmpDir, "repo")
+	os.MkdirAll(configDir, 0755)
+	os.MkdirAll(repoDir, 0755)
+	t.Setenv("OPENKANBAN_CONFIG_DIR", configDir)
+
+	registry := newRegistry()
+	p := &Project{ID: "project-1", Name: "Live", RepoPath: repoDir}
+	registry.Add(p)
+
+	// Ticket file left behind by a project removed from projects.json externally
+	orphanStore := NewTicketStore("gone-project", "")
+	orphan := board.NewTicket("Orphan", "gone-project")
+	orphanStore.Add(orphan)
+	if err := orphanStore.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	globalStore, err := LoadGlobalTicketStore(registry)
+	if err != nil {
+		t.Fatalf("LoadGlobalTicketStore failed: %v", err)
+	}
+
+	loaded, err := globalStore.Get(orphan.ID)
+	if err != nil {
+		t.Fatalf("orphaned ticket should be loaded: %v", err)
+	}
+	orphan = loaded
+	if !globalStore.IsOrphaned(orphan) {
+		t.Error("ticket should be reported as orphaned")
+	}
+	if got := len(globalStore.OrphanedTickets()); got != 1 {
+		t.Errorf("OrphanedTickets() returned %d tickets; want 1", got)
+	}
+	if globalStore.GetProjectForTicket(orphan) != nil {
+		t.Error("GetProjectForTicket should return nil for orphaned ticket")
+	}
+
+	orphan.WorktreePath = "/old/worktree"
+
+	// A directory in the way makes saving the target project fail.
+	targetPath := filepath.Join(configDir, "tickets", p.ID+".json")
+	if err := os.MkdirAll(filepath.Join(targetPath, "blocked"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := globalStore.ReassignTicket(orphan.ID, p.ID); err == nil {
+		t.Fatal("ReassignTicket should fail when the target can't be saved")
+	}
+	if !globalStore.IsOrphaned(orphan) || orphan.WorktreePath != "/old/worktree" {
+		t.Error("ticket should be left unchanged when the target save fails")
+	}
+	reloaded, err := LoadGlobalTicketStore(registry)
+	if err != nil {
+		t.Fatalf("LoadGlobalTicketStore failed: %v", err)
+	}
+	if _, err := reloaded.Get(orphan.ID); err != nil {
+		t.Errorf("ticket lost after a failed reassignment: %v", err)
+	}
+	if err := os.RemoveAll(targetPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := globalStore.ReassignTicket(orphan.ID, p.ID); err != nil {
+		t.Fatalf("ReassignTicket failed: %v", err)
+	}
+
+	if orphan.ProjectID != p.ID {
+		t.Errorf("ProjectID = %q; want %q", orphan.ProjectID, p.ID)
+	}
+	if orphan.WorktreePath != "" {
+		t.Errorf("WorktreePath = %q; want empty after reassignment", orphan.WorktreePath)
+	}
+	if globalStore.IsOrphaned(orphan) {
+		t.Error("ticket should not be orphaned after reassignment")
+	}
+
+	orphanPath := filepath.Join(configDir, "tickets", "gone-project.json")
+	if _, err := os.Stat(orphanPath); !os.IsNotExist(err) {
+		t.Error("empty orphaned ticket file should be removed")
+	}
+
+	if err := globalStore.ReassignTicket(orphan.ID, "missing"); err != ErrProjectNotFound {
+		t.Errorf("ReassignTicket to unknown project error = %v; want ErrProjectNotFound", err)
+	}
+}
+
+func TestGlobalTicketStore_BlockerDependencies(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+
+	registry := newRegistry()
+	p := &Project{ID: "project-1", Name: "Test", RepoPath: t.TempDir()}
+	registry.Add(p)
+
+	globalStore := NewGlobalTicketStore(registry)
+	globalStore.AddProject(p)
+
+	a := board.NewTicket("A", p.ID)
+	b := board.NewTicket("B", p.ID)
+	c := board.NewTicket("C", p.ID)
+	b.BlockedBy = []board.TicketID{a.ID}
+	c.BlockedBy = []board.TicketID{b.ID}
+	for _, ticket := range []*board.Ticket{a, b, c} {
+		if err := globalStore.Add(ticket); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	if !globalStore.WouldCreateCycle(a.ID, []board.TicketID{c.ID}) {
+		t.Error("A blocked by C should be a cycle (C -> B -> A)")
+	}
+	if !globalStore.WouldCreateCycle(a.ID, []board.TicketID{a.ID}) {
+		t.Error("a ticket blocking itself should be a cycle")
+	}
+	if globalStore.WouldCreateCycle(c.ID, []board.TicketID{a.ID}) {
+		t.Error("C blocked by A should not be a cycle")
+	}
+
+	open := globalStore.OpenBlockers(c.ID)
+	if len(open) != 1 || open[0].ID != b.ID {
+		t.Errorf("OpenBlockers(C) = %v; want [B]", open)
+	}
+
+	b.Status = board.StatusDone
+	if open := globalStore.OpenBlockers(c.ID); len(open) != 0 {
+		t.Errorf("OpenBlockers(C) = %v; want none once B is done", open)
+	}
 }
@@ -3,10 +3,15 @@ package terminal
 import (
 	"bytes"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/atotto/clipboard"
@@ -30,6 +35,7 @@ type Pane struct {
 	exitErr     error
 	workdir     string
 	sessionName string
+	env         map[string]string
 	width       int
 	height      int
 
@@ -40,6 +46,13 @@ type Pane struct {
 
 	mouseEnabled bool // tracks if child process has enabled mouse tracking
 
+	// paused drains PTY output without feeding it to the emulator, for
+	// chatty agents running in the background.
+	paused bool
+
+	// startedAt is when the current process was started.
+	startedAt time.Time
+
 	// Scrollback and viewport state (Issue #95)
 	scrollback      *ScrollbackBuffer
 	altScreenActive bool     // tracks if child process is in alternate screen mode
@@ -47,6 +60,21 @@ type Pane struct {
 	lastTopRow      []vt10x.Glyph // snapshot of row 0 before write for scroll detection
 	scrollbackSize  int      // configured scrollback buffer size
 	selection       *SelectionState // mouse text selection state
+
+	// logPath receives a copy of all PTY output when set. logFile is
+	// opened on the first write and closed when the process exits.
+	logPath string
+	logFile *os.File
+	// logTimestamps prefixes each logged line with when its output arrived;
+	// logLineStart is set while the next logged byte starts a line.
+	logTimestamps bool
+	logLineStart  bool
+
+	// searchQuery is the active scrollback search; searchMatches are the
+	// scrollback lines containing it and searchCurrent the one scrolled to.
+	searchQuery   string
+	searchMatches []int
+	searchCurrent int
 }
 
 func New(id string, width, height int, scrollbackSize int) *Pane {
@@ -58,6 +86,7 @@ func New(id string, width, height int, scrollbackSize int) *Pane {
 		width:          width,
 		height:         height,
 		scrollbackSize: scrollbackSize,
+		searchCurrent:  -1,
 	}
 }
 
@@ -80,6 +109,103 @@ func (p *Pane) SetSessionName(name string) {
 	p.sessionName = name
 }
 
+func (p *Pane) GetSessionName() string {
+	return p.sessionName
+}
+
+// SetEnv sets extra environment variables for commands, added on top of
+// the cleaned parent environment and overriding variables of the same name.
+func (p *Pane) SetEnv(env map[string]string) {
+	p.env = maps.Clone(env)
+}
+
+// Env returns the extra environment variables set with SetEnv.
+func (p *Pane) Env() map[string]string {
+	return maps.Clone(p.env)
+}
+
+// ScrollbackSize returns the most lines the pane keeps in scrollback.
+func (p *Pane) ScrollbackSize() int {
+	return p.scrollbackSize
+}
+
+// SetLogFile appends everything the process prints to path, creating the
+// file and its directory on the first output. An empty path stops logging.
+func (p *Pane) SetLogFile(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closeLogUnlocked()
+	p.logPath = path
+	p.logLineStart = true
+}
+
+// SetLogTimestamps prefixes each line written to the log file with the time
+// its output arrived, e.g. "[2024-06-01 14:03:27] ".
+func (p *Pane) SetLogTimestamps(on bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.logTimestamps = on
+}
+
+// writeLogUnlocked must be called with mu held. A log that can't be opened
+// or written is dropped rather than retried on every read.
+func (p *Pane) writeLogUnlocked(data []byte) {
+	if p.logPath == "" {
+		return
+	}
+	if p.logFile == nil {
+		if err := os.MkdirAll(filepath.Dir(p.logPath), 0755); err != nil {
+			p.logPath = ""
+			return
+		}
+		f, err := os.OpenFile(p.logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			p.logPath = ""
+			return
+		}
+		p.logFile = f
+	}
+	if p.logTimestamps {
+		data = p.timestampLogUnlocked(data, time.Now())
+	}
+	if _, err := p.logFile.Write(data); err != nil {
+		p.closeLogUnlocked()
+		p.logPath = ""
+	}
+}
+
+// timestampLogUnlocked returns data with now's timestamp before each line
+// that starts in it. A line split across reads is stamped once, with the
+// arrival time of its first part. It must be called with mu held.
+func (p *Pane) timestampLogUnlocked(data []byte, now time.Time) []byte {
+	prefix := now.Format("[2006-01-02 15:04:05] ")
+	var b bytes.Buffer
+	for len(data) > 0 {
+		if p.logLineStart {
+			b.WriteString(prefix)
+			p.logLineStart = false
+		}
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			b.Write(data)
+			break
+		}
+		b.Write(data[:i+1])
+		data = data[i+1:]
+		p.logLineStart = true
+	}
+	return b.Bytes()
+}
+
+func (p *Pane) closeLogUnlocked() {
+	if p.logFile != nil {
+		p.logFile.Close()
+		p.logFile = nil
+	}
+}
+
 // Running returns whether the pane has a running process
 func (p *Pane) Running() bool {
 	p.mu.Lock()
@@ -87,6 +213,45 @@ func (p *Pane) Running() bool {
 	return p.running
 }
 
+// StartedAt returns when the pane's current process was started, or the
+// zero time if it has never started.
+func (p *Pane) StartedAt() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.startedAt
+}
+
+// Pause stops processing output. The PTY is still drained so the child
+// never blocks on a full buffer; anything it prints while paused is dropped.
+func (p *Pane) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+}
+
+// Resume restarts output processing and asks the child to redraw, so
+// full-screen agents repaint whatever was dropped while paused.
+func (p *Pane) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.paused {
+		return
+	}
+	p.paused = false
+	p.dirty = true
+	if p.running && p.cmd != nil && p.cmd.Process != nil {
+		p.cmd.Process.Signal(syscall.SIGWINCH)
+	}
+}
+
+// Paused reports whether output processing is paused
+func (p *Pane) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
 // ExitErr returns any error from the process exit
 func (p *Pane) ExitErr() error {
 	p.mu.Lock()
@@ -186,29 +351,29 @@ func (p *Pane) Start(command string, args ...string) tea.Cmd {
 
 		// Build command
 		p.cmd = exec.Command(command, args...)
-		p.cmd.Env = buildCleanEnv(p.sessionName)
+		p.cmd.Env = buildCleanEnv(p.sessionName, p.env)
 
 		// Set working directory if specified
 		if p.workdir != "" {
 			p.cmd.Dir = p.workdir
 		}
 
-		// Start PTY first so we can use it as vt10x writer
-		ptmx, err := pty.Start(p.cmd)
+		// Start PTY first so we can use it as vt10x writer. The size is applied
+		// before the child runs so agents that query it on launch see the
+		// pane's current dimensions rather than a default.
+		ptmx, err := pty.StartWithSize(p.cmd, &pty.Winsize{
+			Rows: uint16(p.height),
+			Cols: uint16(p.width),
+		})
 		if err != nil {
 			p.exitErr = err
 			return ExitMsg{PaneID: p.id, Err: err}
 		}
 		p.pty = ptmx
 		p.running = true
+		p.startedAt = time.Now()
 		p.exitErr = nil
 
-		// Set PTY size
-		pty.Setsize(p.pty, &pty.Winsize{
-			Rows: uint16(p.height),
-			Cols: uint16(p.width),
-		})
-
 		// Create virtual terminal with PTY as writer for escape sequence responses
 		// This allows the terminal emulator to respond to queries like cursor position (DSR)
 		p.vt = vt10x.New(vt10x.WithSize(p.width, p.height), vt10x.WithWriter(p.pty))
@@ -231,6 +396,7 @@ func (p *Pane) Stop() error {
 		p.pty.Close()
 	}
 	p.running = false
+	p.closeLogUnlocked()
 	return nil
 }
 
@@ -266,6 +432,7 @@ func (p *Pane) StopGraceful(timeout time.Duration) error {
 		p.pty.Close()
 	}
 	p.running = false
+	p.closeLogUnlocked()
 	p.mu.Unlock()
 
 	return nil
@@ -318,6 +485,9 @@ func (p *Pane) Update(msg tea.Msg) tea.Cmd {
 		if msg.PaneID != p.id {
 			return nil
 		}
+		if p.Paused() {
+			return p.readOutput()
+		}
 		p.handleOutput(msg.Data)
 		return tea.Batch(p.readOutput(), p.scheduleRenderTick())
 
@@ -340,6 +510,7 @@ func (p *Pane) Update(msg tea.Msg) tea.Cmd {
 		if p.pty != nil {
 			p.pty.Close()
 		}
+		p.closeLogUnlocked()
 		p.mu.Unlock()
 		return nil
 	}
@@ -355,6 +526,7 @@ func (p *Pane) handleOutput(data []byte) {
 		return
 	}
 
+	p.writeLogUnlocked(data)
 	p.detectMouseModeChanges(data)
 	p.detectAltScreenChanges(data)
 
@@ -696,6 +868,7 @@ func (p *Pane) HandleKey(msg tea.KeyMsg) tea.Msg {
 		// Esc returns to live view if scrolled
 		if p.viewportOffset > 0 {
 			p.viewportOffset = 0
+			p.clearSearchUnlocked()
 			p.dirty = true
 			return nil
 		}
@@ -713,6 +886,7 @@ func (p *Pane) HandleKey(msg tea.KeyMsg) tea.Msg {
 		p.viewportOffset = 0
 		p.dirty = true
 	}
+	p.clearSearchUnlocked()
 
 	// Clear selection on any keyboard input (except copy)
 	if p.selection != nil && p.selection.IsActive() {
@@ -876,6 +1050,52 @@ func (p *Pane) GetContent() string {
 	return result.String()
 }
 
+// GetVisibleText returns the text currently shown in the pane, including
+// scrollback lines when scrolled back, as plain text. Trailing spaces and
+// trailing blank lines are dropped.
+func (p *Pane) GetVisibleText() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.vt == nil {
+		return ""
+	}
+
+	p.vt.Lock()
+	defer p.vt.Unlock()
+
+	cols, rows := p.vt.Size()
+	if cols <= 0 || rows <= 0 {
+		return ""
+	}
+
+	offset, scrollbackStart := 0, 0
+	if p.scrollback != nil {
+		offset = min(p.viewportOffset, p.scrollback.Len())
+		scrollbackStart = p.scrollback.Len() - offset
+	}
+	scrollbackRows := min(offset, rows)
+
+	lines := make([]string, 0, rows)
+	for viewRow := 0; viewRow < rows; viewRow++ {
+		var line string
+		if viewRow < scrollbackRows {
+			line = glyphText(p.scrollback.Get(scrollbackStart + viewRow))
+		} else {
+			row := make([]vt10x.Glyph, cols)
+			for col := 0; col < cols; col++ {
+				row[col] = p.vt.Cell(col, viewRow-scrollbackRows)
+			}
+			line = glyphText(row)
+		}
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
 // --- Rendering (Issue #14) ---
 
 // View returns the rendered terminal content
@@ -974,6 +1194,14 @@ func (p *Pane) renderGlyphLine(line []vt10x.Glyph, cols int, logicalRow int) str
 	var batch strings.Builder
 	firstCell := true
 	inSelection := false
+	inMatch := false
+
+	matchCols := p.searchColumnsUnlocked(line)
+	matchStyle := "\x1b[30;43m" // Black on yellow for search matches
+	if p.searchCurrent >= 0 && p.searchCurrent < len(p.searchMatches) &&
+		p.searchMatches[p.searchCurrent] == logicalRow+p.scrollback.Len() {
+		matchStyle = "\x1b[30;46m" // Black on cyan for the current match
+	}
 
 	flushBatch := func() {
 		if batch.Len() == 0 {
@@ -981,6 +1209,8 @@ func (p *Pane) renderGlyphLine(line []vt10x.Glyph, cols int, logicalRow int) str
 		}
 		if inSelection {
 			result.WriteString("\x1b[7m") // Reverse video for selection
+		} else if inMatch {
+			result.WriteString(matchStyle)
 		} else {
 			result.WriteString(buildANSI(currentFG, currentBG, currentMode))
 		}
@@ -1001,9 +1231,10 @@ func (p *Pane) renderGlyphLine(line []vt10x.Glyph, cols int, logicalRow int) str
 
 		// Check if this cell is selected
 		cellSelected := p.selection != nil && p.selection.Contains(Position{Row: logicalRow, Col: col})
+		cellMatched := col < len(matchCols) && matchCols[col]
 
 		// Style changed or selection changed? Flush batch
-		if !firstCell && (glyph.FG != currentFG || glyph.BG != currentBG || glyph.Mode != currentMode || cellSelected != inSelection) {
+		if !firstCell && (glyph.FG != currentFG || glyph.BG != currentBG || glyph.Mode != currentMode || cellSelected != inSelection || cellMatched != inMatch) {
 			flushBatch()
 		}
 
@@ -1011,6 +1242,7 @@ func (p *Pane) renderGlyphLine(line []vt10x.Glyph, cols int, logicalRow int) str
 		currentBG = glyph.BG
 		currentMode = glyph.Mode
 		inSelection = cellSelected
+		inMatch = cellMatched
 		firstCell = false
 
 		batch.WriteRune(ch)
@@ -1162,8 +1394,28 @@ func (p *Pane) renderLiveScreenUnlocked(cols, rows int) string {
 	return result.String()
 }
 
-// buildANSI constructs ANSI escape sequence for given colors/mode
+// Glyph mode bits, mirroring vt10x's unexported attr constants.
+const (
+	attrReverse = 1 << iota
+	attrUnderline
+	attrBold
+	attrGfx
+	attrItalic
+	attrBlink
+)
+
+// buildANSI constructs ANSI escape sequence for given colors/mode.
+//
+// vt10x stores reverse-video cells with FG and BG already swapped while
+// keeping the reverse bit, so the swap is undone here and the terminal does
+// the reversing. Emitting the swapped colors plus reverse would reverse
+// twice, and a default color swapped into the other slot can't be expressed
+// as a color at all.
 func buildANSI(fg, bg vt10x.Color, mode int16) string {
+	if mode&attrReverse != 0 {
+		fg, bg = bg, fg
+	}
+
 	var parts []string
 
 	// Foreground
@@ -1177,16 +1429,19 @@ func buildANSI(fg, bg vt10x.Color, mode int16) string {
 	}
 
 	// Attributes
-	if mode&0x04 != 0 { // Bold
+	if mode&attrBold != 0 {
 		parts = append(parts, "1")
 	}
-	if mode&0x10 != 0 { // Italic
+	if mode&attrItalic != 0 {
 		parts = append(parts, "3")
 	}
-	if mode&0x02 != 0 { // Underline
+	if mode&attrUnderline != 0 {
 		parts = append(parts, "4")
 	}
-	if mode&0x01 != 0 { // Reverse
+	if mode&attrBlink != 0 {
+		parts = append(parts, "5")
+	}
+	if mode&attrReverse != 0 {
 		parts = append(parts, "7")
 	}
 
@@ -1197,19 +1452,42 @@ func buildANSI(fg, bg vt10x.Color, mode int16) string {
 	return fmt.Sprintf("\x1b[%sm", strings.Join(parts, ";"))
 }
 
-// colorToANSI converts vt10x.Color to ANSI escape sequence component
+// colorToANSI converts vt10x.Color to ANSI escape sequence component.
+//
+// vt10x encodes the 16 ANSI colors as 0-15, the xterm palette as 16-255,
+// true color as r<<16|g<<8|b, and its default colors from 1<<24 up. The 16
+// ANSI colors are emitted as 30-37/90-97 (40-47/100-107 for backgrounds) so
+// they follow the user's terminal theme. vt10x already turns bold text in
+// colors 0-7 into 8-15, so such cells come out bright and bold.
+//
+// True colors whose red and green are both 0 share values with the palette
+// in this encoding (38;2;0;0;200 is stored as 200), so they are rendered as
+// palette colors; vt10x keeps no way to tell them apart.
 func colorToANSI(c vt10x.Color, isFG bool) string {
-	// Default color (special value)
-	if c >= 0x01000000 {
+	// Default colors (DefaultFG, DefaultBG, DefaultCursor)
+	if c >= vt10x.DefaultFG {
 		return ""
 	}
 
+	switch {
+	case c < 8:
+		if isFG {
+			return strconv.Itoa(30 + int(c))
+		}
+		return strconv.Itoa(40 + int(c))
+	case c < 16:
+		if isFG {
+			return strconv.Itoa(90 + int(c) - 8)
+		}
+		return strconv.Itoa(100 + int(c) - 8)
+	}
+
 	base := 38 // Foreground
 	if !isFG {
 		base = 48 // Background
 	}
 
-	// ANSI 256-color palette (0-255)
+	// xterm 256-color palette (16-255)
 	if c < 256 {
 		return fmt.Sprintf("%d;5;%d", base, c)
 	}
@@ -1221,7 +1499,9 @@ func colorToANSI(c vt10x.Color, isFG bool) string {
 	return fmt.Sprintf("%d;2;%d;%d;%d", base, r, g, b)
 }
 
-func buildCleanEnv(sessionName string) []string {
+// buildCleanEnv returns the parent environment without agent variables,
+// followed by extra sorted by name so it wins over anything inherited.
+func buildCleanEnv(sessionName string, extra map[string]string) []string {
 	var env []string
 	for _, e := range os.Environ() {
 		key := strings.Split(e, "=")[0]
@@ -1243,5 +1523,8 @@ func buildCleanEnv(sessionName string) []string {
 	if sessionName != "" {
 		env = append(env, "OPENKANBAN_SESSION="+sessionName)
 	}
+	for _, key := range slices.Sorted(maps.Keys(extra)) {
+		env = append(env, key+"="+extra[key])
+	}
 	return env
 }
@@ -2,7 +2,14 @@ package terminal
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/hinshun/vt10x"
 )
 
 func TestDetectMouseModeChanges(t *testing.T) {
@@ -187,3 +194,179 @@ func TestViewportScrolling(t *testing.T) {
 		t.Errorf("scrollDown beyond 0 should cap at 0, got %d", pane.viewportOffset)
 	}
 }
+
+func TestStartUsesSizeSetBeforeStart(t *testing.T) {
+	if _, err := exec.LookPath("stty"); err != nil {
+		t.Skip("stty not available")
+	}
+
+	p := New("resize", 80, 24, 0)
+	p.SetSize(132, 43)
+
+	if !p.StartedAt().IsZero() {
+		t.Error("StartedAt() is set before Start")
+	}
+	msg := p.Start("stty", "size")()
+	defer p.Stop()
+	if p.StartedAt().IsZero() {
+		t.Error("StartedAt() is zero after Start")
+	}
+
+	var output strings.Builder
+	for i := 0; i < 100; i++ {
+		out, ok := msg.(OutputMsg)
+		if !ok {
+			break
+		}
+		output.Write(out.Data)
+		msg = p.readOutput()()
+	}
+
+	if got := strings.TrimSpace(output.String()); got != "43 132" {
+		t.Errorf("child saw terminal size %q; want %q", got, "43 132")
+	}
+}
+
+func TestPausedPaneDropsOutput(t *testing.T) {
+	p := New("test", 20, 5, 0)
+	p.vt = vt10x.New(vt10x.WithSize(20, 5))
+	p.scrollback = NewScrollbackBuffer(100)
+
+	p.Pause()
+	p.Update(OutputMsg{PaneID: "test", Data: []byte("hidden")})
+	if strings.Contains(p.GetContent(), "hidden") {
+		t.Error("paused pane processed output")
+	}
+
+	p.Resume()
+	if p.Paused() {
+		t.Error("Paused() = true after Resume")
+	}
+	p.Update(OutputMsg{PaneID: "test", Data: []byte("shown")})
+	if !strings.Contains(p.GetContent(), "shown") {
+		t.Error("resumed pane did not process output")
+	}
+}
+
+func TestPaneGetVisibleText(t *testing.T) {
+	p := New("test", 20, 3, 0)
+	p.vt = vt10x.New(vt10x.WithSize(20, 3))
+	p.scrollback = NewScrollbackBuffer(100)
+	p.scrollback.Push(makeTestLine("older"))
+	p.scrollback.Push(makeTestLine("old   "))
+	p.vt.Write([]byte("live"))
+
+	if got, want := p.GetVisibleText(), "live"; got != want {
+		t.Errorf("GetVisibleText() = %q; want %q", got, want)
+	}
+
+	p.viewportOffset = 2
+	if got, want := p.GetVisibleText(), "older\nold\nlive"; got != want {
+		t.Errorf("GetVisibleText() scrolled back = %q; want %q", got, want)
+	}
+}
+
+func TestPaneLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "ticket.log")
+
+	for _, chunk := range []string{"first run\n", "second run\n"} {
+		p := New("test", 20, 5, 0)
+		p.vt = vt10x.New(vt10x.WithSize(20, 5))
+		p.scrollback = NewScrollbackBuffer(100)
+		p.SetLogFile(path)
+
+		p.Update(OutputMsg{PaneID: "test", Data: []byte(chunk)})
+		p.Update(ExitMsg{PaneID: "test"})
+		if p.logFile != nil {
+			t.Error("log file still open after exit")
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("log not written: %v", err)
+	}
+	if got, want := string(data), "first run\nsecond run\n"; got != want {
+		t.Errorf("log = %q; want %q", got, want)
+	}
+}
+
+func TestPaneLogTimestamps(t *testing.T) {
+	p := New("test", 20, 5, 0)
+	p.SetLogFile(filepath.Join(t.TempDir(), "ticket.log"))
+	p.SetLogTimestamps(true)
+
+	first := time.Date(2024, 6, 1, 14, 3, 27, 0, time.Local)
+	second := first.Add(90 * time.Second)
+	got := string(p.timestampLogUnlocked([]byte("one\ntw"), first)) +
+		string(p.timestampLogUnlocked([]byte("o\nthree\n"), second))
+
+	want := "[2024-06-01 14:03:27] one\n" +
+		"[2024-06-01 14:03:27] two\n" +
+		"[2024-06-01 14:04:57] three\n"
+	if got != want {
+		t.Errorf("timestamped log = %q; want %q", got, want)
+	}
+}
+
+func TestBuildCleanEnvExtra(t *testing.T) {
+	t.Setenv("OPENCODE_TEST_VAR", "stripped")
+	t.Setenv("API_BASE", "inherited")
+
+	env := buildCleanEnv("sess", map[string]string{"API_BASE": "ticket", "FEATURE_X": "1"})
+
+	last := map[string]string{}
+	for _, e := range env {
+		key, value, _ := strings.Cut(e, "=")
+		last[key] = value
+	}
+	if _, ok := last["OPENCODE_TEST_VAR"]; ok {
+		t.Error("agent variable should be stripped")
+	}
+	if last["API_BASE"] != "ticket" {
+		t.Errorf("API_BASE = %q; want extra to override the inherited value", last["API_BASE"])
+	}
+	if last["FEATURE_X"] != "1" {
+		t.Errorf("FEATURE_X = %q; want 1", last["FEATURE_X"])
+	}
+	if last["OPENKANBAN_SESSION"] != "sess" {
+		t.Errorf("OPENKANBAN_SESSION = %q; want sess", last["OPENKANBAN_SESSION"])
+	}
+}
+
+func TestBuildANSIFromSGR(t *testing.T) {
+	tests := []struct {
+		name string
+		sgr  string
+		want string
+	}{
+		{"default colors", "", ""},
+		{"basic fg", "\x1b[31m", "\x1b[31m"},
+		{"basic bg", "\x1b[44m", "\x1b[44m"},
+		{"bright fg", "\x1b[92m", "\x1b[92m"},
+		{"bright bg", "\x1b[103m", "\x1b[103m"},
+		{"bold brightens basic fg", "\x1b[1;31m", "\x1b[91;1m"},
+		{"bold keeps bright fg", "\x1b[1;96m", "\x1b[96;1m"},
+		{"256 palette low entry", "\x1b[38;5;1m", "\x1b[31m"},
+		{"256 palette", "\x1b[38;5;208;48;5;236m", "\x1b[38;5;208;48;5;236m"},
+		{"true color", "\x1b[38;2;255;128;0m", "\x1b[38;2;255;128;0m"},
+		{"reverse default colors", "\x1b[7m", "\x1b[7m"},
+		{"reverse with colors", "\x1b[31;44;7m", "\x1b[31;44;7m"},
+		{"reset after color", "\x1b[31m\x1b[0m", ""},
+		{"fg reset keeps bg", "\x1b[31;42m\x1b[39m", "\x1b[42m"},
+		{"italic underline blink", "\x1b[3;4;5m", "\x1b[3;4;5m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vt := vt10x.New(vt10x.WithSize(10, 2))
+			if _, err := vt.Write([]byte(tt.sgr + "x")); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			glyph := vt.Cell(0, 0)
+			if got := buildANSI(glyph.FG, glyph.BG, glyph.Mode); got != tt.want {
+				t.Errorf("buildANSI() after %q = %q; want %q", tt.sgr, got, tt.want)
+			}
+		})
+	}
+}
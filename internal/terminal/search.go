@@ -0,0 +1,188 @@
+package terminal
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/hinshun/vt10x"
+)
+
+// Search finds the scrollback lines containing query, ignoring case, and
+// scrolls to the newest one. It returns the matching line indices (0 = oldest
+// line in the scrollback). An empty query clears the search.
+func (p *Pane) Search(query string) []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.searchQuery = query
+	p.searchMatches = p.findMatchesUnlocked()
+	p.searchCurrent = len(p.searchMatches) - 1
+	if p.searchCurrent >= 0 {
+		p.scrollToMatchUnlocked()
+	}
+	p.dirty = true
+	return p.searchMatches
+}
+
+// SearchNext scrolls to the match above the current one, wrapping around to
+// the newest. It returns the 1-based number of the match now shown, counted
+// from the oldest, and the number of matches.
+func (p *Pane) SearchNext() (current, total int) {
+	return p.stepSearch(-1)
+}
+
+// SearchPrev scrolls to the match below the current one, wrapping around to
+// the oldest.
+func (p *Pane) SearchPrev() (current, total int) {
+	return p.stepSearch(1)
+}
+
+// SearchQuery returns the active search, or "" when there is none.
+func (p *Pane) SearchQuery() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.searchQuery
+}
+
+// SearchPosition returns the 1-based number of the match scrolled to and
+// the number of matches, or 0, 0 without any.
+func (p *Pane) SearchPosition() (current, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.searchCurrent + 1, len(p.searchMatches)
+}
+
+// ClearSearch drops the search and its highlighting.
+func (p *Pane) ClearSearch() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clearSearchUnlocked()
+}
+
+func (p *Pane) clearSearchUnlocked() {
+	if p.searchQuery == "" {
+		return
+	}
+	p.searchQuery = ""
+	p.searchMatches = nil
+	p.searchCurrent = -1
+	p.dirty = true
+}
+
+func (p *Pane) stepSearch(delta int) (current, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.searchQuery == "" {
+		return 0, 0
+	}
+
+	// Output may have arrived since the last step; find matches again and
+	// continue from the line that was current.
+	line := -1
+	if p.searchCurrent >= 0 && p.searchCurrent < len(p.searchMatches) {
+		line = p.searchMatches[p.searchCurrent]
+	}
+	p.searchMatches = p.findMatchesUnlocked()
+	if len(p.searchMatches) == 0 {
+		p.searchCurrent = -1
+		p.dirty = true
+		return 0, 0
+	}
+
+	next := -1
+	if delta < 0 {
+		for i := len(p.searchMatches) - 1; i >= 0; i-- {
+			if p.searchMatches[i] < line {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			next = len(p.searchMatches) - 1
+		}
+	} else {
+		for i, idx := range p.searchMatches {
+			if idx > line {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			next = 0
+		}
+	}
+	p.searchCurrent = next
+	p.scrollToMatchUnlocked()
+	return next + 1, len(p.searchMatches)
+}
+
+// findMatchesUnlocked must be called with mu held.
+func (p *Pane) findMatchesUnlocked() []int {
+	if p.searchQuery == "" || p.scrollback == nil {
+		return nil
+	}
+	query := strings.ToLower(p.searchQuery)
+
+	var matches []int
+	for i, line := range p.scrollback.GetRange(0, p.scrollback.Len()) {
+		if strings.Contains(strings.ToLower(glyphText(line)), query) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// scrollToMatchUnlocked puts the current match a third of the way down the
+// viewport. Must be called with mu held.
+func (p *Pane) scrollToMatchUnlocked() {
+	line := p.searchMatches[p.searchCurrent]
+	offset := p.scrollback.Len() - line + p.height/3
+	p.viewportOffset = min(max(offset, 1), p.scrollback.Len())
+	p.dirty = true
+}
+
+// searchColumnsUnlocked returns which of line's cells are part of a match
+// of the active search, or nil when none are.
+func (p *Pane) searchColumnsUnlocked(line []vt10x.Glyph) []bool {
+	if p.searchQuery == "" || len(line) == 0 {
+		return nil
+	}
+
+	runes := []rune(glyphText(line))
+	query := []rune(strings.ToLower(p.searchQuery))
+	var cols []bool
+	for start := 0; start+len(query) <= len(runes); start++ {
+		matched := true
+		for j, r := range query {
+			if unicode.ToLower(runes[start+j]) != r {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		if cols == nil {
+			cols = make([]bool, len(runes))
+		}
+		for j := range query {
+			cols[start+j] = true
+		}
+	}
+	return cols
+}
+
+// glyphText returns a line's characters, one rune per cell, with empty
+// cells as spaces.
+func glyphText(line []vt10x.Glyph) string {
+	var b strings.Builder
+	for _, g := range line {
+		if g.Char == 0 {
+			b.WriteRune(' ')
+		} else {
+			b.WriteRune(g.Char)
+		}
+	}
+	return b.String()
+}
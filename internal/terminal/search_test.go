@@ -0,0 +1,71 @@
+package terminal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newSearchTestPane(lines ...string) *Pane {
+	p := New("test", 20, 6, 0)
+	p.scrollback = NewScrollbackBuffer(100)
+	for _, line := range lines {
+		p.scrollback.Push(makeTestLine(line))
+	}
+	return p
+}
+
+func TestPaneSearch(t *testing.T) {
+	p := newSearchTestPane(
+		"building",
+		"Error: disk full",
+		"retrying",
+		"error: timeout",
+		"done",
+	)
+
+	matches := p.Search("ERROR")
+	if want := []int{1, 3}; !reflect.DeepEqual(matches, want) {
+		t.Fatalf("Search() = %v; want %v", matches, want)
+	}
+	// The newest match is shown a third of the way down the viewport.
+	if got, want := p.ViewportOffset(), 5-3+6/3; got != want {
+		t.Errorf("ViewportOffset() after Search = %d; want %d", got, want)
+	}
+
+	if current, total := p.SearchNext(); current != 1 || total != 2 {
+		t.Errorf("SearchNext() = %d/%d; want 1/2", current, total)
+	}
+	if current, _ := p.SearchNext(); current != 2 {
+		t.Errorf("SearchNext() did not wrap to the newest match, got %d", current)
+	}
+	if current, _ := p.SearchPrev(); current != 1 {
+		t.Errorf("SearchPrev() did not wrap to the oldest match, got %d", current)
+	}
+
+	if matches := p.Search("missing"); len(matches) != 0 {
+		t.Errorf("Search(missing) = %v; want none", matches)
+	}
+	if current, total := p.SearchNext(); current != 0 || total != 0 {
+		t.Errorf("SearchNext() without matches = %d/%d; want 0/0", current, total)
+	}
+
+	p.Search("done")
+	p.ClearSearch()
+	if p.SearchQuery() != "" {
+		t.Error("ClearSearch() left the query set")
+	}
+}
+
+func TestPaneSearchColumns(t *testing.T) {
+	p := newSearchTestPane()
+	p.searchQuery = "ab"
+
+	got := p.searchColumnsUnlocked(makeTestLine("xAbyab"))
+	want := []bool{false, true, true, false, true, true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("searchColumnsUnlocked() = %v; want %v", got, want)
+	}
+	if cols := p.searchColumnsUnlocked(makeTestLine("none")); cols != nil {
+		t.Errorf("searchColumnsUnlocked() without a match = %v; want nil", cols)
+	}
+}
@@ -1,63 +1,108 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/techdufus/openkanban/internal/agent"
 	"github.com/techdufus/openkanban/internal/board"
 	"github.com/techdufus/openkanban/internal/config"
 	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/notify"
 	"github.com/techdufus/openkanban/internal/project"
 	"github.com/techdufus/openkanban/internal/terminal"
 	"github.com/techdufus/openkanban/internal/update"
 )
 
-const agentPortBase = 4097
+// diffRefreshInterval controls how often the agent view diff split re-reads
+// the worktree's git diff.
+const diffRefreshInterval = 2 * time.Second
+
+// orphanedColumnID identifies the column that collects orphaned tickets when
+// the board is grouped by project.
+const orphanedColumnID = "orphaned"
+
+var errOrphanedTicket = errors.New("ticket's project is no longer registered; press e to reassign it")
 
 type Mode string
 
 const (
-	ModeNormal        Mode = "NORMAL"
-	ModeInsert        Mode = "INSERT"
-	ModeCommand       Mode = "COMMAND"
-	ModeHelp          Mode = "HELP"
-	ModeConfirm       Mode = "CONFIRM"
-	ModeCreateTicket  Mode = "CREATE"
-	ModeEditTicket    Mode = "EDIT"
-	ModeAgentView     Mode = "AGENT"
-	ModeSettings      Mode = "SETTINGS"
-	ModeShuttingDown  Mode = "SHUTTING_DOWN"
-	ModeSpawning      Mode = "SPAWNING"
-	ModeFilter        Mode = "FILTER"
-	ModeCreateProject Mode = "NEW_PROJECT"
+	ModeNormal          Mode = "NORMAL"
+	ModeInsert          Mode = "INSERT"
+	ModeCommand         Mode = "COMMAND"
+	ModeHelp            Mode = "HELP"
+	ModeConfirm         Mode = "CONFIRM"
+	ModeCreateTicket    Mode = "CREATE"
+	ModeEditTicket      Mode = "EDIT"
+	ModeAgentView       Mode = "AGENT"
+	ModeSettings        Mode = "SETTINGS"
+	ModeShuttingDown    Mode = "SHUTTING_DOWN"
+	ModeSpawning        Mode = "SPAWNING"
+	ModeFilter          Mode = "FILTER"
+	ModeCreateProject   Mode = "NEW_PROJECT"
+	ModeOnboarding      Mode = "WELCOME"
+	ModeBranchPreview   Mode = "BRANCH"
+	ModeWorktrees       Mode = "WORKTREES"
+	ModeSavedFilters    Mode = "FILTERS"
+	ModeEffectiveConfig Mode = "CONFIG"
+	ModeAgentPicker     Mode = "AGENT_TYPE"
+	ModeSpawnPrompt     Mode = "PROMPT"
+	ModeConfigHealth    Mode = "HEALTH"
+	ModeDashboard       Mode = "DASHBOARD"
+)
+
+// Onboarding steps shown on first run, before any project is registered.
+const (
+	onboardingProject = iota
+	onboardingAgent
 )
 
 const (
 	minColumnWidth = 20
 	columnOverhead = 5
 
-	ticketHeight       = 6
-	columnHeaderHeight = 3
-
-	formFieldTitle       = 0
-	formFieldDescription = 1
-	formFieldBranch      = 2
-	formFieldLabels      = 3
-	formFieldPriority    = 4
-	formFieldWorktree    = 5
-	formFieldAgent       = 6
-	formFieldBlockedBy   = 7
-	formFieldProject     = 8
+	ticketHeight        = 6
+	compactTicketHeight = 1
+	columnHeaderHeight  = 3
+
+	// Terminals narrower or shorter than this get compact cards.
+	compactBelowWidth  = 60
+	compactBelowHeight = 24
+
+	formFieldTitle        = 0
+	formFieldDescription  = 1
+	formFieldBranch       = 2
+	formFieldBaseBranch   = 3
+	formFieldLabels       = 4
+	formFieldPriority     = 5
+	formFieldDueDate      = 6
+	formFieldWorktree     = 7
+	formFieldAgent        = 8
+	formFieldAgentArgs    = 9
+	formFieldContextFiles = 10
+	formFieldBlockedBy    = 11
+	formFieldProject      = 12
+	formFieldWorktreePath = 13
 )
 
 type Model struct {
@@ -69,6 +114,7 @@ type Model struct {
 	projectRegistry  *project.ProjectRegistry
 	columns          []board.Column
 	filterProjectIDs map[string]bool
+	filterOrphaned   bool
 
 	worktreeMgrs   map[string]*git.WorktreeManager
 	agentMgr       *agent.Manager
@@ -96,30 +142,81 @@ type Model struct {
 	lastClickTicket int
 
 	columnTickets [][]*board.Ticket
+	// columnWIP is the WIP count and limit each column's header shows.
+	columnWIP []wipUsage
 
 	showHelp    bool
 	showConfirm bool
 	confirmMsg  string
 	confirmFn   func() tea.Cmd
+	// confirmYes is true when the Yes button is highlighted; dialogs open
+	// with No highlighted.
+	confirmYes bool
+	// quitChecking is set while quitting waits for the worktrees' git
+	// status.
+	quitChecking bool
+
+	// removingProject is set while the removal dialog asks what to do with
+	// the project's tickets; removeTargetIndex picks the reassignment target.
+	removingProject   *project.Project
+	removeTargetIndex int
 
 	titleInput         textinput.Model
 	descInput          textarea.Model
 	branchInput        textinput.Model
+	baseBranchInput    textinput.Model
 	labelsInput        textinput.Model
 	ticketPriority     int
+	dueInput           textinput.Model
 	ticketUseWorktree  bool
 	ticketAgent        string
 	agentListIndex     int
+	agentArgsInput     textinput.Model
+	contextFilesInput  textinput.Model
 	projectInput       textinput.Model
+	worktreePathInput  textinput.Model
+	worktreePathLocked bool
 	ticketFormField    int
 	editingTicketID    board.TicketID
 	branchLocked       bool
 	agentLocked        bool
+	reassignProject    bool
 	selectedProject    *project.Project
 	projectListIndex   int
 	showAddProjectForm bool
 	addProjectPath     textinput.Model
 
+	// baseBranchPrefill is the value the base branch field was filled with
+	// for baseBranchProjectID; it is refilled when the form's project
+	// changes unless edited.
+	baseBranchPrefill   string
+	baseBranchProjectID string
+
+	// linkedBranch is set while a new ticket's Branch field holds an
+	// existing branch picked with ctrl+b from branchOptions, which the
+	// ticket's worktree checks out instead of creating a new branch.
+	// branchOptions and remoteBranches are the local and remote-tracking
+	// branches of branchOptionsProject, loaded in the background while the
+	// ticket form is open; the form checks branch names against them.
+	// branchOptionsLoading is the project being loaded.
+	linkedBranch         bool
+	branchOptions        []string
+	remoteBranches       []string
+	branchOptionIndex    int
+	branchOptionsProject string
+	branchOptionsLoading string
+	branchOptionsErr     error
+
+	// ticketTemplateIndex is the description template last applied with
+	// ctrl+t in the create form, -1 for none; ticketTemplateBody is what it
+	// filled in, so edits to it aren't overwritten.
+	ticketTemplateIndex int
+	ticketTemplateBody  string
+
+	// copySettingsIndex picks the project whose settings a new project
+	// copies: 0 for none, otherwise an index into Projects() plus one.
+	copySettingsIndex int
+
 	blockerCandidates  []*board.Ticket
 	selectedBlockers   map[board.TicketID]bool
 	blockerListIndex   int
@@ -134,10 +231,118 @@ type Model struct {
 	panes          map[board.TicketID]*terminal.Pane
 	focusedPane    board.TicketID
 	statusDetector *agent.StatusDetector
+	statusSmoother *agent.StatusSmoother
+
+	// desktopNotified is when each ticket last raised a desktop
+	// notification, so a status flapping between polls doesn't repeat it.
+	desktopNotified map[board.TicketID]time.Time
+
+	// editorPanes hold editors opened on ticket worktrees with o. The agent
+	// view shows them like agents, but they never count as agents.
+	editorPanes map[board.TicketID]*terminal.Pane
+	// quickAgents are the quick-question agents started with c, keyed by
+	// their pane ID (quickPanePrefix plus project ID).
+	quickAgents map[board.TicketID]*quickAgent
+	// agentSearching is set while a scrollback search is typed into
+	// agentSearchInput in the agent view.
+	agentSearching   bool
+	agentSearchInput textinput.Model
+
+	// agentPickerIndex is the highlighted agent in the agent type picker.
+	agentPickerIndex int
+
+	// effectiveConfig is what the effective config view shows, resolved
+	// when it opens; effectiveConfigScroll is the first line shown.
+	effectiveConfig       effectiveConfig
+	effectiveConfigScroll int
+
+	// configHealth is the result of the last config validation, of the
+	// file at configPath; configHealthScroll is the first line shown.
+	configPath         string
+	configHealth       *config.ValidationResult
+	configHealthScroll int
+
+	// dashboardTicket is the ticket whose agent is highlighted in the agent
+	// dashboard, whose rows are rebuilt from panes on every render.
+	dashboardTicket board.TicketID
+	// lastSpawns records how each ticket's most recent agent was started,
+	// so R can replay it verbatim.
+	lastSpawns map[board.TicketID]spawnRecord
+	// focusedEditor is set while the agent view shows focusedPane's editor
+	// rather than its agent.
+	focusedEditor bool
+
+	diffSplit   bool
+	diffTicking bool
+	diffContent string
+	diffErr     error
+
+	// gitOps counts background git operations shown in the status bar.
+	gitOps           int
+	gitOpLabel       string
+	pendingWorktrees map[board.TicketID]bool
+
+	// bulkSpawns maps tickets started by confirmBulkSpawn to their agent
+	// type until their spawn finishes; bulkFailures collects the errors.
+	bulkSpawns   map[board.TicketID]string
+	bulkStarted  int
+	bulkFailures []string
+
+	// filterRegistry holds the saved filters listed with F; savedFilter is
+	// the one applied, narrowing the board by status and label on top of
+	// the project and query filters it set.
+	filterRegistry   *project.FilterRegistry
+	savedFilter      *project.SavedFilter
+	savedFilterIndex int
+	filterNaming     bool
+	filterNameInput  textinput.Model
+
+	// worktreeProject is the project shown in the worktrees view; its rows
+	// are filled in by loadWorktreesAsync.
+	worktreeProject *project.Project
+	worktreeRows    []worktreeRow
+	worktreeIndex   int
+	worktreeLoading bool
+	worktreeErr     error
+
+	// spawnQueue holds tickets waiting for a free agent slot when
+	// behavior.max_concurrent_agents is reached, oldest first.
+	spawnQueue []board.TicketID
+	// restarting holds tickets whose agent ctrl+r stopped; each is queued
+	// once its old pane has exited.
+	restarting map[board.TicketID]bool
+
+	// undoStack records recent moves and deletes, newest last, so u can
+	// revert them.
+	undoStack []undoAction
+
+	// branchDrift caches ahead/behind counts for in-progress worktrees,
+	// refreshed on every status poll.
+	branchDrift map[board.TicketID]branchDrift
+
+	// lastPolled is when each pane's agent status was last checked, so
+	// agents with their own poll_interval_seconds are polled less often.
+	lastPolled map[board.TicketID]time.Time
+
+	// workSummary is the focused pane's uncommitted file and commit counts
+	// shown in the agent view header.
+	workSummary workSummaryMsg
 
 	spawningTicketID board.TicketID
 	spawningAgent    string
 
+	commandInput textinput.Model
+
+	branchPreviewInput    textinput.Model
+	branchPreviewTicketID board.TicketID
+
+	// spawnPromptInput edits the init prompt shown before a spawn
+	// (behavior.confirm_spawn_prompt) for pendingSpawnPrompt. spawnPrompts
+	// holds confirmed prompts, used as-is by the ticket's next spawn.
+	spawnPromptInput   textarea.Model
+	pendingSpawnPrompt *spawnTarget
+	spawnPrompts       map[board.TicketID]string
+
 	settingsIndex   int
 	settingsEditing bool
 	settingsInput   textinput.Model
@@ -151,10 +356,46 @@ type Model struct {
 	sidebarIndex   int
 	sidebarWidth   int
 
+	// sidebarFilter narrows the sidebar to projects whose name contains
+	// its value. sidebarFiltering is set while it is being typed.
+	sidebarFilter    textinput.Model
+	sidebarFiltering bool
+
 	updateChecker *update.Checker
+
+	// opencodeStatus is the shared opencode server's last checked health;
+	// opencodeChecking is set while a check is running.
+	opencodeStatus   opencodeServerMsg
+	opencodeChecking bool
+
+	// keymap is the board action bound to each key (config keys).
+	keymap map[string]string
+
+	// readOnly disables every action that changes tickets, projects,
+	// worktrees or config, or starts a process (--read-only).
+	readOnly bool
+
+	onboardingStep       int
+	onboardingAgentIndex int
+	// installedAgents is which configured agents' commands are on PATH,
+	// detected once at startup for the onboarding agent picker.
+	installedAgents map[string]bool
+
+	// groupByProject swaps the status columns for one column per project.
+	// statusColumns keeps the regular layout for when it is toggled back.
+	groupByProject bool
+	statusColumns  []board.Column
+
+	// selectedTickets are the tickets marked with v. While any are marked,
+	// move, delete, spawn and :label act on all of them.
+	selectedTickets map[board.TicketID]bool
+
+	// hideStatusDetail shrinks each card's agent badge and status text to a
+	// colored dot. It is not saved.
+	hideStatusDetail bool
 }
 
-func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projectRegistry *project.ProjectRegistry, agentMgr *agent.Manager, opencodeServer *agent.OpencodeServer, filterProjectID string, updateChecker *update.Checker) *Model {
+func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projectRegistry *project.ProjectRegistry, agentMgr *agent.Manager, opencodeServer *agent.OpencodeServer, filterProjectID string, updateChecker *update.Checker, readOnly bool) *Model {
 	ti := textinput.New()
 	ti.Placeholder = "Enter ticket title..."
 	ti.CharLimit = 100
@@ -167,16 +408,31 @@ func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projec
 	di.SetHeight(4)
 	di.ShowLineNumbers = false
 
+	pp := textarea.New()
+	pp.Placeholder = "Empty starts the agent without a prompt"
+	pp.CharLimit = 0
+	pp.ShowLineNumbers = false
+
 	bi := textinput.New()
 	bi.Placeholder = "Auto-generated from title..."
 	bi.CharLimit = 100
 	bi.Width = 40
 
+	bb := textinput.New()
+	bb.Placeholder = "Project's default branch"
+	bb.CharLimit = 100
+	bb.Width = 40
+
 	li := textinput.New()
 	li.Placeholder = "bug, urgent, frontend (comma-separated)"
 	li.CharLimit = 200
 	li.Width = 40
 
+	du := textinput.New()
+	du.Placeholder = "2024-06-01 or +3d (optional)"
+	du.CharLimit = 20
+	du.Width = 40
+
 	pi := textinput.New()
 	pi.Placeholder = "Select project..."
 	pi.CharLimit = 100
@@ -201,6 +457,49 @@ func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projec
 	bf.CharLimit = 100
 	bf.Width = 30
 
+	ci := textinput.New()
+	ci.Prompt = ":"
+	ci.Placeholder = "archive, move <column>, spawn [agent], attach <title>, delete, filter [query], quit"
+	ci.CharLimit = 256
+	ci.Width = 60
+
+	bp := textinput.New()
+	bp.Prompt = "Branch: "
+	bp.CharLimit = 200
+	bp.Width = 50
+
+	aa := textinput.New()
+	aa.Placeholder = "--model opus --max-turns 20"
+	aa.CharLimit = 256
+	aa.Width = 40
+
+	fn := textinput.New()
+	fn.Placeholder = "my bugs"
+	fn.CharLimit = 50
+	fn.Width = 30
+
+	cf := textinput.New()
+	cf.Placeholder = "src/auth.go, docs/spec.md"
+	cf.CharLimit = 512
+	cf.Width = 40
+
+	wp := textinput.New()
+	wp.Placeholder = "/path/to/existing/worktree"
+	wp.CharLimit = 256
+	wp.Width = 40
+
+	as := textinput.New()
+	as.Prompt = "/"
+	as.Placeholder = "search scrollback"
+	as.CharLimit = 200
+	as.Width = 30
+
+	sf := textinput.New()
+	sf.Prompt = "/"
+	sf.Placeholder = "filter"
+	sf.CharLimit = 100
+	sf.Width = 18
+
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
 
@@ -228,7 +527,12 @@ func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projec
 		globalStore:        globalStore,
 		projectRegistry:    projectRegistry,
 		columns:            board.DefaultColumns(),
+		statusColumns:      board.DefaultColumns(),
 		filterProjectIDs:   make(map[string]bool),
+		pendingWorktrees:   make(map[board.TicketID]bool),
+		bulkSpawns:         make(map[board.TicketID]string),
+		branchDrift:        make(map[board.TicketID]branchDrift),
+		lastPolled:         make(map[board.TicketID]time.Time),
 		worktreeMgrs:       worktreeMgrs,
 		agentMgr:           agentMgr,
 		opencodeServer:     opencodeServer,
@@ -236,9 +540,20 @@ func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projec
 		titleInput:         ti,
 		descInput:          di,
 		branchInput:        bi,
+		baseBranchInput:    bb,
 		labelsInput:        li,
 		ticketPriority:     3,
+		dueInput:           du,
 		projectInput:       pi,
+		worktreePathInput:  wp,
+		agentArgsInput:     aa,
+		contextFilesInput:  cf,
+		filterNameInput:    fn,
+		commandInput:       ci,
+		branchPreviewInput: bp,
+		spawnPromptInput:   pp,
+		spawnPrompts:       make(map[board.TicketID]string),
+		keymap:             cfg.KeyBindings(),
 		settingsInput:      si,
 		filterInput:        fi,
 		addProjectPath:     ap,
@@ -247,17 +562,46 @@ func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projec
 		formFieldLines:     make(map[int]int),
 		spinner:            sp,
 		panes:              make(map[board.TicketID]*terminal.Pane),
+		editorPanes:        make(map[board.TicketID]*terminal.Pane),
+		quickAgents:        make(map[board.TicketID]*quickAgent),
+		lastSpawns:         make(map[board.TicketID]spawnRecord),
+		restarting:         make(map[board.TicketID]bool),
+		selectedTickets:    make(map[board.TicketID]bool),
 		statusDetector:     agent.NewStatusDetector(),
+		statusSmoother:     agent.NewStatusSmoother(cfg.Behavior.StatusStablePolls),
+		desktopNotified:    make(map[board.TicketID]time.Time),
 		selectedProject:    selectedProject,
 		sidebarVisible:     cfg.UI.SidebarVisible,
 		sidebarWidth:       24,
+		sidebarFilter:      sf,
+		agentSearchInput:   as,
 		hoverColumn:        -1,
 		hoverTicket:        -1,
 		updateChecker:      updateChecker,
+		readOnly:           readOnly,
 	}
 	if filterProjectID != "" {
 		m.filterProjectIDs[filterProjectID] = true
 	}
+	if reg, err := project.LoadFilterRegistry(); err != nil {
+		m.notify("Failed to load saved filters: " + err.Error())
+	} else {
+		m.filterRegistry = reg
+		if def := reg.GetDefault(); def != nil && filterProjectID == "" {
+			m.applySavedFilter(def)
+		}
+	}
+	m.statusDetector.SetStatusPatterns(cfg.Agents)
+	for _, p := range projectRegistry.List() {
+		if err := p.ValidateSettings(); err != nil {
+			m.notify("Project " + p.Name + " uses defaults for invalid settings: " + strings.ReplaceAll(err.Error(), "\n", "; "))
+		}
+	}
+	if !globalStore.HasProjects() && !readOnly {
+		m.mode = ModeOnboarding
+		m.onboardingStep = onboardingProject
+		m.addProjectPath.Focus()
+	}
 
 	// Reset all agent statuses on startup since there are no active sessions yet.
 	// This prevents stale "working" statuses from persisting after app restart.
@@ -277,6 +621,7 @@ func (m *Model) Init() tea.Cmd {
 		tickAgentStatus(m.agentMgr.StatusPollInterval()),
 		m.spinner.Tick,
 		m.checkForUpdates(),
+		m.detectInstalledAgents(),
 	)
 }
 
@@ -290,6 +635,12 @@ func (m *Model) checkForUpdates() tea.Cmd {
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(ShutdownMsg); ok && m.mode != ModeShuttingDown {
+		m.closeConfirm()
+		m.showHelp = false
+		m.mode = ModeShuttingDown
+		return m, tea.Batch(m.spinner.Tick, m.cleanupAsync())
+	}
 	if m.mode == ModeShuttingDown {
 		switch msg := msg.(type) {
 		case shutdownCompleteMsg:
@@ -305,34 +656,47 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.mode == ModeSpawning {
 		switch msg := msg.(type) {
 		case agentStatusMsg:
+			m.wakeSnoozedTickets()
 			return m, tea.Batch(
 				m.pollAgentStatusesAsync(),
+				m.pollBranchDriftAsync(),
+				m.checkOpencodeServerAsync(),
 				tickAgentStatus(m.agentMgr.StatusPollInterval()),
 			)
+		case branchDriftResultMsg:
+			m.branchDrift = msg
+			return m, nil
+		case opencodeServerMsg:
+			m.handleOpencodeServer(msg)
+			return m, nil
+		case quitCheckMsg:
+			// A spawn started meanwhile; the quit is abandoned.
+			m.quitChecking = false
+			return m, nil
+		case worktreesLoadedMsg:
+			m.handleWorktreesLoaded(msg)
+			return m, nil
+		case worktreeRemovedMsg:
+			return m, m.handleWorktreeRemoved(msg)
 		case spawnReadyMsg:
 			if msg.ticketID != m.spawningTicketID {
-				return m, nil
-			}
-
-			ticket, _ := m.globalStore.Get(msg.ticketID)
-			if ticket != nil {
-				ticket.AgentType = m.spawningAgent
-				ticket.AgentStatus = board.AgentNone
-				if ticket.AgentSpawnedAt == nil {
-					now := time.Now()
-					ticket.AgentSpawnedAt = &now
-				}
-				if msg.worktreePath != "" && ticket.WorktreePath == "" {
-					ticket.WorktreePath = msg.worktreePath
-					ticket.BranchName = msg.branchName
-					ticket.BaseBranch = msg.baseBranch
+				if _, ok := m.bulkSpawns[msg.ticketID]; ok {
+					return m, m.handleBulkSpawnReady(msg)
 				}
-				m.saveTicket(ticket)
+				return m, nil
 			}
 
-			m.panes[msg.ticketID] = msg.pane
 			m.focusedPane = msg.ticketID
-			return m, msg.pane.Start(msg.command, msg.args...)
+			m.focusedEditor = false
+			return m, m.applySpawnReady(msg, m.spawningAgent)
+
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+			if pane, ok := m.panes[m.spawningTicketID]; ok {
+				pane.SetSize(m.agentPaneSize())
+			}
+			return m, nil
 
 		case spawnErrorMsg:
 			if msg.ticketID == m.spawningTicketID {
@@ -340,18 +704,72 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.spawningTicketID = ""
 				m.spawningAgent = ""
 				m.notify(msg.err)
+			} else if _, ok := m.bulkSpawns[msg.ticketID]; ok {
+				m.handleBulkSpawnError(msg)
 			}
-			return m, nil
+			return m, m.startQueuedSpawns()
 
 		case terminal.OutputMsg:
+			var diffCmd tea.Cmd
 			if board.TicketID(msg.PaneID) == m.spawningTicketID {
 				m.mode = ModeAgentView
 				m.spawningTicketID = ""
 				m.spawningAgent = ""
+				diffCmd = m.startDiffRefresh()
 			}
-			return m.handleTerminalMsg(msg)
+			_, cmd := m.handleTerminalMsg(msg)
+			return m, tea.Batch(cmd, diffCmd)
+
+		case diffTickMsg, diffResultMsg:
+			m.diffTicking = false
+			return m, nil
+
+		case worktreeCreatedMsg:
+			return m, m.handleWorktreeCreated(msg)
+
+		case abandonedWorktreeMsg:
+			m.handleAbandonedWorktree(msg)
+			return m, nil
+
+		case ticketCleanupMsg:
+			m.handleTicketCleanup(msg)
+			return m, nil
+
+		case branchMergedMsg:
+			m.handleBranchMerged(msg)
+			return m, nil
+
+		case pullRequestMsg:
+			m.handlePullRequest(msg)
+			return m, nil
+
+		case branchRebasedMsg:
+			m.handleBranchRebased(msg)
+			return m, nil
+
+		case worktreeCommittedMsg:
+			m.handleWorktreeCommitted(msg)
+			return m, nil
+
+		case branchRenamedMsg:
+			m.handleBranchRenamed(msg)
+			return m, nil
+
+		case deleteMarkedCheckMsg:
+			m.confirmDeleteMarked(msg)
+			return m, nil
+
+		case deleteCheckMsg:
+			m.confirmDelete(msg)
+			return m, nil
 
 		case terminal.ExitMsg:
+			if m.handleEditorExit(msg) || m.handleQuickAgentExit(msg) {
+				return m, nil
+			}
+			if m.queueRestart(board.TicketID(msg.PaneID)) {
+				return m, m.startQueuedSpawns()
+			}
 			if board.TicketID(msg.PaneID) == m.spawningTicketID {
 				m.resetSpawnState(board.TicketID(msg.PaneID))
 				if msg.Err != nil {
@@ -360,7 +778,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.notify("Agent exited unexpectedly")
 				}
 			}
-			return m, nil
+			return m, m.startQueuedSpawns()
 
 		case spinner.TickMsg:
 			var cmd tea.Cmd
@@ -391,8 +809,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		if m.focusedPane != "" {
-			if pane, ok := m.panes[m.focusedPane]; ok {
-				pane.SetSize(m.width, m.height-2)
+			if pane, ok := m.focusedTerminal(); ok {
+				pane.SetSize(m.agentPaneSize())
 			}
 		}
 		return m, nil
@@ -405,7 +823,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleAgentViewMouse(msg)
 		}
 		if m.mode == ModeCreateTicket || m.mode == ModeEditTicket {
-			return m.handleTicketFormMouse(msg)
+			model, cmd := m.handleTicketFormMouse(msg)
+			return model, tea.Batch(cmd, m.loadFormBranches())
 		}
 		if m.mode == ModeFilter {
 			return m.handleFilterMouse(msg)
@@ -419,6 +838,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+		if m.removingProject != nil {
+			return m, nil
+		}
 		if m.showConfirm {
 			return m.handleConfirmMouse(msg)
 		}
@@ -428,48 +850,164 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleTerminalMsg(msg)
 
 	case terminal.ExitMsg:
+		if m.handleEditorExit(msg) || m.handleQuickAgentExit(msg) {
+			return m, nil
+		}
 		ticketID := board.TicketID(msg.PaneID)
+		if m.queueRestart(ticketID) {
+			return m, m.startQueuedSpawns()
+		}
 		delete(m.panes, ticketID)
 		if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
 			ticket.AgentStatus = board.AgentNone
 			m.saveTicket(ticket)
 		}
-		if m.focusedPane == ticketID {
+		if m.focusedPane == ticketID && !m.focusedEditor {
 			m.mode = ModeNormal
 			m.focusedPane = ""
 			m.notify("Agent exited")
 		}
-		return m, nil
+		return m, m.startQueuedSpawns()
 
 	case terminal.ExitFocusMsg:
 		m.mode = ModeNormal
 		m.focusedPane = ""
 		return m, nil
 
+	case diffTickMsg:
+		if !m.diffSplit || m.mode != ModeAgentView || m.focusedPane == "" {
+			m.diffTicking = false
+			return m, nil
+		}
+		return m, m.refreshDiffAsync()
+
+	case diffResultMsg:
+		if msg.ticketID == m.focusedPane {
+			m.diffContent = msg.content
+			m.diffErr = msg.err
+		}
+		return m, tickDiff()
+
 	case agentStatusMsg:
+		m.wakeSnoozedTickets()
 		return m, tea.Batch(
 			m.pollAgentStatusesAsync(),
+			m.pollBranchDriftAsync(),
+			m.pollWorkSummaryAsync(),
+			m.checkOpencodeServerAsync(),
 			tickAgentStatus(m.agentMgr.StatusPollInterval()),
 		)
 
+	case branchDriftResultMsg:
+		m.branchDrift = msg
+
+	case opencodeServerMsg:
+		m.handleOpencodeServer(msg)
+
+	case quitCheckMsg:
+		return m, m.confirmQuit(msg.dirty)
+
+	case workSummaryMsg:
+		m.workSummary = msg
+
+	case worktreesLoadedMsg:
+		m.handleWorktreesLoaded(msg)
+		return m, nil
+
+	case worktreeRemovedMsg:
+		return m, m.handleWorktreeRemoved(msg)
+
 	case agentStatusResultMsg:
+		var cmds []tea.Cmd
 		for ticketID, status := range msg {
 			if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
+				previous := ticket.AgentStatus
+				status = m.statusSmoother.Observe(ticketID, status)
 				ticket.AgentStatus = status
+				if status == board.AgentCompleted && previous != board.AgentCompleted {
+					m.handleAgentCompleted(ticket)
+				}
+				if status != previous {
+					cmds = append(cmds, m.desktopNotify(ticket), m.alertSound(ticket))
+				}
 			}
 		}
+		return m, tea.Batch(cmds...)
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 
+	case worktreeCreatedMsg:
+		return m, m.handleWorktreeCreated(msg)
+
+	case abandonedWorktreeMsg:
+		m.handleAbandonedWorktree(msg)
+		return m, nil
+
+	case ticketCleanupMsg:
+		m.handleTicketCleanup(msg)
+		return m, nil
+
+	case branchMergedMsg:
+		m.handleBranchMerged(msg)
+		return m, nil
+
+	case pullRequestMsg:
+		m.handlePullRequest(msg)
+		return m, nil
+
+	case branchRebasedMsg:
+		m.handleBranchRebased(msg)
+		return m, nil
+
+	case worktreeCommittedMsg:
+		m.handleWorktreeCommitted(msg)
+		return m, nil
+
+	case branchRenamableMsg:
+		m.handleBranchRenamable(msg)
+		return m, nil
+
+	case branchListMsg:
+		m.handleBranchList(msg)
+		return m, nil
+
+	case deleteMarkedCheckMsg:
+		m.confirmDeleteMarked(msg)
+		return m, nil
+
+	case deleteCheckMsg:
+		m.confirmDelete(msg)
+		return m, nil
+
+	case branchRenamedMsg:
+		m.handleBranchRenamed(msg)
+		return m, nil
+
+	case spawnReadyMsg:
+		if _, ok := m.bulkSpawns[msg.ticketID]; ok {
+			return m, m.handleBulkSpawnReady(msg)
+		}
+		return m, nil
+
+	case spawnErrorMsg:
+		if _, ok := m.bulkSpawns[msg.ticketID]; ok {
+			m.handleBulkSpawnError(msg)
+		}
+		return m, m.startQueuedSpawns()
+
 	case notificationMsg:
 		if time.Since(m.notifyTime) > 3*time.Second {
 			m.notification = ""
 		}
 		return m, nil
 
+	case agentsDetectedMsg:
+		m.installedAgents = msg
+		return m, nil
+
 	case updateCheckMsg:
 		if msg.UpdateAvailable {
 			result := update.CheckResult(msg)
@@ -483,26 +1021,48 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
-	case "ctrl+c", "q":
-		if m.mode == ModeNormal {
+	case "ctrl+c", m.config.Behavior.QuitKey:
+		if msg.String() == "ctrl+c" && m.quitChecking {
+			return m, m.forceQuit()
+		}
+		if m.mode == ModeNormal && (!m.sidebarTyping() || msg.String() == "ctrl+c") {
 			return m.handleQuit()
 		}
 	case "esc":
-		if m.mode == ModeAgentView {
+		if m.mode == ModeAgentView || m.mode == ModeOnboarding {
+			break
+		}
+		if m.mode == ModeSavedFilters && m.filterNaming {
 			break
 		}
-		if m.mode == ModeNormal && (m.filterQuery != "" || len(m.filterProjectIDs) > 0) {
+		if m.removingProject != nil {
+			m.removingProject = nil
+			return m, nil
+		}
+		if m.sidebarTyping() || (m.mode == ModeNormal && m.sidebarFocused && m.sidebarFilter.Value() != "") {
+			m.clearSidebarFilter()
+			return m, nil
+		}
+		if m.mode == ModeNormal && !m.showConfirm && len(m.selectedTickets) > 0 {
+			m.clearTicketMarks()
+			m.notify("Selection cleared")
+			return m, nil
+		}
+		if m.mode == ModeNormal && m.hasActiveFilter() {
 			m.clearFilter()
 			m.notify("Filter cleared")
 			return m, nil
 		}
 		m.mode = ModeNormal
 		m.showHelp = false
-		m.showConfirm = false
+		m.closeConfirm()
 		m.titleInput.Blur()
+		m.commandInput.Blur()
+		m.branchPreviewInput.Blur()
+		m.closeSpawnPromptPreview()
 		return m, nil
 	case "?":
-		if m.mode == ModeNormal || m.mode == ModeHelp {
+		if (m.mode == ModeNormal && !m.sidebarTyping()) || m.mode == ModeHelp {
 			m.showHelp = !m.showHelp
 			return m, nil
 		}
@@ -513,6 +1073,10 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.removingProject != nil {
+		return m.handleRemoveProjectDialog(msg)
+	}
+
 	if m.showConfirm {
 		return m.handleConfirm(msg)
 	}
@@ -522,6 +1086,10 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleNormalMode(msg)
 	case ModeCommand:
 		return m.handleCommandMode(msg)
+	case ModeBranchPreview:
+		return m.handleBranchPreviewMode(msg)
+	case ModeSpawnPrompt:
+		return m.handleSpawnPromptMode(msg)
 	case ModeCreateTicket:
 		return m.handleCreateTicketMode(msg)
 	case ModeEditTicket:
@@ -534,19 +1102,38 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleFilterMode(msg)
 	case ModeCreateProject:
 		return m.handleCreateProjectMode(msg)
+	case ModeOnboarding:
+		return m.handleOnboardingMode(msg)
+	case ModeWorktrees:
+		return m.handleWorktreesMode(msg)
+	case ModeSavedFilters:
+		return m.handleSavedFiltersMode(msg)
+	case ModeEffectiveConfig:
+		return m.handleEffectiveConfigMode(msg)
+	case ModeConfigHealth:
+		return m.handleConfigHealthMode(msg)
+	case ModeAgentPicker:
+		return m.handleAgentPickerMode(msg)
+	case ModeDashboard:
+		return m.handleDashboardMode(msg)
 	}
 
 	return m, nil
 }
 
 func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "tab":
+	if m.sidebarTyping() {
+		return m.handleSidebarFilter(msg)
+	}
+
+	action := m.keymap[msg.String()]
+	switch action {
+	case "sidebar_focus":
 		if m.sidebarVisible {
 			m.sidebarFocused = !m.sidebarFocused
 			return m, nil
 		}
-	case "[":
+	case "sidebar_toggle":
 		m.sidebarVisible = !m.sidebarVisible
 		if !m.sidebarVisible {
 			m.sidebarFocused = false
@@ -558,64 +1145,144 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleSidebarNav(msg)
 	}
 
-	switch msg.String() {
-	case "h", "left":
+	switch action {
+	case "left":
 		if m.activeColumn == 0 && m.sidebarVisible {
 			m.sidebarFocused = true
 			return m, nil
 		}
 		m.moveColumn(-1)
-	case "l", "right":
+	case "right":
 		m.moveColumn(1)
-	case "j", "down":
+	case "down":
 		m.moveTicket(1)
-	case "k", "up":
+	case "up":
 		m.moveTicket(-1)
-	case "g":
+	case "half_page_down":
+		m.moveTicket(max(m.visibleTicketCount()/2, 1))
+	case "half_page_up":
+		m.moveTicket(-max(m.visibleTicketCount()/2, 1))
+	case "page_down":
+		m.moveTicket(m.visibleTicketCount())
+	case "page_up":
+		m.moveTicket(-m.visibleTicketCount())
+	case "first":
 		m.activeTicket = 0
 		m.ensureTicketVisible()
-	case "G":
+	case "last":
 		if len(m.columnTickets) > m.activeColumn {
 			m.activeTicket = max(len(m.columnTickets[m.activeColumn])-1, 0)
 		}
 		m.ensureTicketVisible()
 
-	case "n":
+	case "new":
 		return m.createNewTicket()
-	case "e":
+	case "edit":
 		return m.editTicket()
-	case "enter":
+	case "attach":
 		return m.attachToAgent()
-	case "d":
+	case "delete":
 		return m.confirmDeleteTicket()
-	case " ":
+	case "move_forward":
 		return m.quickMoveTicket()
-	case "-", "backspace":
+	case "move_backward":
 		return m.quickMoveTicketBackward()
-	case "s":
+	case "undo":
+		m.undo()
+	case "spawn":
+		if marked := m.markedTickets(); len(marked) > 0 {
+			return m.confirmSpawnTickets(marked, -1, "selected")
+		}
 		return m.spawnAgent()
-	case "S":
+	case "stop":
 		return m.stopAgent()
-
-	case ":":
+	case "respawn":
+		return m.respawnLast()
+	case "restart":
+		return m.restartAgent()
+	case "spawn_all":
+		return m.confirmBulkSpawn()
+	case "worktrees":
+		return m.openWorktreesView(m.contextProject())
+	case "saved_filters":
+		return m.openSavedFilters()
+	case "dashboard":
+		return m.openDashboard()
+	case "archive":
+		return m.archiveTicket()
+	case "agent_type":
+		return m.openAgentPicker()
+	case "priority_up":
+		return m.bumpPriority(-1)
+	case "priority_down":
+		return m.bumpPriority(1)
+	case "reorder_down":
+		return m.reorderTicket(1)
+	case "reorder_up":
+		return m.reorderTicket(-1)
+	case "pause":
+		return m.togglePaneOutput()
+	case "merge":
+		return m.confirmMergeBranch()
+	case "pull_request":
+		return m.confirmCreatePullRequest()
+
+	case "command":
 		m.mode = ModeCommand
+		m.commandInput.SetValue("")
+		m.commandInput.Focus()
+		return m, textinput.Blink
 
-	case "/":
+	case "filter":
 		m.filterInput.SetValue(m.filterQuery)
 		m.filterInput.Focus()
 		m.mode = ModeFilter
 
-	case "O":
+	case "settings":
 		m.mode = ModeSettings
 		m.settingsIndex = 0
 		m.settingsEditing = false
+	case "effective_config":
+		return m.openEffectiveConfig()
+
+	case "mark":
+		m.toggleTicketMark()
+	case "group_by_project":
+		m.toggleGroupByProject()
+	case "copy_path":
+		m.copyWorktreePath()
+	case "editor":
+		return m.openEditor()
+	case "quick_agent":
+		return m.openQuickAgent()
+	case "snooze":
+		if ticket := m.selectedTicket(); ticket != nil && ticket.SnoozedUntil != nil && len(m.selectedTickets) == 0 {
+			m.unsnoozeTickets()
+			return m, nil
+		}
+		m.mode = ModeCommand
+		m.commandInput.SetValue("snooze ")
+		m.commandInput.CursorEnd()
+		m.commandInput.Focus()
+		return m, textinput.Blink
+	case "status_detail":
+		m.hideStatusDetail = !m.hideStatusDetail
+		if m.hideStatusDetail {
+			m.notify("Agent status detail hidden")
+		} else {
+			m.notify("Agent status detail shown")
+		}
 	}
 
 	return m, nil
 }
 
 func (m *Model) openAddProjectForm() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
 	m.addProjectPath.SetValue("")
+	m.copySettingsIndex = 0
 	m.addProjectPath.Focus()
 	m.mode = ModeCreateProject
 	m.notification = ""
@@ -625,7 +1292,67 @@ func (m *Model) openAddProjectForm() (tea.Model, tea.Cmd) {
 func (m *Model) sidebarAllY() int          { return 2 }
 func (m *Model) sidebarProjectStartY() int { return 4 }
 func (m *Model) sidebarAddProjectY(projectCount int) int {
-	return m.sidebarProjectStartY() + projectCount + 1
+	return m.sidebarProjectStartY() + projectCount + m.sidebarOrphanRows() + 1
+}
+
+// sidebarOrphanRows returns 1 when the orphaned-tickets pseudo-project is shown.
+func (m *Model) sidebarOrphanRows() int {
+	if len(m.globalStore.OrphanedTickets()) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// sidebarProjects returns the projects listed in the sidebar: all of them,
+// or those whose name contains the sidebar filter.
+func (m *Model) sidebarProjects() []*project.Project {
+	projects := m.globalStore.Projects()
+	query := strings.ToLower(strings.TrimSpace(m.sidebarFilter.Value()))
+	if query == "" {
+		return projects
+	}
+	var matches []*project.Project
+	for _, p := range projects {
+		if strings.Contains(strings.ToLower(p.Name), query) {
+			matches = append(matches, p)
+		}
+	}
+	return matches
+}
+
+// handleSidebarFilter edits the sidebar filter. enter keeps it and returns
+// to navigating the matches; esc (handled in handleKey) clears it.
+func (m *Model) handleSidebarFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.sidebarFiltering = false
+		m.sidebarFilter.Blur()
+		return m, nil
+	case "up", "down":
+		m.sidebarFiltering = false
+		m.sidebarFilter.Blur()
+		return m.handleSidebarNav(msg)
+	}
+
+	var cmd tea.Cmd
+	m.sidebarFilter, cmd = m.sidebarFilter.Update(msg)
+	m.sidebarIndex = 0
+	if len(m.sidebarProjects()) > 0 {
+		m.sidebarIndex = 1
+	}
+	return m, cmd
+}
+
+// sidebarTyping reports whether keys go to the sidebar filter.
+func (m *Model) sidebarTyping() bool {
+	return m.mode == ModeNormal && m.sidebarFocused && m.sidebarFiltering
+}
+
+func (m *Model) clearSidebarFilter() {
+	m.sidebarFiltering = false
+	m.sidebarFilter.Blur()
+	m.sidebarFilter.SetValue("")
+	m.sidebarIndex = 0
 }
 
 func (m *Model) handleSidebarMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
@@ -635,7 +1362,7 @@ func (m *Model) handleSidebarMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	projects := m.globalStore.Projects()
+	projects := m.sidebarProjects()
 
 	if y == m.sidebarAllY() {
 		m.sidebarIndex = 0
@@ -651,6 +1378,12 @@ func (m *Model) handleSidebarMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if m.sidebarOrphanRows() > 0 && y == m.sidebarProjectStartY()+len(projects) {
+		m.sidebarIndex = len(projects) + 1
+		m.toggleOrphanedFilter()
+		return m, nil
+	}
+
 	if y == m.sidebarAddProjectY(len(projects)) {
 		return m.openAddProjectForm()
 	}
@@ -660,32 +1393,44 @@ func (m *Model) handleSidebarMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) handleSidebarNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	projects := m.globalStore.Projects()
-	addIndex := len(projects) + 1
+	projects := m.sidebarProjects()
+	orphanIndex := -1
+	if m.sidebarOrphanRows() > 0 {
+		orphanIndex = len(projects) + 1
+	}
+	addIndex := len(projects) + 1 + m.sidebarOrphanRows()
 
-	switch msg.String() {
-	case "j", "down":
+	// Movement follows the board's key bindings.
+	switch m.keymap[msg.String()] {
+	case "down":
 		if m.sidebarIndex < addIndex {
 			m.sidebarIndex++
 		}
-	case "k", "up":
+		return m, nil
+	case "up":
 		if m.sidebarIndex > 0 {
 			m.sidebarIndex--
 		}
+		return m, nil
+	case "right":
+		m.sidebarFocused = false
+		return m, nil
+	}
+
+	switch msg.String() {
 	case "enter", " ":
 		if m.sidebarIndex == 0 {
 			m.toggleAllProjects()
 		} else if m.sidebarIndex == addIndex {
 			return m.openAddProjectForm()
+		} else if m.sidebarIndex == orphanIndex {
+			m.toggleOrphanedFilter()
 		} else {
 			idx := m.sidebarIndex - 1
 			if idx < len(projects) {
 				m.toggleProjectFilter(projects[idx].ID)
 			}
 		}
-	case "l", "right":
-		m.sidebarFocused = false
-		return m, nil
 	case "a":
 		return m.openAddProjectForm()
 	case "d":
@@ -693,6 +1438,15 @@ func (m *Model) handleSidebarNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.confirmDeleteProject(projects[m.sidebarIndex-1])
 		}
 		return m, nil
+	case "w":
+		if m.sidebarIndex > 0 && m.sidebarIndex <= len(projects) {
+			return m.openWorktreesView(projects[m.sidebarIndex-1])
+		}
+		return m, nil
+	case "/":
+		m.sidebarFiltering = true
+		m.sidebarFilter.Focus()
+		return m, textinput.Blink
 	case "esc":
 		m.sidebarFocused = false
 	}
@@ -703,6 +1457,20 @@ func (m *Model) handleSidebarNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	switch msg.Action {
 	case tea.MouseActionPress:
+		if tea.MouseEvent(msg).IsWheel() {
+			if m.sidebarVisible && msg.X < m.sidebarWidth {
+				return m, nil
+			}
+			col, _ := m.hitTest(msg.X, msg.Y)
+			switch msg.Button {
+			case tea.MouseButtonWheelUp:
+				m.scrollColumn(col, -1)
+			case tea.MouseButtonWheelDown:
+				m.scrollColumn(col, 1)
+			}
+			m.hoverColumn, m.hoverTicket = m.hitTest(msg.X, msg.Y)
+			return m, nil
+		}
 		if msg.Button == tea.MouseButtonLeft {
 			if m.hitTestHeader(msg.X, msg.Y) {
 				return m, nil
@@ -710,6 +1478,10 @@ func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			if m.sidebarVisible && msg.X < m.sidebarWidth {
 				return m.handleSidebarMouse(msg)
 			}
+			if delta := m.hitTestScrollIndicator(msg.X, msg.Y); delta != 0 {
+				m.scrollColumns(delta)
+				return m, nil
+			}
 			col, ticket := m.hitTest(msg.X, msg.Y)
 			if col >= 0 {
 				m.sidebarFocused = false
@@ -732,7 +1504,7 @@ func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 					m.lastClickTicket = ticket
 
 					m.activeTicket = ticket
-					m.dragging = true
+					m.dragging = !m.groupByProject && !m.readOnly
 					m.dragSourceColumn = col
 					m.dragSourceTicket = ticket
 					m.dragTargetColumn = col
@@ -769,14 +1541,6 @@ func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		col, ticket := m.hitTest(msg.X, msg.Y)
 		m.hoverColumn = col
 		m.hoverTicket = ticket
-
-	default:
-		switch msg.Button {
-		case tea.MouseButtonWheelUp:
-			m.moveTicket(-1)
-		case tea.MouseButtonWheelDown:
-			m.moveTicket(1)
-		}
 	}
 
 	return m, nil
@@ -787,7 +1551,7 @@ func (m *Model) hitTestHeader(x, y int) bool {
 		return false
 	}
 
-	if m.filterQuery != "" || len(m.filterProjectIDs) > 0 {
+	if m.hasActiveFilter() {
 		clearStart := 20 + len(m.filterQuery) + 15
 		if x >= clearStart && x <= clearStart+10 {
 			m.clearFilter()
@@ -828,11 +1592,8 @@ func (m *Model) hitTest(x, y int) (column, ticket int) {
 
 	baseWidth, remainder := m.distributeWidth(numVisible)
 
-	hasLeftIndicator := m.scrollOffset > 0
-	startX := 0
-	if hasLeftIndicator {
-		startX = 2
-	}
+	leftIndicator, _ := m.scrollIndicators()
+	startX := lipgloss.Width(leftIndicator)
 
 	for i := 0; i < numVisible; i++ {
 		colWidth := baseWidth + 3
@@ -851,6 +1612,53 @@ func (m *Model) hitTest(x, y int) (column, ticket int) {
 	return -1, -1
 }
 
+// hitTestScrollIndicator reports which board scroll indicator is at (x, y):
+// -1 for the left one, 1 for the right one and 0 for neither.
+func (m *Model) hitTestScrollIndicator(x, y int) int {
+	if m.width == 0 || len(m.columns) == 0 || y < 2 {
+		return 0
+	}
+
+	if m.sidebarVisible {
+		x = x - m.sidebarWidth - 1
+	}
+
+	leftIndicator, rightIndicator := m.scrollIndicators()
+	leftWidth := lipgloss.Width(leftIndicator)
+	if x >= 0 && x < leftWidth {
+		return -1
+	}
+	if rightIndicator == "" {
+		return 0
+	}
+
+	numVisible := min(m.visibleColumnCount(m.calcColumnWidth()), len(m.columns)-m.scrollOffset)
+	baseWidth, remainder := m.distributeWidth(numVisible)
+	columnsEnd := leftWidth + numVisible*(baseWidth+2) + min(remainder, numVisible) + numVisible - 1
+	if x >= columnsEnd && x < columnsEnd+lipgloss.Width(rightIndicator) {
+		return 1
+	}
+	return 0
+}
+
+// scrollColumns scrolls the board delta columns sideways, keeping the
+// active column on screen.
+func (m *Model) scrollColumns(delta int) {
+	visibleCols := m.visibleColumnCount(m.calcColumnWidth())
+	maxOffset := max(len(m.columns)-visibleCols, 0)
+	m.scrollOffset = min(max(m.scrollOffset+delta, 0), maxOffset)
+
+	if m.activeColumn < m.scrollOffset {
+		m.activeColumn = m.scrollOffset
+	} else if m.activeColumn >= m.scrollOffset+visibleCols {
+		m.activeColumn = m.scrollOffset + visibleCols - 1
+	} else {
+		return
+	}
+	m.activeTicket = 0
+	m.ensureTicketVisible()
+}
+
 func (m *Model) hitTestTicket(relativeY, column int) int {
 	if column < 0 || column >= len(m.columnTickets) {
 		return -1
@@ -870,8 +1678,15 @@ func (m *Model) hitTestTicket(relativeY, column int) int {
 	if column < len(m.columnOffsets) {
 		offset = m.columnOffsets[column]
 	}
+	if offset > 0 {
+		// Skip the "▲ n more" line above the first visible ticket.
+		ticketY--
+		if ticketY < 0 {
+			return -1
+		}
+	}
 
-	ticketIdx := offset + (ticketY / ticketHeight)
+	ticketIdx := offset + (ticketY / m.cardHeight())
 	if ticketIdx >= len(tickets) {
 		return -1
 	}
@@ -880,6 +1695,10 @@ func (m *Model) hitTestTicket(relativeY, column int) int {
 }
 
 func (m *Model) dropTicket() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		m.dragging = false
+		return m, nil
+	}
 	if len(m.columnTickets) <= m.dragSourceColumn {
 		m.dragging = false
 		return m, nil
@@ -894,22 +1713,36 @@ func (m *Model) dropTicket() (tea.Model, tea.Cmd) {
 	ticket := tickets[m.dragSourceTicket]
 	targetStatus := m.columns[m.dragTargetColumn].Status
 
-	if targetStatus == board.StatusInProgress && ticket.WorktreePath == "" {
-		if ticket.UseWorktree {
-			if err := m.setupWorktree(ticket); err != nil {
-				m.notify("Worktree failed: " + err.Error())
-				m.dragging = false
-				return m, nil
-			}
-		} else {
-			if err := m.setupMainRepoBranch(ticket); err != nil {
-				m.notify("Branch setup failed: " + err.Error())
-				m.dragging = false
-				return m, nil
-			}
-		}
+	if !slices.Contains(m.ticketStatusOrder(ticket), targetStatus) {
+		m.notify(m.columns[m.dragTargetColumn].Name + " isn't a column in this ticket's project")
+		m.dragging = false
+		return m, nil
+	}
+
+	if msg, full := m.wipLimitReached(ticket, targetStatus); full {
+		m.notify(msg)
+		m.dragging = false
+		return m, nil
+	}
+
+	if targetStatus == board.StatusInProgress && m.blockedFromStarting(ticket) {
+		m.dragging = false
+		return m, nil
+	}
+
+	if targetStatus == board.StatusInProgress && m.needsBranchPreview(ticket) {
+		m.dragging = false
+		m.dragTargetColumn = 0
+		return m, m.openBranchPreview(ticket)
+	}
+
+	if targetStatus == board.StatusInProgress && ticket.WorktreePath == "" {
+		m.dragging = false
+		m.dragTargetColumn = 0
+		return m, m.startTicketWork(ticket, targetStatus)
 	}
 
+	m.recordMove(ticket)
 	m.globalStore.Move(ticket.ID, targetStatus)
 	m.refreshColumnTickets()
 	m.saveTicket(ticket)
@@ -929,2058 +1762,7151 @@ func (m *Model) dropTicket() (tea.Model, tea.Cmd) {
 func (m *Model) handleCommandMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter":
+		line := m.commandInput.Value()
+		m.commandInput.Blur()
 		m.mode = ModeNormal
-	case "esc":
+		return m, m.executeCommand(line)
+	case "esc", "ctrl+c":
+		m.commandInput.Blur()
 		m.mode = ModeNormal
+		return m, nil
 	}
-	return m, nil
+
+	var cmd tea.Cmd
+	m.commandInput, cmd = m.commandInput.Update(msg)
+	return m, cmd
 }
 
-func (m *Model) handleConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
-		m.showConfirm = false
-		if m.confirmFn != nil {
-			return m, m.confirmFn()
+// executeCommand runs a line entered at the ':' prompt. Arguments are split
+// shell-style so multi-word values can be quoted.
+func (m *Model) executeCommand(line string) tea.Cmd {
+	if strings.HasPrefix(line, "s/") {
+		return m.commandSubstitute(line)
+	}
+
+	args, err := agent.SplitArgs(line)
+	if err != nil {
+		m.notify("Error: " + err.Error())
+		return nil
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	switch args[0] {
+	case "replace":
+		m.commandReplace(args[1:])
+	case "status", "move":
+		return m.commandStatus(args[1:])
+	case "archive":
+		_, cmd := m.archiveTicket()
+		return cmd
+	case "spawn":
+		return m.commandSpawn(args[1:])
+	case "delete":
+		_, cmd := m.confirmDeleteTicket()
+		return cmd
+	case "label":
+		m.commandLabel(args[1:])
+	case "group":
+		m.toggleGroupByProject()
+	case "filter":
+		m.commandFilter(args[1:])
+	case "attach":
+		return m.commandAttach(args[1:])
+	case "rebase":
+		return m.commandRebase()
+	case "commit":
+		return m.commandCommit(args[1:])
+	case "quit", "q":
+		_, cmd := m.handleQuit()
+		return cmd
+	case "archived":
+		m.setShowArchived(!m.config.UI.ShowArchived)
+		if m.config.UI.ShowArchived {
+			m.notify("Showing archived tickets")
+		} else {
+			m.notify("Hiding archived tickets")
 		}
-	case "n", "N", "esc":
-		m.showConfirm = false
+	case "purge":
+		m.commandPurge()
+	case "snooze":
+		m.commandSnooze(args[1:])
+	case "unsnooze":
+		m.unsnoozeTickets()
+	case "snoozed":
+		m.setShowSnoozed(!m.config.UI.ShowSnoozed)
+		if m.config.UI.ShowSnoozed {
+			m.notify("Showing snoozed tickets")
+		} else {
+			m.notify("Hiding snoozed tickets")
+		}
+	default:
+		m.notify("Error: unknown command: " + args[0])
 	}
-	return m, nil
+	return nil
 }
 
-func (m *Model) handleQuit() (tea.Model, tea.Cmd) {
-	runningCount := m.RunningAgentCount()
-	if runningCount == 0 {
-		return m, tea.Quit
+// replaceScope returns the tickets a bulk edit applies to: everything in the
+// current project filter, sorted by title.
+func (m *Model) replaceScope() []*board.Ticket {
+	var tickets []*board.Ticket
+	for _, t := range m.globalStore.All() {
+		if len(m.filterProjectIDs) > 0 && !m.filterProjectIDs[t.ProjectID] {
+			continue
+		}
+		tickets = append(tickets, t)
 	}
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].Title < tickets[j].Title
+	})
+	return tickets
+}
 
-	if !m.config.Behavior.ConfirmQuitWithAgents {
-		m.mode = ModeShuttingDown
-		return m, tea.Batch(m.spinner.Tick, m.cleanupAsync())
+// commandSpawn starts the selected ticket's agent, first switching it to
+// the named agent type when one is given.
+func (m *Model) commandSpawn(args []string) tea.Cmd {
+	if m.denyReadOnly() {
+		return nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		m.notify("No ticket selected")
+		return nil
+	}
+	if len(args) > 1 {
+		m.notify("Error: usage: spawn [agent]")
+		return nil
 	}
 
-	m.showConfirm = true
-	m.confirmMsg = fmt.Sprintf("%d agent(s) running. Quit anyway? [y/N]", runningCount)
-	m.confirmFn = func() tea.Cmd {
-		m.mode = ModeShuttingDown
-		m.showConfirm = false
-		return tea.Batch(m.spinner.Tick, m.cleanupAsync())
+	if len(args) == 1 && args[0] != ticket.NextAgentType() {
+		if _, ok := m.config.Agents[args[0]]; !ok {
+			m.notify("Error: unknown agent: " + args[0])
+			return nil
+		}
+		if _, exists := m.panes[ticket.ID]; exists {
+			m.notify("Agent already running — press Enter to attach")
+			return nil
+		}
+		m.setTicketAgent(ticket, args[0])
 	}
-	return m, nil
+
+	_, cmd := m.spawnAgent()
+	return cmd
 }
 
-func (m *Model) cleanupAsync() tea.Cmd {
-	return func() tea.Msg {
-		m.Cleanup()
-		return shutdownCompleteMsg{}
+// commandLabel handles ":label +name -name ...", adding or removing labels
+// on the marked tickets, or on the selected ticket when none are marked. A
+// bare name is added.
+func (m *Model) commandLabel(args []string) {
+	if m.denyReadOnly() {
+		return
+	}
+	if len(args) == 0 {
+		m.notify("Error: usage: label +name -name ...")
+		return
+	}
+	tickets := m.markedTickets()
+	if len(tickets) == 0 {
+		if ticket := m.selectedTicket(); ticket != nil {
+			tickets = []*board.Ticket{ticket}
+		}
+	}
+	if len(tickets) == 0 {
+		m.notify("No ticket selected")
+		return
+	}
+
+	for _, t := range tickets {
+		labels := slices.Clone(t.Labels)
+		for _, arg := range args {
+			if name, ok := strings.CutPrefix(arg, "-"); ok {
+				labels = slices.DeleteFunc(labels, func(l string) bool { return strings.EqualFold(l, name) })
+				continue
+			}
+			labels = append(labels, strings.TrimPrefix(arg, "+"))
+		}
+		t.Labels = m.parseLabels(strings.Join(labels, ","))
+		t.Touch()
+		m.saveTicket(t)
 	}
+	m.notify(fmt.Sprintf("Updated labels on %d ticket(s)", len(tickets)))
 }
 
-func (m *Model) handleAgentViewMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	pane, ok := m.panes[m.focusedPane]
-	if !ok {
-		m.mode = ModeNormal
-		m.focusedPane = ""
-		return m, nil
+// commandFilter sets the board filter as if typed after /, clearing it
+// when no query is given.
+func (m *Model) commandFilter(args []string) {
+	query := strings.Join(args, " ")
+	if query == "" {
+		m.clearFilter()
+		m.notify("Filter cleared")
+		return
+	}
+	m.filterQuery = query
+	m.filterInput.SetValue(query)
+	m.refreshColumnTickets()
+}
+
+// commandAttach handles ":attach <query>", opening the agent view for the
+// one running agent whose ticket title contains query, ignoring case.
+func (m *Model) commandAttach(args []string) tea.Cmd {
+	query := strings.ToLower(strings.Join(args, " "))
+	if query == "" {
+		m.notify("Error: usage: attach <title>")
+		return nil
 	}
 
-	if result := pane.HandleKey(msg); result != nil {
-		if _, isExit := result.(terminal.ExitFocusMsg); isExit {
-			m.mode = ModeNormal
-			m.focusedPane = ""
+	var matches []*board.Ticket
+	for id, pane := range m.panes {
+		if !pane.Running() {
+			continue
+		}
+		if ticket, _ := m.globalStore.Get(id); ticket != nil && strings.Contains(strings.ToLower(ticket.Title), query) {
+			matches = append(matches, ticket)
 		}
 	}
 
-	return m, nil
+	switch len(matches) {
+	case 0:
+		m.notify(fmt.Sprintf("No running agent matches %q", query))
+		return nil
+	case 1:
+		m.selectTicketByID(matches[0].ID)
+		_, cmd := m.attachToTicket(matches[0])
+		return cmd
+	default:
+		m.notify(fmt.Sprintf("%d running agents match %q; be more specific", len(matches), query))
+		return nil
+	}
 }
 
-func (m *Model) handleAgentViewMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
-	pane, ok := m.panes[m.focusedPane]
-	if !ok {
-		return m, nil
+// commandStatus handles ":status <column>", moving the selected ticket
+// straight to a column of its project, matched by name or status. Only a
+// move into In Progress sets up a worktree.
+func (m *Model) commandStatus(args []string) tea.Cmd {
+	if m.denyReadOnly() {
+		return nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		m.notify("No ticket selected")
+		return nil
+	}
+	if len(args) == 0 {
+		m.notify("Error: usage: status <column>")
+		return nil
 	}
 
-	if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
-		if msg.Y == 0 && msg.X >= m.width-25 {
-			m.mode = ModeNormal
-			return m, nil
+	want := normalizeColumnName(strings.Join(args, " "))
+	var target *board.Column
+	var names []string
+	for _, col := range m.ticketColumns(ticket) {
+		names = append(names, col.Name)
+		if normalizeColumnName(col.Name) == want || normalizeColumnName(string(col.Status)) == want {
+			target = &col
+			break
 		}
 	}
+	if target == nil {
+		m.notify("Error: no column " + strings.Join(args, " ") + " (try " + strings.Join(names, ", ") + ")")
+		return nil
+	}
 
-	pane.HandleMouse(msg)
-	return m, nil
-}
+	status := target.Status
+	switch {
+	case status == ticket.Status:
+		m.notify("Already in " + target.Name)
+		return nil
+	case status == board.StatusArchived:
+		_, cmd := m.archiveTicket()
+		return cmd
+	}
+	if msg, full := m.wipLimitReached(ticket, status); full {
+		m.notify(msg)
+		return nil
+	}
 
-func (m *Model) handleTicketFormMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
-	switch msg.Button {
-	case tea.MouseButtonWheelUp:
-		m.formScrollOffset -= 3
-		if m.formScrollOffset < 0 {
-			m.formScrollOffset = 0
+	if status == board.StatusInProgress {
+		if m.blockedFromStarting(ticket) {
+			return nil
+		}
+		if m.needsBranchPreview(ticket) {
+			return m.openBranchPreview(ticket)
+		}
+		if ticket.WorktreePath == "" {
+			return m.startTicketWork(ticket, status)
 		}
-		return m, nil
-	case tea.MouseButtonWheelDown:
-		m.formScrollOffset += 3
-		return m, nil
 	}
 
-	formWidth := 50
-	formLeft := (m.width - formWidth) / 2
-	formRight := formLeft + formWidth
+	m.finishMove(ticket, status)
+	return nil
+}
 
-	if msg.X < formLeft || msg.X > formRight {
-		return m, nil
+// normalizeColumnName lowercases s and treats spaces, dashes and
+// underscores alike, so "In Progress" matches "in_progress".
+func normalizeColumnName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(s)
+}
+
+// commandSubstitute handles ":s/old/new/[g]", rewriting the selected
+// ticket's title. old is a regular expression; new may use $1 for groups.
+// Without g only the first match is replaced.
+func (m *Model) commandSubstitute(line string) tea.Cmd {
+	if m.denyReadOnly() {
+		return nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		m.notify("No ticket selected")
+		return nil
 	}
 
-	formTop := (m.height - 28) / 2
-	relY := msg.Y - formTop
+	re, repl, global, err := parseSubstitute(line)
+	if err != nil {
+		m.notify("Error: " + err.Error())
+		return nil
+	}
+
+	title := ticket.Title
+	if global {
+		title = re.ReplaceAllString(title, repl)
+	} else if loc := re.FindStringSubmatchIndex(title); loc != nil {
+		expanded := re.ExpandString(nil, repl, title, loc)
+		title = title[:loc[0]] + string(expanded) + title[loc[1]:]
+	}
+	title = strings.TrimSpace(title)
 
-	var clickedField int = -1
 	switch {
-	case relY >= 3 && relY <= 4:
-		clickedField = formFieldTitle
-	case relY >= 6 && relY <= 9:
-		clickedField = formFieldDescription
-	case relY >= 11 && relY <= 13:
-		clickedField = formFieldBranch
-	case relY >= 15 && relY <= 17:
-		clickedField = formFieldLabels
-	case relY >= 19 && relY <= 21:
-		clickedField = formFieldPriority
-	case relY >= 23:
-		clickedField = formFieldProject
+	case title == ticket.Title:
+		m.notify("Pattern not found: " + re.String())
+		return nil
+	case title == "":
+		m.notify("Error: title cannot be empty")
+		return nil
 	}
 
-	if clickedField >= 0 && msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
-		m.blurAllFormFields()
-		m.ticketFormField = clickedField
-		m.focusCurrentField()
+	oldTitle := ticket.Title
+	ticket.Title = title
+	ticket.Touch()
+	m.saveTicket(ticket)
+	m.refreshColumnTickets()
+	m.selectTicketByID(ticket.ID)
+	m.notify("Renamed: " + title)
+	return m.checkBranchRename(ticket, oldTitle)
+}
 
-		if clickedField == formFieldProject && !m.showAddProjectForm {
-			projects := m.globalStore.Projects()
-			projectRelY := relY - 24
-			if projectRelY >= 0 && projectRelY <= len(projects) {
-				m.projectListIndex = projectRelY
-				if projectRelY == len(projects) {
-					m.showAddProjectForm = true
-					m.addProjectPath.SetValue("")
-					m.addProjectPath.Focus()
-					return m, textinput.Blink
-				}
-				if projectRelY < len(projects) {
-					m.selectedProject = projects[projectRelY]
-				}
-			}
+// parseSubstitute splits "s/old/new/flags" into its parts. A backslash
+// escapes a literal '/'; the only flag is g.
+func parseSubstitute(line string) (*regexp.Regexp, string, bool, error) {
+	const usage = "usage: s/old/new/ or s/old/new/g"
+
+	var parts []string
+	var current strings.Builder
+	body := strings.TrimPrefix(line, "s/")
+	for i := 0; i < len(body); i++ {
+		switch {
+		case body[i] == '\\' && i+1 < len(body) && body[i+1] == '/':
+			current.WriteByte('/')
+			i++
+		case body[i] == '/':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(body[i])
 		}
 	}
+	parts = append(parts, current.String())
+	if len(parts) == 2 {
+		parts = append(parts, "")
+	}
 
-	var cmd tea.Cmd
-	switch m.ticketFormField {
-	case formFieldTitle:
-		m.titleInput, cmd = m.titleInput.Update(msg)
-	case formFieldDescription:
-		m.descInput, cmd = m.descInput.Update(msg)
-	case formFieldBranch:
-		if !m.branchLocked {
-			m.branchInput, cmd = m.branchInput.Update(msg)
-		}
-	case formFieldLabels:
-		m.labelsInput, cmd = m.labelsInput.Update(msg)
+	if len(parts) != 3 || parts[0] == "" {
+		return nil, "", false, errors.New(usage)
+	}
+	global := false
+	switch parts[2] {
+	case "":
+	case "g":
+		global = true
+	default:
+		return nil, "", false, fmt.Errorf("unknown flag %q: %s", parts[2], usage)
 	}
 
-	return m, cmd
+	re, err := regexp.Compile(parts[0])
+	if err != nil {
+		return nil, "", false, fmt.Errorf("bad pattern: %w", err)
+	}
+	return re, parts[1], global, nil
 }
 
-func (m *Model) handleCreateTicketMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	return m.handleTicketForm(msg, false)
-}
+// commandPurge handles ":purge", permanently deleting every archived ticket
+// in the current project filter after confirmation.
+func (m *Model) commandPurge() {
+	if m.denyReadOnly() {
+		return
+	}
+	var archived []*board.Ticket
+	for _, t := range m.replaceScope() {
+		if t.Status == board.StatusArchived {
+			archived = append(archived, t)
+		}
+	}
+	if len(archived) == 0 {
+		m.notify("No archived tickets to purge")
+		return
+	}
 
-func (m *Model) handleEditTicketMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	return m.handleTicketForm(msg, true)
+	m.showConfirm = true
+	m.confirmMsg = fmt.Sprintf("Permanently delete %d archived ticket(s)?", len(archived))
+	m.confirmFn = func() tea.Cmd {
+		var cmds []tea.Cmd
+		for _, t := range archived {
+			cmds = append(cmds, m.performTicketCleanup(t))
+		}
+		m.notify(fmt.Sprintf("Purged %d archived ticket(s)", len(archived)))
+		return tea.Batch(cmds...)
+	}
 }
 
-func (m *Model) handleTicketForm(msg tea.KeyMsg, isEdit bool) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c":
-		m.mode = ModeNormal
-		m.blurAllFormFields()
-		m.editingTicketID = ""
-		m.branchLocked = false
-		m.showAddProjectForm = false
-		return m, nil
-
-	case "tab":
-		if m.showAddProjectForm && m.addProjectPath.Value() != "" {
-			m.createProjectFromPath()
-			if m.showAddProjectForm {
-				return m, nil
-			}
-		} else if m.showAddProjectForm {
-			m.showAddProjectForm = false
-		}
-		return m.nextFormField(isEdit), nil
-	case "shift+tab":
-		if m.showAddProjectForm {
-			m.showAddProjectForm = false
-		}
-		return m.prevFormField(isEdit), nil
-
-	case "ctrl+s":
-		return m.saveTicketForm(isEdit)
-
-	case "enter":
-		if m.ticketFormField == formFieldTitle {
-			return m.saveTicketForm(isEdit)
-		}
-		if m.ticketFormField == formFieldProject && !isEdit {
-			return m.handleProjectSelection()
-		}
+// commandReplace handles ":replace <old> <new>", previewing every ticket
+// whose title or description contains old before rewriting them.
+func (m *Model) commandReplace(args []string) {
+	if m.denyReadOnly() {
+		return
+	}
+	if len(args) != 2 || args[0] == "" {
+		m.notify(`Error: usage: replace "old text" "new text"`)
+		return
+	}
+	old, replacement := args[0], args[1]
 
-	case "esc":
-		if m.showAddProjectForm {
-			m.showAddProjectForm = false
-			m.addProjectPath.Blur()
-			return m, nil
+	var matches []*board.Ticket
+	for _, t := range m.replaceScope() {
+		if strings.Contains(t.Title, old) || strings.Contains(t.Description, old) {
+			matches = append(matches, t)
 		}
-		m.mode = ModeNormal
-		m.blurAllFormFields()
-		m.editingTicketID = ""
-		m.branchLocked = false
-		return m, nil
+	}
+	if len(matches) == 0 {
+		m.notify("No tickets contain \"" + old + "\"")
+		return
 	}
 
-	var cmd tea.Cmd
-	switch m.ticketFormField {
-	case formFieldTitle:
-		m.titleInput, cmd = m.titleInput.Update(msg)
-	case formFieldDescription:
-		m.descInput, cmd = m.descInput.Update(msg)
-	case formFieldBranch:
-		if !m.branchLocked {
-			m.branchInput, cmd = m.branchInput.Update(msg)
-		}
-	case formFieldLabels:
-		m.labelsInput, cmd = m.labelsInput.Update(msg)
-	case formFieldPriority:
-		cmd = m.handlePriorityNav(msg)
-	case formFieldWorktree:
-		cmd = m.handleWorktreeToggle(msg)
-	case formFieldAgent:
-		if !m.agentLocked {
-			cmd = m.handleAgentNav(msg)
+	const maxPreview = 8
+	var preview strings.Builder
+	fmt.Fprintf(&preview, "Replace %q with %q in %d ticket(s)?\n", old, replacement, len(matches))
+	for i, t := range matches {
+		if i == maxPreview {
+			fmt.Fprintf(&preview, "\n  … and %d more", len(matches)-maxPreview)
+			break
 		}
-	case formFieldBlockedBy:
-		cmd = m.handleBlockerNav(msg)
-	case formFieldProject:
-		if m.showAddProjectForm {
-			m.addProjectPath, cmd = m.addProjectPath.Update(msg)
+		line := t.Title
+		if strings.Contains(t.Title, old) {
+			line += " → " + strings.ReplaceAll(t.Title, old, replacement)
 		} else {
-			cmd = m.handleProjectListNav(msg)
+			line += " (description)"
 		}
+		preview.WriteString("\n  • " + line)
 	}
-	return m, cmd
-}
 
-func (m *Model) handlePriorityNav(msg tea.KeyMsg) tea.Cmd {
-	switch msg.String() {
-	case "j", "down", "l", "right":
-		m.ticketPriority++
-		if m.ticketPriority > 5 {
-			m.ticketPriority = 1
-		}
-	case "k", "up", "h", "left":
-		m.ticketPriority--
-		if m.ticketPriority < 1 {
-			m.ticketPriority = 5
+	m.showConfirm = true
+	m.confirmMsg = preview.String()
+	m.confirmFn = func() tea.Cmd {
+		for _, t := range matches {
+			t.Title = strings.ReplaceAll(t.Title, old, replacement)
+			t.Description = strings.ReplaceAll(t.Description, old, replacement)
+			t.Touch()
+			m.saveTicket(t)
 		}
-	case "1", "2", "3", "4", "5":
-		m.ticketPriority = int(msg.String()[0] - '0')
+		m.refreshColumnTickets()
+		m.notify(fmt.Sprintf("Replaced in %d ticket(s)", len(matches)))
+		return nil
 	}
-	return nil
 }
 
-func (m *Model) handleWorktreeToggle(msg tea.KeyMsg) tea.Cmd {
+func (m *Model) handleConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
-	case " ", "enter", "h", "l", "left", "right":
-		m.ticketUseWorktree = !m.ticketUseWorktree
 	case "y", "Y":
-		m.ticketUseWorktree = true
-	case "n", "N":
-		m.ticketUseWorktree = false
+		return m, m.acceptConfirm()
+	case "n", "N", "esc":
+		m.closeConfirm()
+	case "tab", "shift+tab", "left", "right", "h", "l":
+		m.confirmYes = !m.confirmYes
+	case "enter":
+		if m.confirmYes {
+			return m, m.acceptConfirm()
+		}
+		m.closeConfirm()
 	}
-	return nil
+	return m, nil
 }
 
-func (m *Model) handleAgentNav(msg tea.KeyMsg) tea.Cmd {
-	agents := m.getAgentNames()
-	if len(agents) == 0 {
-		return nil
-	}
-
-	switch msg.String() {
-	case "j", "down", "l", "right":
-		m.agentListIndex++
-		if m.agentListIndex >= len(agents) {
-			m.agentListIndex = 0
-		}
-	case "k", "up", "h", "left":
-		m.agentListIndex--
-		if m.agentListIndex < 0 {
-			m.agentListIndex = len(agents) - 1
-		}
+// acceptConfirm closes the confirm dialog and runs its action.
+func (m *Model) acceptConfirm() tea.Cmd {
+	m.closeConfirm()
+	if m.confirmFn != nil {
+		return m.confirmFn()
 	}
-	m.ticketAgent = agents[m.agentListIndex]
 	return nil
 }
 
-func (m *Model) handleProjectListNav(msg tea.KeyMsg) tea.Cmd {
-	projects := m.globalStore.Projects()
-	maxIndex := len(projects)
+func (m *Model) closeConfirm() {
+	m.showConfirm = false
+	m.confirmYes = false
+}
 
-	switch msg.String() {
-	case "j", "down":
-		m.projectListIndex++
-		if m.projectListIndex > maxIndex {
-			m.projectListIndex = 0
-		}
-	case "k", "up":
-		m.projectListIndex--
-		if m.projectListIndex < 0 {
-			m.projectListIndex = maxIndex
-		}
-	case "d":
-		if m.projectListIndex < len(projects) {
-			m.confirmDeleteProject(projects[m.projectListIndex])
-		}
+// handleQuit checks the worktrees for uncommitted changes in the background
+// before quitting or asking to; confirmQuit takes over with the result.
+// ctrl+c during the check quits without waiting for it.
+func (m *Model) handleQuit() (tea.Model, tea.Cmd) {
+	if m.quitChecking {
+		m.notify("Checking worktrees — press ctrl+c again to quit now")
+		return m, nil
 	}
+	m.quitChecking = true
+	return m, m.checkUncommittedChangesAsync()
+}
 
-	// Auto-select the highlighted project (if not on "+ Add project" option)
-	if m.projectListIndex < len(projects) {
-		m.selectedProject = projects[m.projectListIndex]
+// forceQuit quits without waiting for the worktree check, stopping any
+// running agents first.
+func (m *Model) forceQuit() tea.Cmd {
+	m.quitChecking = false
+	m.closeConfirm()
+	if m.RunningAgentCount()+len(m.quickAgents) == 0 {
+		return tea.Quit
 	}
-
-	return nil
+	m.mode = ModeShuttingDown
+	return tea.Batch(m.spinner.Tick, m.cleanupAsync())
 }
 
-func (m *Model) handleBlockerNav(msg tea.KeyMsg) tea.Cmd {
-	visibleCandidates := m.getFilteredBlockerCandidates()
+// quitCheckMsg carries the tickets whose worktrees have uncommitted changes,
+// found when quitting.
+type quitCheckMsg struct {
+	dirty []*board.Ticket
+}
 
-	switch msg.Type {
-	case tea.KeyDown, tea.KeyCtrlN:
-		if len(visibleCandidates) > 0 {
-			m.blockerListIndex++
-			if m.blockerListIndex >= len(visibleCandidates) {
-				m.blockerListIndex = 0
-			}
-		}
-		return nil
-	case tea.KeyUp, tea.KeyCtrlP:
-		if len(visibleCandidates) > 0 {
-			m.blockerListIndex--
-			if m.blockerListIndex < 0 {
-				m.blockerListIndex = len(visibleCandidates) - 1
-			}
-		}
+// confirmQuit quits, or asks first when agents or editors are running,
+// worktrees in dirty have uncommitted changes or behavior asks to confirm.
+// The quit is abandoned if another mode was entered during the check.
+func (m *Model) confirmQuit(dirty []*board.Ticket) tea.Cmd {
+	m.quitChecking = false
+	if m.mode != ModeNormal {
 		return nil
-	case tea.KeySpace, tea.KeyEnter:
-		if m.blockerListIndex < len(visibleCandidates) {
-			ticket := visibleCandidates[m.blockerListIndex]
-			if m.selectedBlockers[ticket.ID] {
-				delete(m.selectedBlockers, ticket.ID)
-			} else {
-				m.selectedBlockers[ticket.ID] = true
-			}
+	}
+	sort.Slice(dirty, func(i, j int) bool { return dirty[i].Title < dirty[j].Title })
+	runningCount := m.RunningAgentCount() + len(m.quickAgents)
+	editorCount := m.runningEditorCount()
+	if runningCount == 0 && editorCount == 0 && len(dirty) == 0 {
+		if !m.config.Behavior.ConfirmQuit {
+			return tea.Quit
 		}
+		m.showConfirm = true
+		m.confirmMsg = "Quit OpenKanban?"
+		m.confirmFn = func() tea.Cmd { return tea.Quit }
 		return nil
 	}
 
-	var cmd tea.Cmd
-	m.blockerFilterInput, cmd = m.blockerFilterInput.Update(msg)
+	quit := func() tea.Cmd {
+		if runningCount == 0 {
+			return tea.Quit
+		}
+		m.mode = ModeShuttingDown
+		return tea.Batch(m.spinner.Tick, m.cleanupAsync())
+	}
 
-	newVisible := m.getFilteredBlockerCandidates()
-	if m.blockerListIndex >= len(newVisible) && len(newVisible) > 0 {
-		m.blockerListIndex = len(newVisible) - 1
-	} else if len(newVisible) == 0 {
-		m.blockerListIndex = 0
+	if len(dirty) == 0 && editorCount == 0 && !m.config.Behavior.ConfirmQuitWithAgents && !m.config.Behavior.ConfirmQuit {
+		return quit()
 	}
 
-	return cmd
+	m.showConfirm = true
+	m.confirmMsg = quitSummary(runningCount, editorCount, dirty)
+	m.confirmFn = func() tea.Cmd {
+		m.showConfirm = false
+		return quit()
+	}
+	return nil
 }
 
-func (m *Model) getFilteredBlockerCandidates() []*board.Ticket {
-	filterVal := m.blockerFilterInput.Value()
-	if filterVal == "" {
-		return m.blockerCandidates
-	}
+// maxQuitSummaryTickets caps how many tickets the quit dialog lists.
+const maxQuitSummaryTickets = 5
 
-	var visible []*board.Ticket
-	for _, t := range m.blockerCandidates {
-		if strings.Contains(strings.ToLower(t.Title), strings.ToLower(filterVal)) {
-			visible = append(visible, t)
+// quitSummary builds the pre-quit confirmation listing running agents, open
+// editors and tickets whose worktrees still have uncommitted changes.
+func quitSummary(runningCount, editorCount int, dirty []*board.Ticket) string {
+	var b strings.Builder
+	if runningCount > 0 {
+		fmt.Fprintf(&b, "%d agent(s) running.\n", runningCount)
+	}
+	if editorCount > 0 {
+		fmt.Fprintf(&b, "%d editor(s) open.\n", editorCount)
+	}
+	if len(dirty) > 0 {
+		fmt.Fprintf(&b, "Uncommitted changes in %d worktree(s):\n", len(dirty))
+		for i, t := range dirty {
+			if i == maxQuitSummaryTickets {
+				fmt.Fprintf(&b, "    …and %d more\n", len(dirty)-i)
+				break
+			}
+			fmt.Fprintf(&b, "    • %s\n", t.Title)
 		}
 	}
-	return visible
+	b.WriteString("Quit anyway? [y/N]")
+	return b.String()
 }
 
-func (m *Model) initBlockerCandidates(excludeTicketID board.TicketID) {
-	m.blockerCandidates = nil
-	for _, ticket := range m.globalStore.All() {
-		if ticket.ID == excludeTicketID {
+// checkUncommittedChangesAsync looks for tickets whose worktree has
+// uncommitted changes off the UI goroutine, reporting them in a
+// quitCheckMsg. A worktree shared by several tickets is checked once.
+func (m *Model) checkUncommittedChangesAsync() tea.Cmd {
+	type worktreeCheck struct {
+		ticket *board.Ticket
+		path   string
+		mgr    *git.WorktreeManager
+	}
+	var checks []worktreeCheck
+	checked := make(map[string]bool)
+	for _, t := range m.globalStore.All() {
+		if t.WorktreePath == "" || checked[t.WorktreePath] {
 			continue
 		}
-		if ticket.Status == board.StatusArchived {
+		checked[t.WorktreePath] = true
+		proj := m.globalStore.GetProjectForTicket(t)
+		if proj == nil {
 			continue
 		}
-		m.blockerCandidates = append(m.blockerCandidates, ticket)
+		if mgr := m.worktreeMgrs[proj.ID]; mgr != nil {
+			checks = append(checks, worktreeCheck{ticket: t, path: t.WorktreePath, mgr: mgr})
+		}
 	}
-	sort.Slice(m.blockerCandidates, func(i, j int) bool {
-		return m.blockerCandidates[i].Title < m.blockerCandidates[j].Title
-	})
-}
 
-func (m *Model) collectSelectedBlockers() []board.TicketID {
-	var blockers []board.TicketID
-	for id := range m.selectedBlockers {
-		blockers = append(blockers, id)
+	return func() tea.Msg {
+		var dirty []*board.Ticket
+		for _, c := range checks {
+			if _, err := os.Stat(c.path); err != nil {
+				continue
+			}
+			if has, err := c.mgr.HasUncommittedChanges(c.path); err == nil && has {
+				dirty = append(dirty, c.ticket)
+			}
+		}
+		return quitCheckMsg{dirty: dirty}
 	}
-	sort.Slice(blockers, func(i, j int) bool {
-		return string(blockers[i]) < string(blockers[j])
-	})
-	return blockers
 }
 
-func (m *Model) confirmDeleteProject(p *project.Project) {
-	ticketCount := 0
-	for _, t := range m.globalStore.All() {
-		if t.ProjectID == p.ID {
-			ticketCount++
-		}
+func (m *Model) cleanupAsync() tea.Cmd {
+	return func() tea.Msg {
+		m.Cleanup()
+		return shutdownCompleteMsg{}
 	}
+}
 
-	if ticketCount > 0 {
-		m.confirmMsg = fmt.Sprintf("Delete '%s' and its %d ticket(s)?", p.Name, ticketCount)
-	} else {
-		m.confirmMsg = fmt.Sprintf("Delete project '%s'?", p.Name)
+func (m *Model) handleAgentViewMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	pane, ok := m.focusedTerminal()
+	if !ok {
+		m.mode = ModeNormal
+		m.focusedPane = ""
+		m.focusedEditor = false
+		return m, nil
 	}
 
-	m.showConfirm = true
-	m.confirmFn = func() tea.Cmd {
-		if err := m.projectRegistry.Delete(p.ID); err != nil {
-			m.notify("Failed to delete: " + err.Error())
-			return nil
-		}
-
-		m.globalStore.RemoveProject(p.ID)
-		delete(m.worktreeMgrs, p.ID)
+	if msg.String() == "ctrl+]" {
+		return m, m.toggleDiffSplit()
+	}
+	if msg.String() == "ctrl+y" && !m.agentSearching {
+		m.copyAgentOutput(pane)
+		return m, nil
+	}
 
-		projects := m.globalStore.Projects()
-		if len(projects) > 0 {
-			if m.projectListIndex >= len(projects) {
-				m.projectListIndex = len(projects) - 1
+	if m.agentSearching {
+		return m.handleAgentSearch(msg, pane)
+	}
+	// While scrolled back, / searches the scrollback and n/N step through
+	// the matches; otherwise keys go to the agent.
+	if pane.ViewportOffset() > 0 {
+		switch msg.String() {
+		case "/":
+			m.agentSearching = true
+			m.agentSearchInput.SetValue(pane.SearchQuery())
+			m.agentSearchInput.Focus()
+			return m, textinput.Blink
+		case "n":
+			if pane.SearchQuery() != "" {
+				pane.SearchNext()
+				return m, nil
+			}
+		case "N":
+			if pane.SearchQuery() != "" {
+				pane.SearchPrev()
+				return m, nil
 			}
-			m.selectedProject = projects[m.projectListIndex]
-		} else {
-			m.selectedProject = nil
 		}
-
-		delete(m.filterProjectIDs, p.ID)
-
-		m.notify("Deleted: " + p.Name)
-		return nil
 	}
-}
-
-func (m *Model) handleProjectSelection() (tea.Model, tea.Cmd) {
-	projects := m.globalStore.Projects()
 
-	if m.showAddProjectForm {
-		return m.createProjectFromPath()
+	if result := pane.HandleKey(msg); result != nil {
+		if _, isExit := result.(terminal.ExitFocusMsg); isExit {
+			m.mode = ModeNormal
+			m.focusedPane = ""
+			m.focusedEditor = false
+		}
 	}
 
-	if m.projectListIndex < len(projects) {
-		m.selectedProject = projects[m.projectListIndex]
+	return m, nil
+}
+
+// handleAgentSearch edits the scrollback search query. Enter searches and
+// jumps to the newest match; Esc cancels without changing the search.
+func (m *Model) handleAgentSearch(msg tea.KeyMsg, pane *terminal.Pane) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.agentSearching = false
+		m.agentSearchInput.Blur()
+		if query := m.agentSearchInput.Value(); query != "" {
+			pane.Search(query)
+		} else {
+			pane.ClearSearch()
+		}
+		return m, nil
+	case "esc":
+		m.agentSearching = false
+		m.agentSearchInput.Blur()
 		return m, nil
 	}
 
-	m.showAddProjectForm = true
-	m.addProjectPath.SetValue("")
-	m.addProjectPath.Focus()
-	return m, textinput.Blink
+	var cmd tea.Cmd
+	m.agentSearchInput, cmd = m.agentSearchInput.Update(msg)
+	return m, cmd
 }
 
-func (m *Model) createProjectFromPath() (tea.Model, tea.Cmd) {
-	path := strings.TrimSpace(m.addProjectPath.Value())
-	if path == "" {
-		m.notify("Path cannot be empty")
+func (m *Model) handleAgentViewMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	pane, ok := m.focusedTerminal()
+	if !ok {
 		return m, nil
 	}
 
-	if strings.HasPrefix(path, "~/") {
-		home, err := os.UserHomeDir()
-		if err == nil {
-			path = filepath.Join(home, path[2:])
+	if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+		if msg.Y == 0 && msg.X >= m.width-25 {
+			m.mode = ModeNormal
+			return m, nil
 		}
 	}
 
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		m.notify("Invalid path: " + err.Error())
-		return m, nil
-	}
+	pane.HandleMouse(msg)
+	return m, nil
+}
 
-	gitDir := filepath.Join(absPath, ".git")
-	if _, err := os.Stat(gitDir); err != nil {
-		m.notify("Not a git repository")
+func (m *Model) handleTicketFormMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.formScrollOffset -= 3
+		if m.formScrollOffset < 0 {
+			m.formScrollOffset = 0
+		}
+		return m, nil
+	case tea.MouseButtonWheelDown:
+		m.formScrollOffset += 3
 		return m, nil
 	}
 
-	name := filepath.Base(absPath)
-
-	newProject := project.NewProject(name, absPath)
-	// Project settings only store explicit user overrides.
-	// Empty values cascade to global config via getDefaultAgent() and GetBranchPrefix().
+	formWidth := 50
+	formLeft := (m.width - formWidth) / 2
+	formRight := formLeft + formWidth
 
-	if err := m.projectRegistry.Add(newProject); err != nil {
-		m.notify("Failed to save: " + err.Error())
+	if msg.X < formLeft || msg.X > formRight {
 		return m, nil
 	}
 
-	m.globalStore.AddProject(newProject)
-	m.worktreeMgrs[newProject.ID] = git.NewWorktreeManager(newProject)
-	m.selectedProject = newProject
-	m.showAddProjectForm = false
-	m.addProjectPath.Blur()
-	m.projectListIndex = len(m.globalStore.Projects()) - 1
+	formTop := (m.height - 28) / 2
+	relY := msg.Y - formTop
 
-	if m.mode == ModeCreateProject {
-		m.mode = ModeNormal
+	var clickedField int = -1
+	switch {
+	case relY >= 3 && relY <= 4:
+		clickedField = formFieldTitle
+	case relY >= 6 && relY <= 9:
+		clickedField = formFieldDescription
+	case relY >= 11 && relY <= 13:
+		clickedField = formFieldBranch
+	case relY >= 15 && relY <= 17:
+		clickedField = formFieldBaseBranch
+	case relY >= 19 && relY <= 21:
+		clickedField = formFieldLabels
+	case relY >= 23 && relY <= 25:
+		clickedField = formFieldPriority
+	case relY >= 27 && relY <= 29:
+		clickedField = formFieldDueDate
+	case relY >= 31:
+		clickedField = formFieldProject
+	}
+	if (clickedField == formFieldBranch || clickedField == formFieldBaseBranch) && m.branchLocked {
+		clickedField = -1
 	}
 
-	m.notify("Added project: " + name)
-	return m, nil
-}
-
-func (m *Model) nextFormField(isEdit bool) *Model {
-	m.blurAllFormFields()
-	m.ticketFormField++
+	if clickedField >= 0 && msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+		m.blurAllFormFields()
+		m.ticketFormField = clickedField
+		m.focusCurrentField()
 
-	maxField := formFieldBlockedBy
-	if !isEdit {
-		maxField = formFieldProject
+		if clickedField == formFieldProject && !m.showAddProjectForm {
+			projects := m.globalStore.Projects()
+			projectRelY := relY - 32
+			if projectRelY >= 0 && projectRelY <= len(projects) {
+				m.projectListIndex = projectRelY
+				if projectRelY == len(projects) {
+					m.showAddProjectForm = true
+					m.addProjectPath.SetValue("")
+					m.addProjectPath.Focus()
+					return m, textinput.Blink
+				}
+				if projectRelY < len(projects) {
+					m.selectedProject = projects[projectRelY]
+					m.syncBaseBranchPrefill()
+				}
+			}
+		}
 	}
 
-	for {
-		if m.ticketFormField > maxField {
-			m.ticketFormField = formFieldTitle
-		}
-		if m.ticketFormField == formFieldBranch && m.branchLocked {
-			m.ticketFormField++
-			continue
+	var cmd tea.Cmd
+	switch m.ticketFormField {
+	case formFieldTitle:
+		m.titleInput, cmd = m.titleInput.Update(msg)
+	case formFieldDescription:
+		m.descInput, cmd = m.descInput.Update(msg)
+	case formFieldBranch:
+		if !m.branchLocked {
+			m.branchInput, cmd = m.branchInput.Update(msg)
 		}
-		if m.ticketFormField == formFieldAgent && m.agentLocked {
-			m.ticketFormField++
-			continue
+	case formFieldBaseBranch:
+		if !m.branchLocked {
+			m.baseBranchInput, cmd = m.baseBranchInput.Update(msg)
 		}
-		break
+	case formFieldLabels:
+		m.labelsInput, cmd = m.labelsInput.Update(msg)
 	}
-	m.focusCurrentField()
-	return m
+
+	return m, cmd
 }
 
-func (m *Model) prevFormField(isEdit bool) *Model {
-	m.blurAllFormFields()
-	m.ticketFormField--
+func (m *Model) handleCreateTicketMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	model, cmd := m.handleTicketForm(msg, false)
+	return model, tea.Batch(cmd, m.loadFormBranches())
+}
 
-	maxField := formFieldBlockedBy
-	if !isEdit {
-		maxField = formFieldProject
-	}
+func (m *Model) handleEditTicketMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	model, cmd := m.handleTicketForm(msg, true)
+	return model, tea.Batch(cmd, m.loadFormBranches())
+}
 
-	for {
-		if m.ticketFormField < formFieldTitle {
-			m.ticketFormField = maxField
-		}
-		if m.ticketFormField == formFieldBranch && m.branchLocked {
-			m.ticketFormField--
-			continue
-		}
-		if m.ticketFormField == formFieldAgent && m.agentLocked {
-			m.ticketFormField--
-			continue
+// loadFormBranches lists the branches of the ticket form's project in the
+// background, unless they are loaded or loading already. It is called
+// whenever the form may have changed project.
+func (m *Model) loadFormBranches() tea.Cmd {
+	if (m.mode != ModeCreateTicket && m.mode != ModeEditTicket) || m.selectedProject == nil {
+		return nil
+	}
+	projectID := m.selectedProject.ID
+	if m.branchOptionsProject == projectID || m.branchOptionsLoading == projectID {
+		return nil
+	}
+	mgr := m.worktreeMgrs[projectID]
+	if mgr == nil {
+		return nil
+	}
+	m.branchOptionsLoading = projectID
+	return func() tea.Msg {
+		msg := branchListMsg{projectID: projectID}
+		if msg.branches, msg.err = mgr.ListBranches(); msg.err == nil {
+			msg.remote, msg.err = mgr.ListRemoteBranches()
 		}
-		break
+		return msg
 	}
-	m.focusCurrentField()
-	return m
 }
 
-func (m *Model) blurAllFormFields() {
-	m.titleInput.Blur()
-	m.descInput.Blur()
-	m.branchInput.Blur()
-	m.labelsInput.Blur()
-	m.blockerFilterInput.Blur()
-	m.projectInput.Blur()
+// handleBranchList keeps the branches loaded by loadFormBranches, unless the
+// form has moved on to another project since.
+func (m *Model) handleBranchList(msg branchListMsg) {
+	if msg.projectID != m.branchOptionsLoading {
+		return
+	}
+	m.branchOptionsLoading = ""
+	m.branchOptionsProject = msg.projectID
+	m.branchOptions = msg.branches
+	m.remoteBranches = msg.remote
+	m.branchOptionIndex = -1
+	m.branchOptionsErr = msg.err
 }
 
-func (m *Model) focusCurrentField() {
-	switch m.ticketFormField {
-	case formFieldTitle:
-		m.titleInput.Focus()
-	case formFieldDescription:
-		m.descInput.Focus()
-	case formFieldBranch:
-		m.branchInput.Focus()
-	case formFieldLabels:
-		m.labelsInput.Focus()
-	case formFieldPriority:
-		break
-	case formFieldWorktree:
-		break
-	case formFieldBlockedBy:
-		m.blockerFilterInput.Focus()
-	case formFieldProject:
-		m.projectInput.Focus()
+// formBranchesReady reports whether the form's project's branches are
+// loaded, notifying why not otherwise.
+func (m *Model) formBranchesReady() bool {
+	switch {
+	case m.selectedProject == nil:
+		m.notify("No project selected")
+	case m.worktreeMgrs[m.selectedProject.ID] == nil:
+		m.notify("Worktree manager not found")
+	case m.branchOptionsProject != m.selectedProject.ID:
+		m.notify("Branches are still loading")
+	case m.branchOptionsErr != nil:
+		m.notify("Failed to list branches: " + m.branchOptionsErr.Error())
+	default:
+		return true
 	}
+	return false
 }
 
-func (m *Model) saveTicketForm(isEdit bool) (tea.Model, tea.Cmd) {
-	title := strings.TrimSpace(m.titleInput.Value())
-	if title == "" {
-		m.notify("Title cannot be empty")
+func (m *Model) handleTicketForm(msg tea.KeyMsg, isEdit bool) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.mode = ModeNormal
+		m.blurAllFormFields()
+		m.editingTicketID = ""
+		m.branchLocked = false
+		m.showAddProjectForm = false
 		return m, nil
-	}
 
-	if m.selectedProject == nil {
-		m.notify("No project selected")
-		return m, nil
-	}
+	case "tab":
+		if m.showAddProjectForm && m.addProjectPath.Value() != "" {
+			m.createProjectFromPath()
+			if m.showAddProjectForm {
+				return m, nil
+			}
+		} else if m.showAddProjectForm {
+			m.showAddProjectForm = false
+		}
+		return m.nextFormField(isEdit), nil
+	case "shift+tab":
+		if m.showAddProjectForm {
+			m.showAddProjectForm = false
+		}
+		return m.prevFormField(isEdit), nil
 
-	desc := strings.TrimSpace(m.descInput.Value())
-	branchName := strings.TrimSpace(m.branchInput.Value())
-	if branchName == "" {
-		branchName = m.generateBranchNameFromTitle(title, m.selectedProject)
-	}
+	case "ctrl+s":
+		return m.saveTicketForm(isEdit)
 
-	labels := m.parseLabels(m.labelsInput.Value())
+	case "ctrl+b":
+		if !isEdit && m.ticketFormField == formFieldBranch {
+			m.cycleExistingBranch()
+			return m, nil
+		}
 
-	blockedBy := m.collectSelectedBlockers()
+	case "ctrl+t":
+		if !isEdit {
+			m.cycleTicketTemplate()
+			return m, nil
+		}
 
-	if isEdit && m.editingTicketID != "" {
-		ticket, _ := m.globalStore.Get(m.editingTicketID)
-		if ticket != nil {
-			ticket.Title = title
-			ticket.Description = desc
-			if !m.branchLocked {
-				ticket.BranchName = branchName
-			}
-			ticket.Labels = labels
-			ticket.Priority = m.ticketPriority
-			ticket.UseWorktree = m.ticketUseWorktree
-			if !m.agentLocked {
-				ticket.AgentType = m.ticketAgent
-			}
-			ticket.BlockedBy = blockedBy
-			ticket.Touch()
-			m.saveTicket(ticket)
-			m.refreshColumnTickets()
-			m.notify("Updated: " + title)
+	case "enter":
+		if m.ticketFormField == formFieldTitle {
+			return m.saveTicketForm(isEdit)
+		}
+		if m.ticketFormField == formFieldProject && m.showProjectField(isEdit) {
+			return m.handleProjectSelection()
 		}
-	} else {
-		ticket := board.NewTicket(title, m.selectedProject.ID)
-		ticket.Description = desc
-		ticket.BranchName = branchName
-		ticket.Labels = labels
-		ticket.Priority = m.ticketPriority
-		ticket.UseWorktree = m.ticketUseWorktree
-		ticket.AgentType = m.ticketAgent
-		ticket.BlockedBy = blockedBy
-		ticket.Status = m.columns[m.activeColumn].Status
-		m.globalStore.Add(ticket)
-		m.refreshColumnTickets()
-		m.selectTicketByID(ticket.ID)
-		m.saveTicket(ticket)
-		m.notify("Created: " + title)
-	}
-
-	m.mode = ModeNormal
-	m.blurAllFormFields()
-	m.editingTicketID = ""
-	m.branchLocked = false
-	return m, nil
-}
 
-func (m *Model) parseLabels(input string) []string {
-	if strings.TrimSpace(input) == "" {
-		return []string{}
-	}
-	parts := strings.Split(input, ",")
-	var labels []string
-	for _, p := range parts {
-		label := strings.TrimSpace(p)
-		if label != "" {
-			labels = append(labels, label)
+	case "esc":
+		if m.showAddProjectForm {
+			m.showAddProjectForm = false
+			m.addProjectPath.Blur()
+			return m, nil
 		}
+		m.mode = ModeNormal
+		m.blurAllFormFields()
+		m.editingTicketID = ""
+		m.branchLocked = false
+		return m, nil
 	}
-	return labels
-}
 
-type settingsField struct {
-	key         string
-	label       string
-	kind        string
-	description string
-}
-
-var settingsFields = []settingsField{
-	{"theme", "Theme", "theme", "Color theme for the UI"},
-	{"default_agent", "Default Agent", "agent", "Agent to spawn for new tickets (opencode, claude, aider)"},
-	{"confirm_quit", "Confirm Quit", "toggle", "Prompt before quitting with running agents"},
-	{"branch_prefix", "Branch Prefix", "text", "Prefix for auto-generated branch names (e.g. task/, feature/)"},
-	{"delete_worktree", "Delete Worktree", "toggle", "Remove git worktree when deleting tickets"},
-	{"delete_branch", "Delete Branch", "toggle", "Delete git branch when deleting tickets"},
-	{"force_cleanup", "Force Cleanup", "toggle", "Force worktree removal even with uncommitted changes"},
-	{"sidebar_visible", "Show Sidebar", "toggle", "Toggle the project sidebar visibility"},
-	{"filter_project", "Filter Project", "project", "Show only tickets from a specific project"},
-}
-
-func (m *Model) handleSettingsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	if m.settingsEditing {
-		return m.handleSettingsEdit(msg)
+	var cmd tea.Cmd
+	switch m.ticketFormField {
+	case formFieldTitle:
+		m.titleInput, cmd = m.titleInput.Update(msg)
+	case formFieldDescription:
+		m.descInput, cmd = m.descInput.Update(msg)
+	case formFieldBranch:
+		if !m.branchLocked && !m.linkedBranch {
+			m.branchInput, cmd = m.branchInput.Update(msg)
+		}
+	case formFieldBaseBranch:
+		if !m.branchLocked {
+			m.baseBranchInput, cmd = m.baseBranchInput.Update(msg)
+		}
+	case formFieldLabels:
+		m.labelsInput, cmd = m.labelsInput.Update(msg)
+	case formFieldPriority:
+		cmd = m.handlePriorityNav(msg)
+	case formFieldDueDate:
+		m.dueInput, cmd = m.dueInput.Update(msg)
+	case formFieldWorktree:
+		cmd = m.handleWorktreeToggle(msg)
+	case formFieldAgent:
+		if !m.agentLocked {
+			cmd = m.handleAgentNav(msg)
+		}
+	case formFieldAgentArgs:
+		m.agentArgsInput, cmd = m.agentArgsInput.Update(msg)
+	case formFieldContextFiles:
+		m.contextFilesInput, cmd = m.contextFilesInput.Update(msg)
+	case formFieldBlockedBy:
+		cmd = m.handleBlockerNav(msg)
+	case formFieldWorktreePath:
+		if !m.worktreePathLocked {
+			m.worktreePathInput, cmd = m.worktreePathInput.Update(msg)
+		}
+	case formFieldProject:
+		if m.showAddProjectForm {
+			m.addProjectPath, cmd = m.addProjectPath.Update(msg)
+		} else {
+			cmd = m.handleProjectListNav(msg)
+		}
 	}
+	return m, cmd
+}
 
+func (m *Model) handlePriorityNav(msg tea.KeyMsg) tea.Cmd {
 	switch msg.String() {
-	case "j", "down":
-		m.settingsIndex++
-		if m.settingsIndex >= len(settingsFields) {
-			m.settingsIndex = len(settingsFields) - 1
+	case "j", "down", "l", "right":
+		m.ticketPriority++
+		if m.ticketPriority > 5 {
+			m.ticketPriority = 1
 		}
-	case "k", "up":
-		m.settingsIndex--
-		m.settingsIndex = max(m.settingsIndex, 0)
-	case "enter", " ":
-		return m.enterSettingsEdit()
-	case "esc", "q":
-		m.mode = ModeNormal
+	case "k", "up", "h", "left":
+		m.ticketPriority--
+		if m.ticketPriority < 1 {
+			m.ticketPriority = 5
+		}
+	case "1", "2", "3", "4", "5":
+		m.ticketPriority = int(msg.String()[0] - '0')
 	}
-	return m, nil
+	return nil
 }
 
-func (m *Model) handleSettingsEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	field := settingsFields[m.settingsIndex]
-
-	if field.kind == "project" {
-		m.filterInput.SetValue(m.filterQuery)
-		m.filterInput.Focus()
-		m.mode = ModeFilter
-		return m, textinput.Blink
+func (m *Model) handleWorktreeToggle(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case " ", "enter", "h", "l", "left", "right":
+		m.ticketUseWorktree = !m.ticketUseWorktree
+	case "y", "Y":
+		m.ticketUseWorktree = true
+	case "n", "N":
+		m.ticketUseWorktree = false
 	}
+	return nil
+}
 
-	if field.kind == "theme" {
-		return m.handleThemeNav(msg)
+func (m *Model) handleAgentNav(msg tea.KeyMsg) tea.Cmd {
+	agents := m.getAgentNames()
+	if len(agents) == 0 {
+		return nil
 	}
 
 	switch msg.String() {
-	case "enter":
-		m.applySettingsValue(field.key, m.settingsInput.Value())
-		m.settingsEditing = false
-		m.settingsInput.Blur()
-		m.notify("Settings saved")
-		return m, nil
-	case "esc":
-		m.settingsEditing = false
-		m.settingsInput.Blur()
-		return m, nil
+	case "j", "down", "l", "right":
+		m.agentListIndex++
+		if m.agentListIndex >= len(agents) {
+			m.agentListIndex = 0
+		}
+	case "k", "up", "h", "left":
+		m.agentListIndex--
+		if m.agentListIndex < 0 {
+			m.agentListIndex = len(agents) - 1
+		}
 	}
-
-	var cmd tea.Cmd
-	m.settingsInput, cmd = m.settingsInput.Update(msg)
-	return m, cmd
+	m.ticketAgent = agents[m.agentListIndex]
+	return nil
 }
 
-func (m *Model) handleThemeNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	themes := config.ThemeNames()
-	if len(themes) == 0 {
-		return m, nil
-	}
+func (m *Model) handleProjectListNav(msg tea.KeyMsg) tea.Cmd {
+	projects := m.globalStore.Projects()
+	maxIndex := len(projects)
 
 	switch msg.String() {
 	case "j", "down":
-		m.themeListIndex++
-		if m.themeListIndex >= len(themes) {
-			m.themeListIndex = 0
+		m.projectListIndex++
+		if m.projectListIndex > maxIndex {
+			m.projectListIndex = 0
 		}
-		m.applySettingsValue("theme", themes[m.themeListIndex])
 	case "k", "up":
-		m.themeListIndex--
-		if m.themeListIndex < 0 {
-			m.themeListIndex = len(themes) - 1
+		m.projectListIndex--
+		if m.projectListIndex < 0 {
+			m.projectListIndex = maxIndex
+		}
+	case "d":
+		if m.projectListIndex < len(projects) {
+			m.confirmDeleteProject(projects[m.projectListIndex])
 		}
-		m.applySettingsValue("theme", themes[m.themeListIndex])
-	case "enter":
-		m.settingsEditing = false
-		m.notify("Theme: " + themes[m.themeListIndex])
-	case "esc":
-		m.settingsEditing = false
-	}
-
-	return m, nil
-}
-
-func (m *Model) handleSettingsMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
-	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
-		return m, nil
 	}
 
-	formTop := (m.height - 10) / 2
-	relY := msg.Y - formTop - 3
-
-	if relY >= 0 && relY < len(settingsFields) {
-		m.settingsIndex = relY
-		return m.enterSettingsEdit()
+	// Auto-select the highlighted project (if not on "+ Add project" option)
+	if m.projectListIndex < len(projects) {
+		m.selectedProject = projects[m.projectListIndex]
+		m.syncBaseBranchPrefill()
 	}
 
-	return m, nil
+	return nil
 }
 
-func (m *Model) handleConfirmMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
-	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
-		return m, nil
-	}
-
-	formCenterY := m.height / 2
-	formCenterX := m.width / 2
-
-	yesX := formCenterX - 10
-	noX := formCenterX + 5
+func (m *Model) handleBlockerNav(msg tea.KeyMsg) tea.Cmd {
+	visibleCandidates := m.getFilteredBlockerCandidates()
 
-	if msg.Y == formCenterY+2 {
-		if msg.X >= yesX && msg.X <= yesX+5 {
-			m.showConfirm = false
-			if m.confirmFn != nil {
-				return m, m.confirmFn()
+	switch msg.Type {
+	case tea.KeyDown, tea.KeyCtrlN:
+		if len(visibleCandidates) > 0 {
+			m.blockerListIndex++
+			if m.blockerListIndex >= len(visibleCandidates) {
+				m.blockerListIndex = 0
 			}
 		}
-		if msg.X >= noX && msg.X <= noX+4 {
-			m.showConfirm = false
+		return nil
+	case tea.KeyUp, tea.KeyCtrlP:
+		if len(visibleCandidates) > 0 {
+			m.blockerListIndex--
+			if m.blockerListIndex < 0 {
+				m.blockerListIndex = len(visibleCandidates) - 1
+			}
+		}
+		return nil
+	case tea.KeySpace, tea.KeyEnter:
+		if m.blockerListIndex < len(visibleCandidates) {
+			ticket := visibleCandidates[m.blockerListIndex]
+			if m.selectedBlockers[ticket.ID] {
+				delete(m.selectedBlockers, ticket.ID)
+			} else {
+				m.selectedBlockers[ticket.ID] = true
+			}
 		}
+		return nil
 	}
 
-	return m, nil
-}
+	var cmd tea.Cmd
+	m.blockerFilterInput, cmd = m.blockerFilterInput.Update(msg)
 
-func (m *Model) enterSettingsEdit() (tea.Model, tea.Cmd) {
-	field := settingsFields[m.settingsIndex]
+	newVisible := m.getFilteredBlockerCandidates()
+	if m.blockerListIndex >= len(newVisible) && len(newVisible) > 0 {
+		m.blockerListIndex = len(newVisible) - 1
+	} else if len(newVisible) == 0 {
+		m.blockerListIndex = 0
+	}
 
-	switch field.kind {
-	case "project":
-		m.filterInput.SetValue(m.filterQuery)
-		m.filterInput.Focus()
-		m.mode = ModeFilter
-		return m, textinput.Blink
+	return cmd
+}
 
-	case "toggle":
-		m.applySettingsValue(field.key, "")
-		status := m.getSettingsValue(field.key)
-		m.notify(field.label + ": " + status)
-		return m, nil
+func (m *Model) getFilteredBlockerCandidates() []*board.Ticket {
+	filterVal := m.blockerFilterInput.Value()
+	if filterVal == "" {
+		return m.blockerCandidates
+	}
 
-	case "theme":
-		themes := config.ThemeNames()
-		current := m.config.UI.Theme
-		m.themeListIndex = 0
-		for i, t := range themes {
-			if t == current {
-				m.themeListIndex = i
-				break
-			}
+	var visible []*board.Ticket
+	for _, t := range m.blockerCandidates {
+		if strings.Contains(strings.ToLower(t.Title), strings.ToLower(filterVal)) {
+			visible = append(visible, t)
 		}
-		m.settingsEditing = true
-		return m, nil
+	}
+	return visible
+}
 
-	case "agent":
-		agents := m.getAgentNames()
-		current := m.config.Defaults.DefaultAgent
-		currentIndex := 0
-		for i, a := range agents {
-			if a == current {
-				currentIndex = i
-				break
-			}
+func (m *Model) initBlockerCandidates(excludeTicketID board.TicketID) {
+	m.blockerCandidates = nil
+	for _, ticket := range m.globalStore.All() {
+		if ticket.ID == excludeTicketID {
+			continue
 		}
-		nextIndex := (currentIndex + 1) % len(agents)
-		nextAgent := agents[nextIndex]
-		m.applySettingsValue(field.key, nextAgent)
-		m.notify("Default agent: " + nextAgent)
-		return m, nil
+		if ticket.Status == board.StatusArchived {
+			continue
+		}
+		m.blockerCandidates = append(m.blockerCandidates, ticket)
+	}
+	sort.Slice(m.blockerCandidates, func(i, j int) bool {
+		return m.blockerCandidates[i].Title < m.blockerCandidates[j].Title
+	})
+}
 
-	case "text":
-		m.settingsEditing = true
-		m.settingsInput.SetValue(m.getSettingsValue(field.key))
-		m.settingsInput.Focus()
-		return m, textinput.Blink
+func (m *Model) collectSelectedBlockers() []board.TicketID {
+	var blockers []board.TicketID
+	for id := range m.selectedBlockers {
+		blockers = append(blockers, id)
+	}
+	sort.Slice(blockers, func(i, j int) bool {
+		return string(blockers[i]) < string(blockers[j])
+	})
+	return blockers
+}
 
-	default:
-		m.settingsEditing = true
-		m.settingsInput.SetValue(m.getSettingsValue(field.key))
-		m.settingsInput.Focus()
-		return m, textinput.Blink
+// projectTicketAction is what happens to a project's tickets when the
+// project is removed.
+type projectTicketAction int
+
+const (
+	projectTicketsArchive projectTicketAction = iota
+	projectTicketsDelete
+	projectTicketsReassign
+)
+
+// confirmDeleteProject asks before removing a project. Projects with tickets
+// get a dialog to archive, delete or reassign them instead of a plain confirm.
+func (m *Model) confirmDeleteProject(p *project.Project) {
+	if m.denyReadOnly() {
+		return
+	}
+	if len(m.globalStore.ProjectTickets(p.ID)) > 0 {
+		m.removingProject = p
+		m.removeTargetIndex = 0
+		return
+	}
+
+	m.showConfirm = true
+	m.confirmMsg = fmt.Sprintf("Delete project '%s'?", p.Name)
+	m.confirmFn = func() tea.Cmd {
+		m.removeProject(p, projectTicketsArchive, nil)
+		return nil
 	}
 }
 
-func (m *Model) getSettingsValue(key string) string {
-	switch key {
-	case "theme":
-		return m.config.UI.Theme
-	case "default_agent":
-		return m.config.Defaults.DefaultAgent
-	case "confirm_quit":
-		if m.config.Behavior.ConfirmQuitWithAgents {
-			return "On"
-		}
-		return "Off"
-	case "branch_prefix":
-		return m.config.Defaults.BranchPrefix
-	case "delete_worktree":
-		if m.config.Cleanup.DeleteWorktree {
-			return "On"
+// removeTargets lists the projects that can take over the tickets of the
+// project being removed.
+func (m *Model) removeTargets() []*project.Project {
+	var targets []*project.Project
+	for _, p := range m.globalStore.Projects() {
+		if m.removingProject != nil && p.ID != m.removingProject.ID {
+			targets = append(targets, p)
 		}
-		return "Off"
-	case "delete_branch":
-		if m.config.Cleanup.DeleteBranch {
-			return "On"
+	}
+	return targets
+}
+
+func (m *Model) handleRemoveProjectDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.removingProject
+	targets := m.removeTargets()
+
+	switch msg.String() {
+	case "a", "enter":
+		m.removingProject = nil
+		m.removeProject(p, projectTicketsArchive, nil)
+	case "d":
+		m.removingProject = nil
+		count := len(m.globalStore.ProjectTickets(p.ID))
+		m.showConfirm = true
+		m.confirmMsg = fmt.Sprintf("Permanently delete '%s' and its %d ticket(s)?", p.Name, count)
+		m.confirmFn = func() tea.Cmd {
+			m.removeProject(p, projectTicketsDelete, nil)
+			return nil
 		}
-		return "Off"
-	case "force_cleanup":
-		if m.config.Cleanup.ForceWorktreeRemoval {
-			return "On"
+	case "r":
+		if len(targets) == 0 {
+			m.notify("No other project to reassign tickets to")
+			return m, nil
 		}
-		return "Off"
-	case "filter_project":
-		count := len(m.filterProjectIDs)
-		if count == 0 {
-			return "All Projects"
+		m.removingProject = nil
+		m.removeProject(p, projectTicketsReassign, targets[m.removeTargetIndex%len(targets)])
+	case "tab", "j", "down":
+		if len(targets) > 0 {
+			m.removeTargetIndex = (m.removeTargetIndex + 1) % len(targets)
 		}
-		return fmt.Sprintf("%d selected", count)
-	case "sidebar_visible":
-		if m.sidebarVisible {
-			return "On"
+	case "shift+tab", "k", "up":
+		if len(targets) > 0 {
+			m.removeTargetIndex = (m.removeTargetIndex - 1 + len(targets)) % len(targets)
 		}
-		return "Off"
+	case "esc", "n", "q":
+		m.removingProject = nil
 	}
-	return ""
+	return m, nil
 }
 
-func (m *Model) applySettingsValue(key, value string) {
-	switch key {
-	case "theme":
-		m.config.UI.Theme = value
-		m.theme = m.config.GetTheme()
-		m.colors = newUIColors(m.theme)
-		m.config.Save("")
-	case "default_agent":
-		m.config.Defaults.DefaultAgent = value
-		m.config.Save("")
-	case "confirm_quit":
-		m.config.Behavior.ConfirmQuitWithAgents = !m.config.Behavior.ConfirmQuitWithAgents
-		m.config.Save("")
-	case "branch_prefix":
-		m.config.Defaults.BranchPrefix = value
-		m.config.Save("")
-	case "delete_worktree":
-		m.config.Cleanup.DeleteWorktree = !m.config.Cleanup.DeleteWorktree
-		m.config.Save("")
-	case "delete_branch":
-		m.config.Cleanup.DeleteBranch = !m.config.Cleanup.DeleteBranch
-		m.config.Save("")
-	case "force_cleanup":
-		m.config.Cleanup.ForceWorktreeRemoval = !m.config.Cleanup.ForceWorktreeRemoval
-		m.config.Save("")
-	case "sidebar_visible":
-		m.sidebarVisible = !m.sidebarVisible
-		m.config.UI.SidebarVisible = m.sidebarVisible
-		if !m.sidebarVisible {
-			m.sidebarFocused = false
+// removeProject unregisters a project after archiving, deleting or
+// reassigning its tickets. Agents running on those tickets are stopped.
+func (m *Model) removeProject(p *project.Project, action projectTicketAction, target *project.Project) {
+	tickets := m.globalStore.ProjectTickets(p.ID)
+	for _, t := range tickets {
+		m.unqueueSpawn(t.ID)
+		if pane, ok := m.panes[t.ID]; ok {
+			pane.Stop()
+			delete(m.panes, t.ID)
+			t.AgentStatus = board.AgentNone
+			m.saveTicket(t)
 		}
-		m.config.Save("")
 	}
-}
 
-func (m *Model) handleFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "enter":
-		m.filterInput.Blur()
-		m.mode = ModeNormal
-		return m, nil
-	case "esc":
-		m.filterQuery = ""
-		m.filterInput.SetValue("")
-		m.filterInput.Blur()
-		m.mode = ModeNormal
-		m.refreshColumnTickets()
-		return m, nil
+	var err error
+	switch action {
+	case projectTicketsDelete:
+		for _, t := range tickets {
+			m.globalStore.RemoveBlockerReferences(t.ID)
+			delete(m.lastSpawns, t.ID)
+			agent.RemoveAgentLog(string(t.ID))
+		}
+		err = m.globalStore.DeleteProjectTickets(p.ID)
+	case projectTicketsReassign:
+		err = m.globalStore.ReassignProjectTickets(p.ID, target.ID)
+	}
+	if err == nil {
+		err = m.globalStore.RemoveProject(p.ID)
+	}
+	if err != nil {
+		m.notify("Failed to delete: " + err.Error())
+		return
 	}
+	if action == projectTicketsDelete {
+		m.globalStore.SaveAll()
+	}
+	delete(m.worktreeMgrs, p.ID)
 
-	var cmd tea.Cmd
-	m.filterInput, cmd = m.filterInput.Update(msg)
-	m.filterQuery = m.filterInput.Value()
+	projects := m.globalStore.Projects()
+	if len(projects) > 0 {
+		if m.projectListIndex >= len(projects) {
+			m.projectListIndex = len(projects) - 1
+		}
+		m.selectedProject = projects[m.projectListIndex]
+	} else {
+		m.selectedProject = nil
+	}
+
+	delete(m.filterProjectIDs, p.ID)
 	m.refreshColumnTickets()
-	return m, cmd
-}
 
-func (m *Model) handleFilterMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-	m.filterInput, cmd = m.filterInput.Update(msg)
-	return m, cmd
+	switch {
+	case len(tickets) == 0:
+		m.notify("Deleted: " + p.Name)
+	case action == projectTicketsDelete:
+		m.notify(fmt.Sprintf("Deleted: %s and %d ticket(s)", p.Name, len(tickets)))
+	case action == projectTicketsReassign:
+		m.notify(fmt.Sprintf("Deleted: %s, moved %d ticket(s) to %s", p.Name, len(tickets), target.Name))
+	default:
+		m.notify(fmt.Sprintf("Deleted: %s, archived %d ticket(s)", p.Name, len(tickets)))
+	}
 }
 
-func (m *Model) handleCreateProjectMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "enter":
-		return m.createProjectFromPath()
-	case "esc":
-		m.mode = ModeNormal
-		m.addProjectPath.Blur()
+func (m *Model) handleProjectSelection() (tea.Model, tea.Cmd) {
+	projects := m.globalStore.Projects()
+
+	if m.showAddProjectForm {
+		return m.createProjectFromPath()
+	}
+
+	if m.projectListIndex < len(projects) {
+		m.selectedProject = projects[m.projectListIndex]
 		return m, nil
-	case "ctrl+c":
-		m.mode = ModeNormal
-		m.addProjectPath.Blur()
+	}
+
+	m.showAddProjectForm = true
+	m.addProjectPath.SetValue("")
+	m.addProjectPath.Focus()
+	return m, textinput.Blink
+}
+
+// copySettingsSource returns the project chosen to copy settings from in the
+// add-project form, or nil.
+func (m *Model) copySettingsSource() *project.Project {
+	projects := m.globalStore.Projects()
+	if m.copySettingsIndex < 1 || m.copySettingsIndex > len(projects) {
+		return nil
+	}
+	return projects[m.copySettingsIndex-1]
+}
+
+func (m *Model) createProjectFromPath() (tea.Model, tea.Cmd) {
+	path := strings.TrimSpace(m.addProjectPath.Value())
+	if path == "" {
+		m.notify("Path cannot be empty")
 		return m, nil
 	}
 
-	var cmd tea.Cmd
-	m.addProjectPath, cmd = m.addProjectPath.Update(msg)
-	return m, cmd
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		m.notify("Invalid path: " + err.Error())
+		return m, nil
+	}
+
+	gitDir := filepath.Join(absPath, ".git")
+	if _, err := os.Stat(gitDir); err != nil {
+		m.notify("Not a git repository")
+		return m, nil
+	}
+
+	name := filepath.Base(absPath)
+
+	newProject := project.NewProject(name, absPath)
+	// Project settings only store explicit user overrides.
+	// Empty values cascade to global config via getDefaultAgent() and GetBranchPrefix().
+	if src := m.copySettingsSource(); src != nil {
+		newProject.CopySettingsFrom(src)
+	}
+	m.copySettingsIndex = 0
+
+	if err := m.projectRegistry.Add(newProject); err != nil {
+		m.notify("Failed to save: " + err.Error())
+		return m, nil
+	}
+
+	m.globalStore.AddProject(newProject)
+	m.worktreeMgrs[newProject.ID] = git.NewWorktreeManager(newProject)
+	m.selectedProject = newProject
+	m.syncBaseBranchPrefill()
+	m.showAddProjectForm = false
+	m.addProjectPath.Blur()
+	m.projectListIndex = len(m.globalStore.Projects()) - 1
+
+	if m.mode == ModeCreateProject {
+		m.mode = ModeNormal
+	}
+
+	m.notify("Added project: " + name)
+	return m, nil
 }
 
-func (m *Model) clearFilter() {
-	m.filterQuery = ""
-	m.filterProjectIDs = make(map[string]bool)
-	m.refreshColumnTickets()
+func (m *Model) nextFormField(isEdit bool) *Model {
+	m.blurAllFormFields()
+	for {
+		m.ticketFormField++
+		if m.ticketFormField > formFieldWorktreePath {
+			m.ticketFormField = formFieldTitle
+		}
+		if m.formFieldEnabled(m.ticketFormField, isEdit) {
+			break
+		}
+	}
+	m.focusCurrentField()
+	return m
 }
 
-func (m *Model) toggleProjectFilter(projectID string) {
-	if m.filterProjectIDs[projectID] {
-		delete(m.filterProjectIDs, projectID)
+func (m *Model) prevFormField(isEdit bool) *Model {
+	m.blurAllFormFields()
+	for {
+		m.ticketFormField--
+		if m.ticketFormField < formFieldTitle {
+			m.ticketFormField = formFieldWorktreePath
+		}
+		if m.formFieldEnabled(m.ticketFormField, isEdit) {
+			break
+		}
+	}
+	m.focusCurrentField()
+	return m
+}
+
+// formFieldEnabled reports whether Tab navigation should stop on field.
+func (m *Model) formFieldEnabled(field int, isEdit bool) bool {
+	switch field {
+	case formFieldBranch:
+		return !m.branchLocked
+	case formFieldBaseBranch:
+		return !m.branchLocked && !m.linkedBranch
+	case formFieldAgent:
+		return !m.agentLocked
+	case formFieldProject:
+		return m.showProjectField(isEdit)
+	case formFieldWorktreePath:
+		return isEdit && !m.worktreePathLocked
+	}
+	return true
+}
+
+// showProjectField reports whether the ticket form offers project selection.
+// Edits only show it when an orphaned ticket needs a new home.
+func (m *Model) showProjectField(isEdit bool) bool {
+	return !isEdit || m.reassignProject
+}
+
+func (m *Model) blurAllFormFields() {
+	m.titleInput.Blur()
+	m.descInput.Blur()
+	m.branchInput.Blur()
+	m.baseBranchInput.Blur()
+	m.labelsInput.Blur()
+	m.dueInput.Blur()
+	m.blockerFilterInput.Blur()
+	m.projectInput.Blur()
+	m.worktreePathInput.Blur()
+	m.agentArgsInput.Blur()
+	m.contextFilesInput.Blur()
+}
+
+func (m *Model) focusCurrentField() {
+	switch m.ticketFormField {
+	case formFieldTitle:
+		m.titleInput.Focus()
+	case formFieldDescription:
+		m.descInput.Focus()
+	case formFieldBranch:
+		m.branchInput.Focus()
+	case formFieldBaseBranch:
+		m.baseBranchInput.Focus()
+	case formFieldLabels:
+		m.labelsInput.Focus()
+	case formFieldPriority:
+		break
+	case formFieldDueDate:
+		m.dueInput.Focus()
+	case formFieldWorktree:
+		break
+	case formFieldBlockedBy:
+		m.blockerFilterInput.Focus()
+	case formFieldProject:
+		m.projectInput.Focus()
+	case formFieldWorktreePath:
+		m.worktreePathInput.Focus()
+	case formFieldAgentArgs:
+		m.agentArgsInput.Focus()
+	case formFieldContextFiles:
+		m.contextFilesInput.Focus()
+	}
+}
+
+func (m *Model) saveTicketForm(isEdit bool) (tea.Model, tea.Cmd) {
+	// linkCmd links a new ticket's branch or checks an edited ticket's
+	// branch for a rename.
+	var linkCmd tea.Cmd
+	title := strings.TrimSpace(m.titleInput.Value())
+	if title == "" {
+		m.notify("Title cannot be empty")
+		return m, nil
+	}
+
+	if !isEdit && m.selectedProject == nil {
+		m.notify("No project selected")
+		return m, nil
+	}
+
+	desc := strings.TrimSpace(m.descInput.Value())
+	branchName := strings.TrimSpace(m.branchInput.Value())
+	if branchName == "" {
+		number := 0
+		if isEdit {
+			if ticket, _ := m.globalStore.Get(m.editingTicketID); ticket != nil {
+				number = ticket.Number
+			}
+		} else {
+			number = m.globalStore.NextTicketNumber(m.selectedProject.ID)
+		}
+		branchName = m.generateBranchNameFromTitle(title, number, m.selectedProject)
+	}
+
+	labels := m.parseLabels(m.labelsInput.Value())
+
+	dueAt, err := board.ParseDueDate(m.dueInput.Value(), time.Now())
+	if err != nil {
+		m.notify("Due: " + err.Error())
+		return m, nil
+	}
+
+	agentArgs, err := agent.SplitArgs(m.agentArgsInput.Value())
+	if err != nil {
+		m.notify("Agent args: " + err.Error())
+		return m, nil
+	}
+
+	contextFiles := m.parseLabels(m.contextFilesInput.Value())
+	if err := m.validateContextFiles(contextFiles, isEdit); err != nil {
+		m.notify("Context files: " + err.Error())
+		return m, nil
+	}
+
+	baseBranch := strings.TrimSpace(m.baseBranchInput.Value())
+	if m.linkedBranch && !isEdit {
+		baseBranch = ""
+		if !m.formBranchesReady() {
+			return m, nil
+		}
+		if !slices.Contains(m.branchOptions, branchName) {
+			m.notify("Branch '" + branchName + "' does not exist")
+			return m, nil
+		}
+	}
+	if baseBranch != "" && !m.branchLocked && m.selectedProject != nil && m.worktreeMgrs[m.selectedProject.ID] != nil {
+		if !m.formBranchesReady() {
+			return m, nil
+		}
+		if !slices.Contains(m.branchOptions, baseBranch) && !slices.Contains(m.remoteBranches, baseBranch) {
+			m.notify("Base branch '" + baseBranch + "' does not exist")
+			return m, nil
+		}
+	}
+
+	blockedBy := m.collectSelectedBlockers()
+	if isEdit && m.globalStore.WouldCreateCycle(m.editingTicketID, blockedBy) {
+		m.notify("Blocked By would create a dependency cycle")
+		return m, nil
+	}
+
+	if isEdit && m.editingTicketID != "" {
+		ticket, _ := m.globalStore.Get(m.editingTicketID)
+		if ticket != nil {
+			var worktree *externalWorktree
+			if !m.worktreePathLocked {
+				var err error
+				worktree, err = m.resolveWorktreePath(ticket, m.worktreePathInput.Value())
+				if err != nil {
+					m.notify("Worktree path: " + err.Error())
+					return m, nil
+				}
+			}
+
+			oldTitle := ticket.Title
+			ticket.Title = title
+			ticket.Description = desc
+			if !m.branchLocked {
+				ticket.BranchName = branchName
+				ticket.BaseBranch = baseBranch
+			}
+			ticket.Labels = labels
+			ticket.Priority = m.ticketPriority
+			if ticket.DueAt == nil || m.dueInput.Value() != ticket.DueAt.Format(board.DueDateLayout) {
+				ticket.DueAt = dueAt
+			}
+			ticket.UseWorktree = m.ticketUseWorktree
+			if !m.agentLocked {
+				ticket.AgentType = m.ticketAgent
+			}
+			ticket.AgentArgs = agentArgs
+			ticket.ContextFiles = contextFiles
+			ticket.BlockedBy = blockedBy
+			if worktree != nil {
+				worktree.apply(ticket)
+			}
+			ticket.Touch()
+			if m.reassignProject && m.selectedProject != nil {
+				if err := m.globalStore.ReassignTicket(ticket.ID, m.selectedProject.ID); err != nil {
+					m.notify("Failed to reassign: " + err.Error())
+				}
+			}
+			m.saveTicket(ticket)
+			m.refreshColumnTickets()
+			m.notify("Updated: " + title)
+			linkCmd = m.checkBranchRename(ticket, oldTitle)
+		}
 	} else {
-		m.filterProjectIDs[projectID] = true
+		ticket := board.NewTicket(title, m.selectedProject.ID)
+		ticket.Description = desc
+		ticket.BranchName = branchName
+		ticket.BaseBranch = baseBranch
+		ticket.Labels = labels
+		ticket.Priority = m.ticketPriority
+		ticket.DueAt = dueAt
+		ticket.UseWorktree = m.ticketUseWorktree || m.linkedBranch
+		ticket.AgentType = m.ticketAgent
+		ticket.AgentArgs = agentArgs
+		ticket.ContextFiles = contextFiles
+		ticket.BlockedBy = blockedBy
+		ticket.Status = m.columns[m.activeColumn].Status
+		if ticket.Status == "" {
+			ticket.Status = board.StatusBacklog
+		}
+		m.globalStore.Add(ticket)
+		m.refreshColumnTickets()
+		m.selectTicketByID(ticket.ID)
+		m.saveTicket(ticket)
+		m.notify("Created: " + title)
+		if m.linkedBranch {
+			linkCmd = m.linkTicketBranch(ticket)
+		}
 	}
-	m.filterQuery = ""
-	m.refreshColumnTickets()
+
+	m.mode = ModeNormal
+	m.blurAllFormFields()
+	m.editingTicketID = ""
+	m.branchLocked = false
+	m.linkedBranch = false
+	return m, linkCmd
 }
 
-func (m *Model) toggleAllProjects() {
-	projects := m.globalStore.Projects()
-	allSelected := len(m.filterProjectIDs) == len(projects) && len(projects) > 0
-	for _, p := range projects {
-		if !m.filterProjectIDs[p.ID] {
-			allSelected = false
-			break
+// cycleExistingBranch fills the Branch field of a new ticket with the next
+// local branch of the form's project, returning to a new branch after the
+// last one.
+func (m *Model) cycleExistingBranch() {
+	if !m.formBranchesReady() {
+		return
+	}
+	if len(m.branchOptions) == 0 {
+		m.notify("No branches in " + m.selectedProject.Name)
+		return
+	}
+
+	m.branchOptionIndex++
+	if m.branchOptionIndex >= len(m.branchOptions) {
+		m.branchOptionIndex = -1
+		m.linkedBranch = false
+		m.branchInput.SetValue("")
+		return
+	}
+	m.linkedBranch = true
+	m.branchInput.SetValue(m.branchOptions[m.branchOptionIndex])
+}
+
+// cycleTicketTemplate fills the new ticket's description with the next
+// configured template, going back to an empty description after the last.
+// A description typed or edited by hand is left alone.
+func (m *Model) cycleTicketTemplate() {
+	names := m.config.TicketTemplateNames()
+	if len(names) == 0 {
+		m.notify("No ticket templates configured (defaults.ticket_templates)")
+		return
+	}
+	if desc := m.descInput.Value(); desc != "" && desc != m.ticketTemplateBody {
+		m.notify("Clear the description to apply a template")
+		return
+	}
+
+	m.ticketTemplateIndex++
+	if m.ticketTemplateIndex >= len(names) {
+		m.ticketTemplateIndex = -1
+		m.ticketTemplateBody = ""
+		m.descInput.SetValue("")
+		return
+	}
+	name := names[m.ticketTemplateIndex]
+	body, err := m.config.RenderTicketTemplate(name, strings.TrimSpace(m.titleInput.Value()))
+	if err != nil {
+		m.ticketTemplateIndex = -1
+		m.ticketTemplateBody = ""
+		m.notify("Template " + name + ": " + err.Error())
+		return
+	}
+	m.ticketTemplateBody = body
+	m.descInput.SetValue(body)
+}
+
+// ticketTemplateName returns the name of the description template applied
+// in the create form, or "" for none.
+func (m *Model) ticketTemplateName() string {
+	names := m.config.TicketTemplateNames()
+	if m.ticketTemplateIndex < 0 || m.ticketTemplateIndex >= len(names) {
+		return ""
+	}
+	if m.descInput.Value() != m.ticketTemplateBody {
+		return ""
+	}
+	return names[m.ticketTemplateIndex]
+}
+
+// linkTicketBranch checks out ticket's existing branch in a worktree in the
+// background, reusing a worktree that already has it.
+func (m *Model) linkTicketBranch(ticket *board.Ticket) tea.Cmd {
+	mgr := m.worktreeMgrs[ticket.ProjectID]
+	if mgr == nil {
+		m.notify("Worktree failed: worktree manager not found")
+		return nil
+	}
+
+	ticketID := ticket.ID
+	projectID := ticket.ProjectID
+	branchName := ticket.BranchName
+	status := ticket.Status
+	m.pendingWorktrees[ticketID] = true
+	m.startGitOp("Creating worktree")
+
+	return func() tea.Msg {
+		path, err := mgr.AttachWorktree(branchName)
+		return worktreeCreatedMsg{
+			ticketID:   ticketID,
+			projectID:  projectID,
+			fromStatus: status,
+			status:     status,
+			path:       path,
+			branchName: branchName,
+			err:        err,
+			linked:     true,
+		}
+	}
+}
+
+// validateContextFiles checks that each context file exists relative to the
+// ticket's worktree, or to its project's repository before the worktree is
+// created.
+func (m *Model) validateContextFiles(files []string, isEdit bool) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	root := ""
+	if isEdit {
+		if ticket, _ := m.globalStore.Get(m.editingTicketID); ticket != nil {
+			root = ticket.WorktreePath
+			if root == "" {
+				if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+					root = proj.RepoPath
+				}
+			}
+		}
+	} else if m.selectedProject != nil {
+		root = m.selectedProject.RepoPath
+	}
+	if root == "" {
+		return nil
+	}
+
+	for _, file := range files {
+		if filepath.IsAbs(file) {
+			return fmt.Errorf("%s must be relative to the repository", file)
+		}
+		if _, err := os.Stat(filepath.Join(root, file)); err != nil {
+			return fmt.Errorf("%s not found in %s", file, root)
+		}
+	}
+	return nil
+}
+
+// parseLabels splits comma-separated input into trimmed labels, dropping
+// empty entries and repeats that differ only in case.
+func (m *Model) parseLabels(input string) []string {
+	if strings.TrimSpace(input) == "" {
+		return []string{}
+	}
+	parts := strings.Split(input, ",")
+	var labels []string
+	for _, p := range parts {
+		label := strings.TrimSpace(p)
+		if label == "" {
+			continue
+		}
+		if slices.ContainsFunc(labels, func(l string) bool { return strings.EqualFold(l, label) }) {
+			continue
+		}
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+type settingsField struct {
+	key         string
+	label       string
+	kind        string
+	description string
+}
+
+var settingsFields = []settingsField{
+	{"theme", "Theme", "theme", "Color theme for the UI"},
+	{"default_agent", "Default Agent", "agent", "Agent to spawn for new tickets (opencode, claude, gemini, codex, aider, qwen, cursor-agent)"},
+	{"confirm_quit", "Confirm Quit", "toggle", "Prompt before quitting with running agents"},
+	{"confirm_every_quit", "Confirm Every Quit", "toggle", "Prompt before quitting even when no agents are running"},
+	{"enforce_blockers", "Enforce Blockers", "toggle", "Refuse to start tickets until their blockers are done"},
+	{"auto_move_on_complete", "Auto-Move Done", "toggle", "Move to Done and stop the agent when it reports completion"},
+	{"confirm_branch_name", "Confirm Branch", "toggle", "Preview and edit the branch name before starting a ticket"},
+	{"enforce_wip_limits", "Enforce WIP Limits", "toggle", "Refuse to move tickets into columns that are full"},
+	{"confirm_spawn_prompt", "Confirm Prompt", "toggle", "Preview and edit the init prompt before spawning an agent"},
+	{"confirm_parallel_agents", "Confirm Parallel", "toggle", "Ask before spawning another agent in a project that has one running"},
+	{"enable_pr_creation", "PR Creation", "toggle", "Let P push a ticket's branch and open a pull request with gh"},
+	{"log_agent_output", "Log Agent Output", "toggle", "Save each agent's terminal output to ~/.cache/openkanban-logs"},
+	{"timestamp_log", "Timestamp Log", "toggle", "Start each line of the agent output log with when it arrived"},
+	{"desktop_notifications", "Desktop Notify", "toggle", "Show a desktop notification when an agent is waiting or fails"},
+	{"branch_prefix", "Branch Prefix", "text", "Prefix for auto-generated branch names (e.g. task/, feature/)"},
+	{"slug_max_length", "Slug Max Length", "number", "Longest title slug in generated branch names (0 uses 40)"},
+	{"poll_interval", "Status Refresh", "number", "Seconds between agent status checks"},
+	{"delete_worktree", "Delete Worktree", "toggle", "Remove git worktree when deleting tickets"},
+	{"delete_branch", "Delete Branch", "toggle", "Delete git branch when deleting tickets"},
+	{"force_cleanup", "Force Cleanup", "toggle", "Force worktree removal even with uncommitted changes"},
+	{"keep_unmerged_branches", "Keep Unmerged", "toggle", "Keep branches with unmerged commits instead of asking before deleting them"},
+	{"sidebar_visible", "Show Sidebar", "toggle", "Toggle the project sidebar visibility"},
+	{"show_archived", "Show Archived", "toggle", "Show the Archived column and count archived tickets"},
+	{"show_snoozed", "Show Snoozed", "toggle", "Show snoozed tickets on the board"},
+	{"compact_cards", "Compact Cards", "toggle", "Draw each ticket as a single line"},
+	{"filter_project", "Filter Project", "project", "Show only tickets from a specific project"},
+	{"config_health", "Config Health", "view", "Show warnings and errors from validating the config file"},
+}
+
+func (m *Model) handleSettingsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.settingsEditing {
+		return m.handleSettingsEdit(msg)
+	}
+
+	switch msg.String() {
+	case "j", "down":
+		m.settingsIndex++
+		if m.settingsIndex >= len(settingsFields) {
+			m.settingsIndex = len(settingsFields) - 1
+		}
+	case "k", "up":
+		m.settingsIndex--
+		m.settingsIndex = max(m.settingsIndex, 0)
+	case "enter", " ":
+		return m.enterSettingsEdit()
+	case "esc", "q":
+		m.mode = ModeNormal
+	}
+	return m, nil
+}
+
+func (m *Model) handleSettingsEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := settingsFields[m.settingsIndex]
+
+	if field.kind == "project" {
+		m.filterInput.SetValue(m.filterQuery)
+		m.filterInput.Focus()
+		m.mode = ModeFilter
+		return m, textinput.Blink
+	}
+
+	if field.kind == "theme" {
+		return m.handleThemeNav(msg)
+	}
+
+	switch msg.String() {
+	case "enter":
+		if field.kind == "number" {
+			if _, err := parseSettingsNumber(field.key, m.settingsInput.Value()); err != nil {
+				m.notify("Error: " + err.Error())
+				return m, nil
+			}
+		}
+		m.applySettingsValue(field.key, m.settingsInput.Value())
+		m.settingsEditing = false
+		m.settingsInput.Blur()
+		m.notify("Settings saved")
+		return m, nil
+	case "esc":
+		m.settingsEditing = false
+		m.settingsInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.settingsInput, cmd = m.settingsInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) handleThemeNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	themes := config.ThemeNames()
+	if len(themes) == 0 {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "j", "down":
+		m.themeListIndex++
+		if m.themeListIndex >= len(themes) {
+			m.themeListIndex = 0
+		}
+		m.applySettingsValue("theme", themes[m.themeListIndex])
+	case "k", "up":
+		m.themeListIndex--
+		if m.themeListIndex < 0 {
+			m.themeListIndex = len(themes) - 1
+		}
+		m.applySettingsValue("theme", themes[m.themeListIndex])
+	case "enter":
+		m.settingsEditing = false
+		m.notify("Theme: " + themes[m.themeListIndex])
+	case "esc":
+		m.settingsEditing = false
+	}
+
+	return m, nil
+}
+
+func (m *Model) handleSettingsMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return m, nil
+	}
+
+	formTop := (m.height - 10) / 2
+	relY := msg.Y - formTop - 3
+
+	if relY >= 0 && relY < len(settingsFields) {
+		m.settingsIndex = relY
+		return m.enterSettingsEdit()
+	}
+
+	return m, nil
+}
+
+func (m *Model) handleConfirmMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return m, nil
+	}
+
+	formCenterY := m.height / 2
+	formCenterX := m.width / 2
+
+	yesX := formCenterX - 10
+	noX := formCenterX + 5
+
+	if msg.Y == formCenterY+2 {
+		if msg.X >= yesX && msg.X <= yesX+5 {
+			return m, m.acceptConfirm()
+		}
+		if msg.X >= noX && msg.X <= noX+4 {
+			m.closeConfirm()
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Model) enterSettingsEdit() (tea.Model, tea.Cmd) {
+	field := settingsFields[m.settingsIndex]
+	if field.kind == "view" {
+		m.configHealthScroll = 0
+		m.mode = ModeConfigHealth
+		return m, nil
+	}
+	if m.denyReadOnly() {
+		return m, nil
+	}
+
+	switch field.kind {
+	case "project":
+		m.filterInput.SetValue(m.filterQuery)
+		m.filterInput.Focus()
+		m.mode = ModeFilter
+		return m, textinput.Blink
+
+	case "toggle":
+		m.applySettingsValue(field.key, "")
+		status := m.getSettingsValue(field.key)
+		m.notify(field.label + ": " + status)
+		return m, nil
+
+	case "theme":
+		themes := config.ThemeNames()
+		current := m.config.UI.Theme
+		m.themeListIndex = 0
+		for i, t := range themes {
+			if t == current {
+				m.themeListIndex = i
+				break
+			}
+		}
+		m.settingsEditing = true
+		return m, nil
+
+	case "agent":
+		agents := m.getAgentNames()
+		current := m.config.Defaults.DefaultAgent
+		currentIndex := 0
+		for i, a := range agents {
+			if a == current {
+				currentIndex = i
+				break
+			}
+		}
+		nextIndex := (currentIndex + 1) % len(agents)
+		nextAgent := agents[nextIndex]
+		m.applySettingsValue(field.key, nextAgent)
+		m.notify("Default agent: " + nextAgent)
+		return m, nil
+
+	case "text":
+		m.settingsEditing = true
+		m.settingsInput.SetValue(m.getSettingsValue(field.key))
+		m.settingsInput.Focus()
+		return m, textinput.Blink
+
+	default:
+		m.settingsEditing = true
+		m.settingsInput.SetValue(m.getSettingsValue(field.key))
+		m.settingsInput.Focus()
+		return m, textinput.Blink
+	}
+}
+
+func (m *Model) getSettingsValue(key string) string {
+	switch key {
+	case "config_health":
+		return configHealthSummary(m.configHealth)
+	case "theme":
+		return m.config.UI.Theme
+	case "default_agent":
+		return m.config.Defaults.DefaultAgent
+	case "confirm_quit":
+		if m.config.Behavior.ConfirmQuitWithAgents {
+			return "On"
+		}
+		return "Off"
+	case "confirm_every_quit":
+		if m.config.Behavior.ConfirmQuit {
+			return "On"
+		}
+		return "Off"
+	case "enforce_blockers":
+		if m.config.Behavior.EnforceBlockers {
+			return "On"
+		}
+		return "Off"
+	case "auto_move_on_complete":
+		if m.config.Behavior.AutoMoveOnComplete {
+			return "On"
+		}
+		return "Off"
+	case "confirm_branch_name":
+		if m.config.Behavior.ConfirmBranchName {
+			return "On"
+		}
+		return "Off"
+	case "enforce_wip_limits":
+		if m.config.Behavior.EnforceWIPLimits {
+			return "On"
+		}
+		return "Off"
+	case "confirm_spawn_prompt":
+		if m.config.Behavior.ConfirmSpawnPrompt {
+			return "On"
+		}
+		return "Off"
+	case "confirm_parallel_agents":
+		if m.config.Behavior.ConfirmParallelAgents {
+			return "On"
+		}
+		return "Off"
+	case "enable_pr_creation":
+		if m.config.Behavior.EnablePRCreation {
+			return "On"
+		}
+		return "Off"
+	case "log_agent_output":
+		if m.config.Behavior.LogAgentOutput {
+			return "On"
+		}
+		return "Off"
+	case "timestamp_log":
+		if m.config.Logging.TimestampLog {
+			return "On"
+		}
+		return "Off"
+	case "desktop_notifications":
+		if m.config.Behavior.DesktopNotifications {
+			return "On"
+		}
+		return "Off"
+	case "branch_prefix":
+		return m.config.Defaults.BranchPrefix
+	case "slug_max_length":
+		return strconv.Itoa(m.config.Defaults.SlugMaxLength)
+	case "poll_interval":
+		return strconv.Itoa(int(m.agentMgr.StatusPollInterval() / time.Second))
+	case "delete_worktree":
+		if m.config.Cleanup.DeleteWorktree {
+			return "On"
+		}
+		return "Off"
+	case "delete_branch":
+		if m.config.Cleanup.DeleteBranch {
+			return "On"
+		}
+		return "Off"
+	case "force_cleanup":
+		if m.config.Cleanup.ForceWorktreeRemoval {
+			return "On"
+		}
+		return "Off"
+	case "keep_unmerged_branches":
+		if m.config.Cleanup.KeepUnmergedBranches {
+			return "On"
+		}
+		return "Off"
+	case "filter_project":
+		count := len(m.filterProjectIDs)
+		if count == 0 {
+			return "All Projects"
+		}
+		return fmt.Sprintf("%d selected", count)
+	case "sidebar_visible":
+		if m.sidebarVisible {
+			return "On"
+		}
+		return "Off"
+	case "show_archived":
+		if m.config.UI.ShowArchived {
+			return "On"
+		}
+		return "Off"
+	case "show_snoozed":
+		if m.config.UI.ShowSnoozed {
+			return "On"
+		}
+		return "Off"
+	case "compact_cards":
+		if m.config.UI.CompactCards {
+			return "On"
+		}
+		return "Off"
+	}
+	return ""
+}
+
+func (m *Model) applySettingsValue(key, value string) {
+	switch key {
+	case "theme":
+		m.config.UI.Theme = value
+		m.theme = m.config.GetTheme()
+		m.colors = newUIColors(m.theme)
+		m.config.Save("")
+	case "default_agent":
+		m.config.Defaults.DefaultAgent = value
+		m.config.Save("")
+	case "confirm_quit":
+		m.config.Behavior.ConfirmQuitWithAgents = !m.config.Behavior.ConfirmQuitWithAgents
+		m.config.Save("")
+	case "confirm_every_quit":
+		m.config.Behavior.ConfirmQuit = !m.config.Behavior.ConfirmQuit
+		m.config.Save("")
+	case "enforce_blockers":
+		m.config.Behavior.EnforceBlockers = !m.config.Behavior.EnforceBlockers
+		m.config.Save("")
+	case "auto_move_on_complete":
+		m.config.Behavior.AutoMoveOnComplete = !m.config.Behavior.AutoMoveOnComplete
+		m.config.Save("")
+	case "confirm_branch_name":
+		m.config.Behavior.ConfirmBranchName = !m.config.Behavior.ConfirmBranchName
+		m.config.Save("")
+	case "enforce_wip_limits":
+		m.config.Behavior.EnforceWIPLimits = !m.config.Behavior.EnforceWIPLimits
+		m.config.Save("")
+	case "confirm_spawn_prompt":
+		m.config.Behavior.ConfirmSpawnPrompt = !m.config.Behavior.ConfirmSpawnPrompt
+		m.config.Save("")
+	case "confirm_parallel_agents":
+		m.config.Behavior.ConfirmParallelAgents = !m.config.Behavior.ConfirmParallelAgents
+		m.config.Save("")
+	case "enable_pr_creation":
+		m.config.Behavior.EnablePRCreation = !m.config.Behavior.EnablePRCreation
+		m.config.Save("")
+	case "log_agent_output":
+		m.config.Behavior.LogAgentOutput = !m.config.Behavior.LogAgentOutput
+		m.config.Save("")
+	case "timestamp_log":
+		m.config.Logging.TimestampLog = !m.config.Logging.TimestampLog
+		m.config.Save("")
+	case "desktop_notifications":
+		m.config.Behavior.DesktopNotifications = !m.config.Behavior.DesktopNotifications
+		m.config.Save("")
+		if m.config.Behavior.DesktopNotifications && !notify.Available() {
+			m.notify(notify.ErrUnavailable.Error())
+		}
+	case "branch_prefix":
+		m.config.Defaults.BranchPrefix = value
+		m.config.Save("")
+	case "slug_max_length":
+		if n, err := parseSettingsNumber(key, value); err == nil {
+			m.config.Defaults.SlugMaxLength = n
+			m.config.Save("")
+		}
+	case "poll_interval":
+		if n, err := parseSettingsNumber(key, value); err == nil {
+			m.config.Opencode.PollInterval = n
+			m.config.Save("")
+		}
+	case "delete_worktree":
+		m.config.Cleanup.DeleteWorktree = !m.config.Cleanup.DeleteWorktree
+		m.config.Save("")
+	case "delete_branch":
+		m.config.Cleanup.DeleteBranch = !m.config.Cleanup.DeleteBranch
+		m.config.Save("")
+	case "force_cleanup":
+		m.config.Cleanup.ForceWorktreeRemoval = !m.config.Cleanup.ForceWorktreeRemoval
+		m.config.Save("")
+	case "keep_unmerged_branches":
+		m.config.Cleanup.KeepUnmergedBranches = !m.config.Cleanup.KeepUnmergedBranches
+		m.config.Save("")
+	case "sidebar_visible":
+		m.sidebarVisible = !m.sidebarVisible
+		m.config.UI.SidebarVisible = m.sidebarVisible
+		if !m.sidebarVisible {
+			m.sidebarFocused = false
+		}
+		m.config.Save("")
+	case "show_archived":
+		m.setShowArchived(!m.config.UI.ShowArchived)
+	case "show_snoozed":
+		m.setShowSnoozed(!m.config.UI.ShowSnoozed)
+	case "compact_cards":
+		m.config.UI.CompactCards = !m.config.UI.CompactCards
+		m.config.Save("")
+		m.ensureTicketVisible()
+	}
+}
+
+// parseSettingsNumber parses the value typed for the numeric setting key,
+// rejecting values outside the range the setting accepts.
+func parseSettingsNumber(key, value string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a whole number", strings.TrimSpace(value))
+	}
+	switch key {
+	case "slug_max_length":
+		if n < 0 || n > 100 {
+			return 0, errors.New("slug max length must be between 0 and 100")
+		}
+	case "poll_interval":
+		if n < 1 || n > 60 {
+			return 0, errors.New("status refresh must be between 1 and 60 seconds")
+		}
+	}
+	return n, nil
+}
+
+// SetConfigHealth records the config file path and the result of
+// validating it at startup, shown in the Config Health settings view.
+func (m *Model) SetConfigHealth(path string, result *config.ValidationResult) {
+	m.configPath = path
+	m.configHealth = result
+}
+
+// configHealthSummary describes a validation result in a few words.
+func configHealthSummary(r *config.ValidationResult) string {
+	if r == nil {
+		return "Not checked"
+	}
+	var parts []string
+	if n := len(r.Errors); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", n, pluralize(n, "error", "errors")))
+	}
+	if n := len(r.Warnings); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", n, pluralize(n, "warning", "warnings")))
+	}
+	if len(parts) == 0 {
+		return "OK"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (m *Model) handleConfigHealthMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		m.configHealthScroll++
+	case "k", "up":
+		m.configHealthScroll = max(m.configHealthScroll-1, 0)
+	case "r":
+		// Re-reads the file to pick up edits made outside OpenKanban; the
+		// running config is unchanged.
+		_, result, err := config.LoadWithValidation(m.configPath)
+		if err != nil && result == nil {
+			m.notify("Failed to read config: " + err.Error())
+			return m, nil
+		}
+		m.configHealth = result
+		m.configHealthScroll = 0
+		m.notify("Config health: " + configHealthSummary(m.configHealth))
+	case "q":
+		m.mode = ModeSettings
+	}
+	return m, nil
+}
+
+func (m *Model) handleFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.filterInput.Blur()
+		m.mode = ModeNormal
+		return m, nil
+	case "esc":
+		m.filterQuery = ""
+		m.filterInput.SetValue("")
+		m.filterInput.Blur()
+		m.mode = ModeNormal
+		m.refreshColumnTickets()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	m.filterQuery = m.filterInput.Value()
+	m.refreshColumnTickets()
+	return m, cmd
+}
+
+func (m *Model) handleFilterMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) handleCreateProjectMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		return m.createProjectFromPath()
+	case "tab":
+		m.copySettingsIndex = (m.copySettingsIndex + 1) % (len(m.globalStore.Projects()) + 1)
+		return m, nil
+	case "shift+tab":
+		count := len(m.globalStore.Projects()) + 1
+		m.copySettingsIndex = (m.copySettingsIndex + count - 1) % count
+		return m, nil
+	case "esc":
+		m.mode = ModeNormal
+		m.addProjectPath.Blur()
+		return m, nil
+	case "ctrl+c":
+		m.mode = ModeNormal
+		m.addProjectPath.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.addProjectPath, cmd = m.addProjectPath.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) handleOnboardingMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.onboardingStep {
+	case onboardingProject:
+		switch msg.String() {
+		case "esc":
+			return m, tea.Quit
+		case "enter":
+			m.createProjectFromPath()
+			if m.globalStore.HasProjects() {
+				m.notification = ""
+				m.onboardingStep = onboardingAgent
+				m.onboardingAgentIndex = m.getAgentIndex(config.DetectAvailableAgent(m.config.Agents))
+			}
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.addProjectPath, cmd = m.addProjectPath.Update(msg)
+		return m, cmd
+
+	case onboardingAgent:
+		agents := m.getAgentNames()
+		switch msg.String() {
+		case "j", "down":
+			m.onboardingAgentIndex = (m.onboardingAgentIndex + 1) % len(agents)
+		case "k", "up":
+			m.onboardingAgentIndex = (m.onboardingAgentIndex - 1 + len(agents)) % len(agents)
+		case "enter":
+			m.config.Defaults.DefaultAgent = agents[m.onboardingAgentIndex]
+			if err := m.config.Save(""); err != nil {
+				m.notify("Failed to save config: " + err.Error())
+			}
+			m.finishOnboarding()
+		case "esc":
+			m.finishOnboarding()
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) finishOnboarding() {
+	m.mode = ModeNormal
+	m.refreshColumnTickets()
+	m.notify("All set! Press n to create your first ticket")
+}
+
+// detectInstalledAgents looks up each configured agent's command on PATH
+// for the onboarding agent picker, which is only shown on first run.
+func (m *Model) detectInstalledAgents() tea.Cmd {
+	if m.mode != ModeOnboarding {
+		return nil
+	}
+	commands := make(map[string]string, len(m.config.Agents))
+	for name, cfg := range m.config.Agents {
+		commands[name] = cfg.Command
+	}
+	return func() tea.Msg {
+		installed := make(agentsDetectedMsg, len(commands))
+		for name, command := range commands {
+			_, err := exec.LookPath(command)
+			installed[name] = err == nil
+		}
+		return installed
+	}
+}
+
+func (m *Model) clearFilter() {
+	m.filterQuery = ""
+	m.filterProjectIDs = make(map[string]bool)
+	m.filterOrphaned = false
+	m.savedFilter = nil
+	m.refreshColumnTickets()
+}
+
+func (m *Model) hasActiveFilter() bool {
+	return m.filterQuery != "" || len(m.filterProjectIDs) > 0 || m.filterOrphaned || m.savedFilter != nil
+}
+
+// toggleOrphanedFilter shows only tickets whose project is no longer registered.
+func (m *Model) toggleOrphanedFilter() {
+	m.filterOrphaned = !m.filterOrphaned
+	if m.filterOrphaned {
+		m.filterProjectIDs = make(map[string]bool)
+	}
+	m.filterQuery = ""
+	m.refreshColumnTickets()
+}
+
+func (m *Model) toggleProjectFilter(projectID string) {
+	m.filterOrphaned = false
+	if m.filterProjectIDs[projectID] {
+		delete(m.filterProjectIDs, projectID)
+	} else {
+		m.filterProjectIDs[projectID] = true
+	}
+	m.filterQuery = ""
+	m.refreshColumnTickets()
+}
+
+func (m *Model) toggleAllProjects() {
+	projects := m.globalStore.Projects()
+	allSelected := len(m.filterProjectIDs) == len(projects) && len(projects) > 0
+	for _, p := range projects {
+		if !m.filterProjectIDs[p.ID] {
+			allSelected = false
+			break
+		}
+	}
+
+	m.filterOrphaned = false
+	if allSelected || len(m.filterProjectIDs) == 0 {
+		m.filterProjectIDs = make(map[string]bool)
+		for _, p := range projects {
+			m.filterProjectIDs[p.ID] = true
+		}
+		m.notify("All projects selected")
+	} else {
+		m.filterProjectIDs = make(map[string]bool)
+		m.notify("All projects deselected")
+	}
+	m.filterQuery = ""
+	m.refreshColumnTickets()
+}
+
+func (m *Model) moveColumn(delta int) {
+	m.activeColumn += delta
+	m.activeColumn = max(m.activeColumn, 0)
+	if m.activeColumn >= len(m.columns) {
+		m.activeColumn = len(m.columns) - 1
+	}
+	m.activeTicket = 0
+	m.ensureColumnVisible()
+	m.ensureTicketVisible()
+}
+
+func (m *Model) ensureColumnVisible() {
+	colWidth := m.calcColumnWidth()
+	visibleCols := m.visibleColumnCount(colWidth)
+
+	if m.activeColumn < m.scrollOffset {
+		m.scrollOffset = m.activeColumn
+	} else if m.activeColumn >= m.scrollOffset+visibleCols {
+		m.scrollOffset = m.activeColumn - visibleCols + 1
+	}
+
+	maxOffset := max(len(m.columns)-visibleCols, 0)
+	if m.scrollOffset > maxOffset {
+		m.scrollOffset = maxOffset
+	}
+}
+
+func (m *Model) headerHeight() int {
+	const (
+		content      = 1
+		borderBottom = 1
+		spacing      = 2
+	)
+	return content + borderBottom + spacing
+}
+
+func (m *Model) calcColumnWidth() int {
+	boardW := m.boardWidth()
+	if boardW == 0 || len(m.columns) == 0 {
+		return minColumnWidth
+	}
+
+	numCols := len(m.columns)
+	totalOverhead := numCols * columnOverhead
+	colWidth := (boardW - totalOverhead) / numCols
+
+	return max(colWidth, minColumnWidth)
+}
+
+func (m *Model) visibleColumnCount(colWidth int) int {
+	boardW := m.boardWidth()
+	if boardW == 0 {
+		return len(m.columns)
+	}
+	visible := boardW / (colWidth + columnOverhead)
+	visible = max(visible, 1)
+	if limit := m.config.UI.MaxVisibleColumns; limit > 0 && visible > limit {
+		visible = limit
+	}
+	if visible > len(m.columns) {
+		visible = len(m.columns)
+	}
+	return visible
+}
+
+func (m *Model) distributeWidth(numCols int) (baseWidth, remainder int) {
+	boardW := m.boardWidth()
+	if numCols == 0 || boardW == 0 {
+		return minColumnWidth, 0
+	}
+	borders := numCols * 2
+	margins := numCols - 1
+	available := boardW - borders - margins
+	baseWidth = available / numCols
+	remainder = available % numCols
+	if baseWidth < minColumnWidth {
+		baseWidth = minColumnWidth
+		remainder = 0
+	}
+	return baseWidth, remainder
+}
+
+func (m *Model) moveTicket(delta int) {
+	if len(m.columnTickets) <= m.activeColumn {
+		return
+	}
+	tickets := m.columnTickets[m.activeColumn]
+	m.activeTicket += delta
+	m.activeTicket = max(m.activeTicket, 0)
+	if m.activeTicket >= len(tickets) {
+		m.activeTicket = max(len(tickets)-1, 0)
+	}
+	m.ensureTicketVisible()
+}
+
+func (m *Model) visibleTicketCount() int {
+	availableHeight := m.columnContentHeight()
+	if availableHeight <= 0 {
+		return 1
+	}
+	if m.compactCards() {
+		// Leave room for the "▲ n more" and "▼ n more" lines.
+		return max(availableHeight-2, 1)
+	}
+	count := availableHeight / ticketHeight
+	return max(count, 1)
+}
+
+// compactCards reports whether tickets are drawn as single lines, either
+// because ui.compact_cards is set or because the terminal is small.
+func (m *Model) compactCards() bool {
+	if m.config.UI.CompactCards {
+		return true
+	}
+	return (m.width > 0 && m.width < compactBelowWidth) ||
+		(m.height > 0 && m.height < compactBelowHeight)
+}
+
+// cardHeight is the number of rows a ticket takes in a column.
+func (m *Model) cardHeight() int {
+	if m.compactCards() {
+		return compactTicketHeight
+	}
+	return ticketHeight
+}
+
+func (m *Model) columnContentHeight() int {
+	boardHeight := m.height - 4
+	contentHeight := boardHeight - columnHeaderHeight - 4
+	return contentHeight
+}
+
+func (m *Model) ensureTicketVisible() {
+	if m.activeColumn < 0 || m.activeColumn >= len(m.columnOffsets) {
+		return
+	}
+
+	offset := m.columnOffsets[m.activeColumn]
+	visible := m.visibleTicketCount()
+
+	if m.activeTicket < offset {
+		m.columnOffsets[m.activeColumn] = m.activeTicket
+	} else if m.activeTicket >= offset+visible {
+		m.columnOffsets[m.activeColumn] = m.activeTicket - visible + 1
+	}
+
+	m.columnOffsets[m.activeColumn] = max(m.columnOffsets[m.activeColumn], 0)
+}
+
+// scrollColumn scrolls column's ticket list by delta rows without changing
+// the selection, unless the selected ticket would leave the viewport.
+func (m *Model) scrollColumn(column, delta int) {
+	if column < 0 || column >= len(m.columnTickets) || column >= len(m.columnOffsets) {
+		return
+	}
+
+	visible := m.visibleTicketCount()
+	maxOffset := max(len(m.columnTickets[column])-visible, 0)
+	offset := min(max(m.columnOffsets[column]+delta, 0), maxOffset)
+	m.columnOffsets[column] = offset
+
+	if column != m.activeColumn {
+		return
+	}
+	if m.activeTicket < offset {
+		m.activeTicket = offset
+	} else if m.activeTicket >= offset+visible {
+		m.activeTicket = offset + visible - 1
+	}
+}
+
+func (m *Model) createNewTicket() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	m.mode = ModeCreateTicket
+	m.ticketFormField = formFieldTitle
+	m.editingTicketID = ""
+	m.branchLocked = false
+	m.linkedBranch = false
+	m.branchOptionsProject = ""
+	m.branchOptionsLoading = ""
+	m.agentLocked = false
+	m.reassignProject = false
+	m.showAddProjectForm = false
+	m.ticketTemplateIndex = -1
+	m.ticketTemplateBody = ""
+
+	if m.groupByProject && m.activeColumn < len(m.columns) && m.globalStore.GetProject(m.columns[m.activeColumn].ID) != nil {
+		m.selectedProject = m.globalStore.GetProject(m.columns[m.activeColumn].ID)
+	} else if len(m.filterProjectIDs) == 1 {
+		for id := range m.filterProjectIDs {
+			m.selectedProject = m.globalStore.GetProject(id)
+			break
+		}
+	} else if m.selectedProject == nil {
+		projects := m.globalStore.Projects()
+		if len(projects) > 0 {
+			m.selectedProject = projects[0]
+		}
+	}
+
+	m.projectListIndex = 0
+	if m.selectedProject != nil {
+		for i, p := range m.globalStore.Projects() {
+			if p.ID == m.selectedProject.ID {
+				m.projectListIndex = i
+				break
+			}
+		}
+	}
+
+	m.ticketAgent = m.getDefaultAgent()
+	m.agentListIndex = m.getAgentIndex(m.ticketAgent)
+
+	m.titleInput.Reset()
+	m.descInput.Reset()
+	m.branchInput.Reset()
+	m.baseBranchPrefill = m.currentBaseBranch(m.selectedProject)
+	m.baseBranchInput.SetValue(m.baseBranchPrefill)
+	m.baseBranchProjectID = ""
+	if m.selectedProject != nil {
+		m.baseBranchProjectID = m.selectedProject.ID
+	}
+	m.labelsInput.Reset()
+	m.dueInput.Reset()
+	m.agentArgsInput.Reset()
+	m.contextFilesInput.Reset()
+	m.ticketPriority = 3
+	m.ticketUseWorktree = true
+
+	m.initBlockerCandidates("")
+	m.selectedBlockers = make(map[board.TicketID]bool)
+	m.blockerListIndex = 0
+	m.blockerFilterInput.Reset()
+	m.formScrollOffset = 0
+
+	m.blurAllFormFields()
+	m.titleInput.Focus()
+	return m, tea.Batch(m.titleInput.Cursor.BlinkCmd(), m.loadFormBranches())
+}
+
+func (m *Model) editTicket() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		m.notify("No ticket selected")
+		return m, nil
+	}
+
+	m.mode = ModeEditTicket
+	m.ticketFormField = formFieldTitle
+	m.editingTicketID = ticket.ID
+	m.branchLocked = ticket.WorktreePath != ""
+	m.linkedBranch = false
+	m.agentLocked = ticket.AgentSpawnedAt != nil
+	m.reassignProject = m.globalStore.IsOrphaned(ticket)
+	m.worktreePathLocked = m.reassignProject
+	if pane, ok := m.panes[ticket.ID]; ok && pane.Running() {
+		m.worktreePathLocked = true
+	}
+	m.worktreePathInput.SetValue(ticket.WorktreePath)
+	m.selectedProject = m.globalStore.GetProjectForTicket(ticket)
+	m.projectListIndex = 0
+	m.titleInput.SetValue(ticket.Title)
+	m.descInput.SetValue(ticket.Description)
+	if ticket.BranchName != "" {
+		m.branchInput.SetValue(ticket.BranchName)
+	} else if m.selectedProject != nil {
+		m.branchInput.SetValue(m.generateBranchNameFromTitle(ticket.Title, ticket.Number, m.selectedProject))
+	}
+	m.baseBranchInput.SetValue(ticket.BaseBranch)
+	m.labelsInput.SetValue(strings.Join(ticket.Labels, ", "))
+	m.ticketPriority = ticket.Priority
+	if m.ticketPriority < 1 || m.ticketPriority > 5 {
+		m.ticketPriority = 3
+	}
+	m.dueInput.Reset()
+	if ticket.DueAt != nil {
+		m.dueInput.SetValue(ticket.DueAt.Format(board.DueDateLayout))
+	}
+	m.ticketUseWorktree = ticket.UseWorktree
+	if ticket.AgentType != "" {
+		m.ticketAgent = ticket.AgentType
+	} else {
+		m.ticketAgent = m.getDefaultAgent()
+	}
+	m.agentListIndex = m.getAgentIndex(m.ticketAgent)
+	m.agentArgsInput.SetValue(agent.JoinArgs(ticket.AgentArgs))
+	m.contextFilesInput.SetValue(strings.Join(ticket.ContextFiles, ", "))
+
+	m.initBlockerCandidates(ticket.ID)
+	m.selectedBlockers = make(map[board.TicketID]bool)
+	for _, blockerID := range ticket.BlockedBy {
+		m.selectedBlockers[blockerID] = true
+	}
+	m.blockerListIndex = 0
+	m.blockerFilterInput.Reset()
+	m.formScrollOffset = 0
+
+	m.branchOptionsProject = ""
+	m.branchOptionsLoading = ""
+	m.blurAllFormFields()
+	m.titleInput.Focus()
+	return m, tea.Batch(m.titleInput.Cursor.BlinkCmd(), m.loadFormBranches())
+}
+
+func (m *Model) attachToAgent() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		m.notify("No ticket selected")
+		return m, nil
+	}
+	return m.attachToTicket(ticket)
+}
+
+// attachToTicket opens the agent view for ticket's running agent.
+func (m *Model) attachToTicket(ticket *board.Ticket) (tea.Model, tea.Cmd) {
+	pane, ok := m.panes[ticket.ID]
+	if !ok || !pane.Running() {
+		m.notify("No agent running — press 's' to spawn")
+		return m, nil
+	}
+
+	m.mode = ModeAgentView
+	m.focusedPane = ticket.ID
+	m.focusedEditor = false
+	pane.Resume()
+	pane.SetSize(m.agentPaneSize())
+	m.diffContent = ""
+	m.diffErr = nil
+	return m, m.startDiffRefresh()
+}
+
+// dashboardEntry is a running agent listed in the agent dashboard.
+type dashboardEntry struct {
+	ticket  *board.Ticket
+	project *project.Project
+	pane    *terminal.Pane
+}
+
+// dashboardStatusRank orders dashboard rows: agents waiting on input first,
+// then failed and finished ones, with busy agents last.
+func dashboardStatusRank(status board.AgentStatus) int {
+	switch status {
+	case board.AgentWaiting:
+		return 0
+	case board.AgentError:
+		return 1
+	case board.AgentCompleted:
+		return 2
+	case board.AgentIdle:
+		return 3
+	case board.AgentWorking:
+		return 4
+	}
+	return 5
+}
+
+// dashboardEntries lists every ticket's running agent across all projects,
+// ignoring board filters, ordered by dashboardStatusRank and then by how
+// long the agent has been running.
+func (m *Model) dashboardEntries() []dashboardEntry {
+	var entries []dashboardEntry
+	for id, pane := range m.panes {
+		if !pane.Running() {
+			continue
+		}
+		ticket, _ := m.globalStore.Get(id)
+		if ticket == nil {
+			continue
+		}
+		entries = append(entries, dashboardEntry{
+			ticket:  ticket,
+			project: m.globalStore.GetProjectForTicket(ticket),
+			pane:    pane,
+		})
+	}
+	slices.SortFunc(entries, func(a, b dashboardEntry) int {
+		if c := dashboardStatusRank(a.ticket.AgentStatus) - dashboardStatusRank(b.ticket.AgentStatus); c != 0 {
+			return c
+		}
+		if c := a.pane.StartedAt().Compare(b.pane.StartedAt()); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.ticket.ID), string(b.ticket.ID))
+	})
+	return entries
+}
+
+// dashboardIndex returns the position of dashboardTicket in entries, or 0
+// when its agent is no longer listed.
+func (m *Model) dashboardIndex(entries []dashboardEntry) int {
+	for i, e := range entries {
+		if e.ticket.ID == m.dashboardTicket {
+			return i
+		}
+	}
+	return 0
+}
+
+func (m *Model) openDashboard() (tea.Model, tea.Cmd) {
+	entries := m.dashboardEntries()
+	if len(entries) == 0 {
+		m.notify("No agents running")
+		return m, nil
+	}
+	m.sidebarFocused = false
+	m.mode = ModeDashboard
+	m.dashboardTicket = entries[0].ticket.ID
+	return m, nil
+}
+
+func (m *Model) handleDashboardMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := m.dashboardEntries()
+	idx := m.dashboardIndex(entries)
+	switch msg.String() {
+	case "j", "down":
+		if idx < len(entries)-1 {
+			m.dashboardTicket = entries[idx+1].ticket.ID
+		}
+	case "k", "up":
+		if idx > 0 {
+			m.dashboardTicket = entries[idx-1].ticket.ID
+		}
+	case "g":
+		if len(entries) > 0 {
+			m.dashboardTicket = entries[0].ticket.ID
+		}
+	case "G":
+		if len(entries) > 0 {
+			m.dashboardTicket = entries[len(entries)-1].ticket.ID
+		}
+	case "enter":
+		if len(entries) == 0 {
+			return m, nil
+		}
+		ticket := entries[idx].ticket
+		m.selectTicketByID(ticket.ID)
+		return m.attachToTicket(ticket)
+	case "q":
+		m.mode = ModeNormal
+	default:
+		if m.keymap[msg.String()] == "dashboard" {
+			m.mode = ModeNormal
+		}
+	}
+	return m, nil
+}
+
+// focusedTerminal returns the pane the agent view shows: focusedPane's
+// editor or agent.
+func (m *Model) focusedTerminal() (*terminal.Pane, bool) {
+	if m.focusedEditor {
+		pane, ok := m.editorPanes[m.focusedPane]
+		return pane, ok
+	}
+	if quick, ok := m.quickAgents[m.focusedPane]; ok {
+		return quick.pane, true
+	}
+	pane, ok := m.panes[m.focusedPane]
+	return pane, ok
+}
+
+// copyWorktreePath copies the selected ticket's worktree path to the system
+// clipboard, showing the path instead when no clipboard is available.
+func (m *Model) copyWorktreePath() {
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return
+	}
+	if ticket.WorktreePath == "" {
+		m.notify("No worktree yet")
+		return
+	}
+	if err := clipboard.WriteAll(ticket.WorktreePath); err != nil {
+		m.notify("No clipboard available; worktree is at " + ticket.WorktreePath)
+		return
+	}
+	m.notify("Copied " + ticket.WorktreePath)
+}
+
+// opencodeServerMsg reports the shared opencode server's health.
+type opencodeServerMsg struct {
+	checked bool
+	running bool
+	// owned is set when OpenKanban started the server itself.
+	owned bool
+	err   error
+}
+
+// ensureOpencodeServer starts the shared opencode server in the background
+// if it isn't answering, reporting the result.
+func (m *Model) ensureOpencodeServer() tea.Cmd {
+	agentMgr := m.agentMgr
+	return func() tea.Msg {
+		err := agentMgr.EnsureOpencodeServer()
+		server := agentMgr.OpencodeServer()
+		return opencodeServerMsg{checked: true, running: server.IsRunning(), owned: server.Owned(), err: err}
+	}
+}
+
+// checkOpencodeServerAsync probes the shared opencode server while its
+// health is worth showing: OpenKanban started it or an opencode agent runs.
+func (m *Model) checkOpencodeServerAsync() tea.Cmd {
+	if m.opencodeChecking || !m.config.Opencode.ServerEnabled {
+		return nil
+	}
+	if !m.opencodeStatus.owned && !m.opencodeAgentRunning() {
+		if m.opencodeStatus.checked {
+			m.opencodeStatus = opencodeServerMsg{}
+		}
+		return nil
+	}
+	m.opencodeChecking = true
+	server := m.agentMgr.OpencodeServer()
+	return func() tea.Msg {
+		return opencodeServerMsg{checked: true, running: server.IsRunning(), owned: server.Owned()}
+	}
+}
+
+func (m *Model) handleOpencodeServer(msg opencodeServerMsg) {
+	m.opencodeChecking = false
+	if msg.err != nil {
+		m.notify("Failed to start opencode server: " + msg.err.Error())
+	}
+	m.opencodeStatus = msg
+}
+
+// opencodeAgentRunning reports whether any ticket or quick agent running is
+// opencode.
+func (m *Model) opencodeAgentRunning() bool {
+	for id, pane := range m.panes {
+		if !pane.Running() {
+			continue
+		}
+		if t, _ := m.globalStore.Get(id); t != nil && t.AgentType == "opencode" {
+			return true
+		}
+	}
+	for _, quick := range m.quickAgents {
+		if quick.agentType == "opencode" && quick.pane.Running() {
+			return true
+		}
+	}
+	return false
+}
+
+// copyAgentOutput copies the text shown in the agent view to the system
+// clipboard. Mouse selection doesn't reach the outer terminal while the app
+// captures mouse events, so this is the way to get output out.
+func (m *Model) copyAgentOutput(pane *terminal.Pane) {
+	text := pane.GetVisibleText()
+	if text == "" {
+		m.notify("Nothing to copy")
+		return
+	}
+	if err := clipboard.WriteAll(text); err != nil {
+		m.notify("No clipboard available")
+		return
+	}
+	lines := strings.Count(text, "\n") + 1
+	if lines == 1 {
+		m.notify("Copied 1 line")
+	} else {
+		m.notify(fmt.Sprintf("Copied %d lines", lines))
+	}
+}
+
+// editorPanePrefix starts the IDs of editor panes, telling their messages
+// apart from agent panes, which use the bare ticket ID.
+const editorPanePrefix = "editor:"
+
+// openEditor runs behavior.editor_command, or $EDITOR, on the selected
+// ticket's worktree in a pane shown in the agent view, or returns to the
+// ticket's editor if one is already open.
+func (m *Model) openEditor() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+	if ticket.WorktreePath == "" {
+		m.notify("No worktree yet")
+		return m, nil
+	}
+	if pane, ok := m.editorPanes[ticket.ID]; ok && pane.Running() {
+		m.focusEditor(ticket.ID, pane)
+		return m, nil
+	}
+
+	command := m.config.Behavior.EditorCommand
+	if command == "" {
+		command = os.Getenv("EDITOR")
+	}
+	args, err := agent.SplitArgs(command)
+	if err != nil {
+		m.notify("Error: editor command: " + err.Error())
+		return m, nil
+	}
+	if len(args) == 0 {
+		m.notify("Set $EDITOR or behavior.editor_command to open worktrees")
+		return m, nil
+	}
+
+	pane := terminal.New(editorPanePrefix+string(ticket.ID), m.width, m.height, m.config.UI.ScrollbackLines)
+	pane.SetWorkdir(ticket.WorktreePath)
+	m.editorPanes[ticket.ID] = pane
+	m.focusEditor(ticket.ID, pane)
+	return m, pane.Start(args[0], append(args[1:], ticket.WorktreePath)...)
+}
+
+func (m *Model) focusEditor(ticketID board.TicketID, pane *terminal.Pane) {
+	m.mode = ModeAgentView
+	m.focusedPane = ticketID
+	m.focusedEditor = true
+	pane.SetSize(m.agentPaneSize())
+	m.diffContent = ""
+	m.diffErr = nil
+}
+
+// handleEditorExit forgets an editor pane whose process ended, returning to
+// the board if it was shown. It reports whether msg was from an editor.
+func (m *Model) handleEditorExit(msg terminal.ExitMsg) bool {
+	id, ok := strings.CutPrefix(msg.PaneID, editorPanePrefix)
+	if !ok {
+		return false
+	}
+	ticketID := board.TicketID(id)
+	delete(m.editorPanes, ticketID)
+	if m.focusedEditor && m.focusedPane == ticketID {
+		m.mode = ModeNormal
+		m.focusedPane = ""
+		m.focusedEditor = false
+	}
+	if msg.Err != nil {
+		m.notify("Editor failed: " + msg.Err.Error())
+	}
+	return true
+}
+
+// quickPanePrefix starts the IDs of quick-question agents; the rest of the ID
+// is the project they run in.
+const quickPanePrefix = "quick:"
+
+// quickAgent is a transient agent for asking a quick question in a project's
+// main repo. It has no ticket, worktree or branch, is never persisted and is
+// discarded when it exits.
+type quickAgent struct {
+	pane      *terminal.Pane
+	project   *project.Project
+	agentType string
+	port      int
+}
+
+// openQuickAgent starts the default agent in the current project's main
+// repo without a ticket, or returns to the project's quick agent if one is
+// already running.
+func (m *Model) openQuickAgent() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	proj := m.contextProject()
+	if proj == nil {
+		m.notify("Select a ticket or project first")
+		return m, nil
+	}
+	id := board.TicketID(quickPanePrefix + proj.ID)
+	if quick, ok := m.quickAgents[id]; ok && quick.pane.Running() {
+		m.focusQuickAgent(id)
+		return m, nil
+	}
+
+	agentType := m.config.Defaults.DefaultAgent
+	agentCfg, ok := m.config.Agents[agentType]
+	if !ok {
+		m.notify("Agent '" + agentType + "' not configured")
+		return m, nil
+	}
+
+	quick := &quickAgent{project: proj, agentType: agentType}
+	configArgs := agentCfg.Args
+	if agentType == "opencode" {
+		port, err := m.allocateAgentPort()
+		if err != nil {
+			m.notify("Failed to start agent: " + err.Error())
+			return m, nil
+		}
+		quick.port = port
+		if len(configArgs) == 0 {
+			configArgs = config.DefaultAgentArgs("opencode")
+		}
+	}
+	args := agent.ExpandArgs(configArgs, agent.ArgValues{
+		Worktree: proj.RepoPath,
+		Title:    "Quick question",
+		Port:     quick.port,
+	})
+	if agentType == "opencode" {
+		args = agent.OpencodeArgs(args, proj.RepoPath, quick.port)
+	}
+
+	quick.pane = terminal.New(string(id), m.width, m.height, m.config.GetScrollbackLines(agentType))
+	quick.pane.SetWorkdir(proj.RepoPath)
+	quick.pane.SetEnv(agentCfg.Env)
+	m.quickAgents[id] = quick
+	m.focusQuickAgent(id)
+	start := quick.pane.Start(agentCfg.Command, args...)
+	if agentType == "opencode" {
+		return m, tea.Sequence(m.ensureOpencodeServer(), start)
+	}
+	return m, start
+}
+
+func (m *Model) focusQuickAgent(id board.TicketID) {
+	m.mode = ModeAgentView
+	m.focusedPane = id
+	m.focusedEditor = false
+	m.quickAgents[id].pane.SetSize(m.agentPaneSize())
+	m.diffContent = ""
+	m.diffErr = nil
+}
+
+// handleQuickAgentExit discards a quick agent whose process ended, returning
+// to the board if it was shown. It reports whether msg was from a quick
+// agent.
+func (m *Model) handleQuickAgentExit(msg terminal.ExitMsg) bool {
+	id := board.TicketID(msg.PaneID)
+	if _, ok := m.quickAgents[id]; !ok {
+		return false
+	}
+	delete(m.quickAgents, id)
+	if m.focusedPane == id {
+		m.mode = ModeNormal
+		m.focusedPane = ""
+	}
+	if msg.Err != nil {
+		m.notify("Agent failed: " + msg.Err.Error())
+	}
+	return true
+}
+
+// agentPaneSize returns the dimensions available to the focused agent pane,
+// leaving room for the diff panel when the split is enabled.
+func (m *Model) agentPaneSize() (width, height int) {
+	width = m.width
+	if m.diffSplit {
+		width = m.width / 2
+	}
+	return width, m.height - 2
+}
+
+func (m *Model) toggleDiffSplit() tea.Cmd {
+	m.diffSplit = !m.diffSplit
+	if pane, ok := m.focusedTerminal(); ok {
+		pane.SetSize(m.agentPaneSize())
+	}
+	if !m.diffSplit {
+		return nil
+	}
+	m.diffContent = ""
+	m.diffErr = nil
+	return m.startDiffRefresh()
+}
+
+// startDiffRefresh kicks off the diff polling loop if the split is enabled
+// and no loop is already running.
+func (m *Model) startDiffRefresh() tea.Cmd {
+	if !m.diffSplit || m.diffTicking {
+		return nil
+	}
+	m.diffTicking = true
+	return m.refreshDiffAsync()
+}
+
+func (m *Model) refreshDiffAsync() tea.Cmd {
+	ticketID := m.focusedPane
+	var worktreePath string
+	if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
+		worktreePath = ticket.WorktreePath
+		if worktreePath == "" {
+			if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+				worktreePath = proj.RepoPath
+			}
+		}
+	} else if quick, ok := m.quickAgents[ticketID]; ok {
+		worktreePath = quick.project.RepoPath
+	}
+
+	return func() tea.Msg {
+		if worktreePath == "" {
+			return diffResultMsg{ticketID: ticketID, err: errors.New("no worktree for ticket")}
+		}
+		content, err := git.Diff(worktreePath)
+		return diffResultMsg{ticketID: ticketID, content: content, err: err}
+	}
+}
+
+func (m *Model) handleDoubleClick() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+
+	pane, ok := m.panes[ticket.ID]
+	if ok && pane.Running() {
+		return m.attachToAgent()
+	}
+
+	return m.spawnAgent()
+}
+
+// confirmDeleteTicket checks the selected or marked tickets' worktrees and
+// branches in the background; the confirmation opens when the check ends.
+func (m *Model) confirmDeleteTicket() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	if marked := m.markedTickets(); len(marked) > 0 {
+		return m, m.checkDeleteMarked(marked)
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+
+	var mgr *git.WorktreeManager
+	if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+		mgr = m.worktreeMgrs[proj.ID]
+	}
+	path := ""
+	if ticket.WorktreePath != "" && m.config.Cleanup.DeleteWorktree {
+		path = ticket.WorktreePath
+	}
+	branch := m.unmergedBranchCheck(ticket)
+	ticketID := ticket.ID
+
+	m.startGitOp("Checking " + ticket.Title)
+	return m, func() tea.Msg {
+		msg := deleteCheckMsg{ticketID: ticketID, unmergedBase: branch.unmergedBase()}
+		if path != "" && mgr != nil {
+			msg.dirty, _ = mgr.HasUncommittedChanges(path)
+		}
+		return msg
+	}
+}
+
+// confirmDelete asks before deleting the ticket checked by
+// confirmDeleteTicket, and again when its branch has unmerged commits.
+func (m *Model) confirmDelete(msg deleteCheckMsg) {
+	m.endGitOp()
+	ticket, _ := m.globalStore.Get(msg.ticketID)
+	if ticket == nil || m.mode != ModeNormal || m.showConfirm {
+		return
+	}
+
+	cleanup := func() tea.Cmd {
+		return m.performTicketCleanup(ticket)
+	}
+	if base := msg.unmergedBase; base != "" {
+		cleanup = func() tea.Cmd {
+			m.showConfirm = true
+			m.confirmMsg = "Branch " + ticket.BranchName + " has commits not merged into " + base + ". Delete the ticket and its branch anyway?"
+			m.confirmFn = func() tea.Cmd {
+				return m.performTicketCleanup(ticket)
+			}
+			return nil
+		}
+	}
+
+	if msg.dirty && !m.config.Cleanup.ForceWorktreeRemoval {
+		m.showConfirm = true
+		m.confirmMsg = "Worktree has uncommitted changes. Force delete?"
+		m.confirmFn = cleanup
+	} else {
+		m.showConfirm = true
+		m.confirmMsg = "Delete ticket: " + ticket.Title + "?"
+		m.confirmFn = cleanup
+	}
+}
+
+// checkDeleteMarked counts the marked tickets whose worktree has
+// uncommitted changes or whose branch is unmerged off the UI goroutine, reporting them in a
+// deleteMarkedCheckMsg so confirmDeleteMarked can ask.
+func (m *Model) checkDeleteMarked(marked []*board.Ticket) tea.Cmd {
+	type worktreeCheck struct {
+		path string
+		mgr  *git.WorktreeManager
+	}
+	var checks []worktreeCheck
+	var branches []*branchCheck
+	ids := make([]board.TicketID, 0, len(marked))
+	for _, t := range marked {
+		ids = append(ids, t.ID)
+		if branch := m.unmergedBranchCheck(t); branch != nil {
+			branches = append(branches, branch)
+		}
+		if t.WorktreePath == "" || !m.config.Cleanup.DeleteWorktree || m.config.Cleanup.ForceWorktreeRemoval {
+			continue
+		}
+		if proj := m.globalStore.GetProjectForTicket(t); proj != nil && m.worktreeMgrs[proj.ID] != nil {
+			checks = append(checks, worktreeCheck{path: t.WorktreePath, mgr: m.worktreeMgrs[proj.ID]})
+		}
+	}
+
+	m.startGitOp("Checking worktrees")
+	return func() tea.Msg {
+		dirty := 0
+		for _, c := range checks {
+			if has, err := c.mgr.HasUncommittedChanges(c.path); err == nil && has {
+				dirty++
+			}
+		}
+		unmerged := 0
+		for _, b := range branches {
+			if b.unmergedBase() != "" {
+				unmerged++
+			}
+		}
+		return deleteMarkedCheckMsg{ticketIDs: ids, dirty: dirty, unmerged: unmerged}
+	}
+}
+
+// confirmDeleteMarked asks once before deleting the marked tickets checked
+// by checkDeleteMarked, counting those whose worktree or branch would lose
+// work. Tickets deleted meanwhile are left out.
+func (m *Model) confirmDeleteMarked(msg deleteMarkedCheckMsg) {
+	m.endGitOp()
+	var marked []*board.Ticket
+	for _, id := range msg.ticketIDs {
+		if t, _ := m.globalStore.Get(id); t != nil {
+			marked = append(marked, t)
+		}
+	}
+	if len(marked) == 0 || m.mode != ModeNormal || m.showConfirm {
+		return
+	}
+
+	dirty, unmerged := msg.dirty, msg.unmerged
+
+	m.showConfirm = true
+	m.confirmMsg = fmt.Sprintf("Delete %d selected ticket(s)?", len(marked))
+	if dirty > 0 {
+		m.confirmMsg += fmt.Sprintf(" %d have uncommitted changes.", dirty)
+	}
+	if unmerged > 0 {
+		m.confirmMsg += fmt.Sprintf(" %d have unmerged branches.", unmerged)
+	}
+	m.confirmFn = func() tea.Cmd {
+		var cmds []tea.Cmd
+		for _, t := range marked {
+			cmds = append(cmds, m.performTicketCleanup(t))
+		}
+		m.clearTicketMarks()
+		m.notify(fmt.Sprintf("Deleted %d ticket(s)", len(marked)))
+		return tea.Batch(cmds...)
+	}
+}
+
+// branchCheck is a branch that deleting its ticket would delete, to be
+// checked for unmerged commits off the UI goroutine.
+type branchCheck struct {
+	mgr    *git.WorktreeManager
+	branch string
+	base   string
+}
+
+// unmergedBranchCheck returns the check for ticket's branch, or nil when
+// deleting ticket leaves its branch alone. With
+// cleanup.keep_unmerged_branches unmerged branches are kept, so they need
+// no check.
+func (m *Model) unmergedBranchCheck(ticket *board.Ticket) *branchCheck {
+	if !m.config.Cleanup.DeleteBranch || m.config.Cleanup.KeepUnmergedBranches {
+		return nil
+	}
+	if ticket.BranchName == "" || ticket.WorktreeExternal {
+		return nil
+	}
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil || m.worktreeMgrs[proj.ID] == nil {
+		return nil
+	}
+	return &branchCheck{mgr: m.worktreeMgrs[proj.ID], branch: ticket.BranchName, base: ticket.BaseBranch}
+}
+
+// unmergedBase returns the base branch when the branch has commits not
+// merged into it, so the delete needs an extra confirmation; otherwise it
+// returns "". It runs git and must not be called from Update.
+func (c *branchCheck) unmergedBase() string {
+	if c == nil || !c.mgr.BranchExists(c.branch) {
+		return ""
+	}
+	base := c.base
+	if base == "" {
+		base, _ = c.mgr.GetDefaultBranch()
+	}
+	merged, err := c.mgr.IsBranchMerged(c.branch, base)
+	if err != nil || merged {
+		return ""
+	}
+	return base
+}
+
+// performTicketCleanup deletes the ticket and removes its worktree and
+// branch in the background, per the cleanup settings.
+func (m *Model) performTicketCleanup(ticket *board.Ticket) tea.Cmd {
+	ticketTitle := ticket.Title // Capture before deletion
+
+	if pane, ok := m.panes[ticket.ID]; ok {
+		pane.Stop()
+		delete(m.panes, ticket.ID)
+	}
+	delete(m.lastSpawns, ticket.ID)
+	agent.RemoveAgentLog(string(ticket.ID))
+
+	var mgr *git.WorktreeManager
+	if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+		mgr = m.worktreeMgrs[proj.ID]
+	}
+	var worktreePath, branchName string
+	if mgr != nil && !ticket.WorktreeExternal {
+		if ticket.WorktreePath != "" && m.config.Cleanup.DeleteWorktree {
+			worktreePath = ticket.WorktreePath
+		}
+		if ticket.BranchName != "" && m.config.Cleanup.DeleteBranch {
+			branchName = ticket.BranchName
+		}
+	}
+
+	m.recordDelete(ticket, worktreePath != "", branchName != "")
+	m.globalStore.RemoveBlockerReferences(ticket.ID)
+	m.globalStore.Delete(ticket.ID)
+	m.refreshColumnTickets()
+	m.globalStore.SaveAll()
+
+	if worktreePath == "" && branchName == "" {
+		m.notify("Deleted: " + ticketTitle)
+		return nil
+	}
+
+	baseBranch := ticket.BaseBranch
+	keepUnmerged := m.config.Cleanup.KeepUnmergedBranches
+	m.startGitOp("Removing worktree")
+	return func() tea.Msg {
+		msg := ticketCleanupMsg{title: ticketTitle}
+		if worktreePath != "" {
+			msg.worktreeErr = mgr.RemoveWorktree(worktreePath)
+		}
+		if branchName != "" && keepUnmerged {
+			if baseBranch == "" {
+				baseBranch, _ = mgr.GetDefaultBranch()
+			}
+			// Keep the branch unless it is known to be merged.
+			if merged, err := mgr.IsBranchMerged(branchName, baseBranch); err != nil || !merged {
+				msg.branchKept = branchName
+				return msg
+			}
+		}
+		if branchName != "" {
+			msg.branchErr = mgr.DeleteBranch(branchName)
+		}
+		return msg
+	}
+}
+
+// confirmMergeBranch asks before merging the selected ticket's branch into
+// its base branch in the main repo.
+func (m *Model) confirmMergeBranch() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+	if ticket.WorktreePath == "" || ticket.BranchName == "" {
+		m.notify("No worktree branch to merge")
+		return m, nil
+	}
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil {
+		m.notify("Ticket has no project")
+		return m, nil
+	}
+	mgr := m.worktreeMgrs[proj.ID]
+	if mgr == nil {
+		return m, nil
+	}
+
+	base := ticket.BaseBranch
+	if base == "" {
+		var err error
+		if base, err = mgr.GetDefaultBranch(); err != nil {
+			m.notify("Failed to find base branch: " + err.Error())
+			return m, nil
+		}
+	}
+
+	branch := ticket.BranchName
+	m.showConfirm = true
+	m.confirmMsg = "Merge " + branch + " into " + base + "?"
+	m.confirmFn = func() tea.Cmd {
+		m.startGitOp("Merging " + branch)
+		return func() tea.Msg {
+			return branchMergedMsg{branch: branch, base: base, err: mgr.MergeBranch(branch, base)}
+		}
+	}
+	return m, nil
+}
+
+// confirmCreatePullRequest asks before pushing the selected in-progress
+// ticket's branch and opening a pull request for it with gh.
+func (m *Model) confirmCreatePullRequest() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	if !m.config.Behavior.EnablePRCreation {
+		m.notify("PR creation is off (enable it in settings)")
+		return m, nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+	// The branch exists once the ticket has a worktree, whatever column
+	// the ticket has moved to since.
+	if ticket.BranchName == "" || ticket.WorktreePath == "" {
+		m.notify("Start the ticket to create its branch before opening a PR")
+		return m, nil
+	}
+	if !git.GHAvailable() {
+		m.notify("gh not found on PATH")
+		return m, nil
+	}
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil || m.worktreeMgrs[proj.ID] == nil {
+		m.notify("Ticket has no project")
+		return m, nil
+	}
+
+	mgr := m.worktreeMgrs[proj.ID]
+	title, body := ticket.Title, ticket.Description
+	branch, base := ticket.BranchName, ticket.BaseBranch
+	m.showConfirm = true
+	m.confirmMsg = "Push " + branch + " and open a pull request?"
+	m.confirmFn = func() tea.Cmd {
+		m.startGitOp("Opening PR for " + branch)
+		return func() tea.Msg {
+			if err := mgr.PushBranch(branch); err != nil {
+				return pullRequestMsg{err: err}
+			}
+			url, err := mgr.CreatePullRequest(title, body, branch, base)
+			return pullRequestMsg{url: url, err: err}
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) handlePullRequest(msg pullRequestMsg) {
+	m.endGitOp()
+	if msg.err != nil {
+		m.notify("Failed to open PR: " + msg.err.Error())
+		return
+	}
+	m.notify("Opened PR: " + msg.url)
+}
+
+func (m *Model) handleBranchMerged(msg branchMergedMsg) {
+	m.endGitOp()
+	var conflictErr *git.MergeConflictError
+	switch {
+	case errors.As(msg.err, &conflictErr):
+		m.notify("Merge failed, aborted: " + conflictErr.Error())
+	case msg.err != nil:
+		m.notify("Failed to merge: " + msg.err.Error())
+	default:
+		m.notify("Merged " + msg.branch + " into " + msg.base)
+	}
+}
+
+// worktreeTicket returns the selected ticket with its worktree manager when
+// it has a worktree branch, notifying otherwise.
+func (m *Model) worktreeTicket() (*board.Ticket, *git.WorktreeManager) {
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		m.notify("No ticket selected")
+		return nil, nil
+	}
+	if ticket.WorktreePath == "" || ticket.BranchName == "" {
+		m.notify("No worktree branch for " + ticket.Title)
+		return nil, nil
+	}
+	mgr := m.worktreeMgrs[ticket.ProjectID]
+	if mgr == nil {
+		m.notify("Ticket has no project")
+		return nil, nil
+	}
+	return ticket, mgr
+}
+
+// commandRebase handles ":rebase", rebasing the selected ticket's branch onto
+// its base branch in the background. A conflicting rebase is aborted.
+func (m *Model) commandRebase() tea.Cmd {
+	if m.denyReadOnly() {
+		return nil
+	}
+	ticket, mgr := m.worktreeTicket()
+	if ticket == nil {
+		return nil
+	}
+	if pane, ok := m.panes[ticket.ID]; ok && pane.Running() {
+		m.notify("Stop the agent before rebasing")
+		return nil
+	}
+
+	path, branch, base := ticket.WorktreePath, ticket.BranchName, ticket.BaseBranch
+	m.startGitOp("Rebasing " + branch)
+	return func() tea.Msg {
+		if base == "" {
+			base, _ = mgr.GetDefaultBranch()
+		}
+		return branchRebasedMsg{branch: branch, base: base, err: mgr.RebaseBranch(path, base)}
+	}
+}
+
+// commandCommit handles ":commit <message>", committing every change in the
+// selected ticket's worktree in the background.
+func (m *Model) commandCommit(args []string) tea.Cmd {
+	if m.denyReadOnly() {
+		return nil
+	}
+	message := strings.TrimSpace(strings.Join(args, " "))
+	if message == "" {
+		m.notify("Usage: commit <message>")
+		return nil
+	}
+	ticket, mgr := m.worktreeTicket()
+	if ticket == nil {
+		return nil
+	}
+
+	path, title := ticket.WorktreePath, ticket.Title
+	m.startGitOp("Committing")
+	return func() tea.Msg {
+		return worktreeCommittedMsg{title: title, err: mgr.CommitAll(path, message)}
+	}
+}
+
+func (m *Model) handleBranchRebased(msg branchRebasedMsg) {
+	m.endGitOp()
+	var conflictErr *git.MergeConflictError
+	switch {
+	case errors.As(msg.err, &conflictErr):
+		m.notify("Rebase failed, aborted: " + conflictErr.Error())
+	case msg.err != nil:
+		m.notify("Failed to rebase: " + msg.err.Error())
+	default:
+		m.notify("Rebased " + msg.branch + " onto " + msg.base)
+	}
+}
+
+func (m *Model) handleWorktreeCommitted(msg worktreeCommittedMsg) {
+	m.endGitOp()
+	if msg.err != nil {
+		m.notify(msg.err.Error())
+		return
+	}
+	m.notify("Committed changes in " + msg.title)
+}
+
+func (m *Model) handleTicketCleanup(msg ticketCleanupMsg) {
+	m.endGitOp()
+	switch {
+	case msg.worktreeErr != nil:
+		m.notify("Failed to remove worktree: " + msg.worktreeErr.Error())
+	case msg.branchErr != nil:
+		m.notify("Failed to delete branch: " + msg.branchErr.Error())
+	case msg.branchKept != "":
+		m.notify("Deleted: " + msg.title + " (kept unmerged branch " + msg.branchKept + ")")
+	default:
+		m.notify("Deleted: " + msg.title)
+	}
+}
+
+// handleAgentCompleted reacts to an agent's explicit completion signal. Idle
+// is deliberately not treated as completion; only a "completed" status does.
+// With behavior.auto_move_on_complete an In Progress ticket is moved to Done
+// and its agent stopped, unless Done is at an enforced WIP limit.
+func (m *Model) handleAgentCompleted(ticket *board.Ticket) {
+	if !m.config.Behavior.AutoMoveOnComplete || ticket.Status != board.StatusInProgress {
+		return
+	}
+	if msg, full := m.wipLimitReached(ticket, board.StatusDone); full {
+		m.notify("Completed: " + ticket.Title + " (not moved: " + msg + ")")
+		return
+	}
+
+	if pane, ok := m.panes[ticket.ID]; ok {
+		pane.Stop()
+		delete(m.panes, ticket.ID)
+	}
+	if m.focusedPane == ticket.ID && !m.focusedEditor {
+		m.mode = ModeNormal
+		m.focusedPane = ""
+	}
+
+	m.recordMove(ticket)
+	m.globalStore.Move(ticket.ID, board.StatusDone)
+	m.saveTicket(ticket)
+	m.refreshColumnTickets()
+	m.notify("Completed: " + ticket.Title)
+}
+
+// desktopNotifyInterval is the least time between two desktop notifications
+// for the same ticket.
+const desktopNotifyInterval = time.Minute
+
+// desktopNotify shows a desktop notification when ticket's agent has just
+// started waiting for input or failed, if behavior.desktop_notifications is
+// on. Tickets notified within desktopNotifyInterval and the agent currently
+// attached are skipped.
+func (m *Model) desktopNotify(ticket *board.Ticket) tea.Cmd {
+	if !m.config.Behavior.DesktopNotifications {
+		return nil
+	}
+	var message string
+	switch ticket.AgentStatus {
+	case board.AgentWaiting:
+		message = "Waiting for input: " + ticket.Title
+	case board.AgentError:
+		message = "Agent error: " + ticket.Title
+	default:
+		return nil
+	}
+	if m.mode == ModeAgentView && m.focusedPane == ticket.ID {
+		return nil
+	}
+	if last, ok := m.desktopNotified[ticket.ID]; ok && time.Since(last) < desktopNotifyInterval {
+		return nil
+	}
+	m.desktopNotified[ticket.ID] = time.Now()
+
+	title := "OpenKanban"
+	if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+		title += " · " + proj.Name
+	}
+	return func() tea.Msg {
+		// Config validation already warns when no notifier is installed.
+		_ = notify.Send(title, message)
+		return nil
+	}
+}
+
+// alertSound plays behavior.alert_sound when ticket's agent has just
+// finished or started waiting for input, unless it is the agent attached.
+func (m *Model) alertSound(ticket *board.Ticket) tea.Cmd {
+	if ticket.AgentStatus != board.AgentCompleted && ticket.AgentStatus != board.AgentWaiting {
+		return nil
+	}
+	if m.mode == ModeAgentView && m.focusedPane == ticket.ID {
+		return nil
+	}
+
+	switch m.config.Behavior.AlertSound {
+	case "bell":
+		return func() tea.Msg {
+			os.Stdout.WriteString("\a")
+			return nil
+		}
+	case "command":
+		args, err := agent.SplitArgs(m.config.Behavior.AlertCommand)
+		if err != nil || len(args) == 0 {
+			return nil
+		}
+		return func() tea.Msg {
+			// Config validation reports a missing or unknown command.
+			_ = exec.Command(args[0], args[1:]...).Run()
+			return nil
+		}
+	}
+	return nil
+}
+
+// wipUsage is how many tickets a column holds against its WIP limit; a zero
+// limit means unlimited.
+type wipUsage struct {
+	count, limit int
+}
+
+// wipCount returns how many of the project's tickets are in status, whatever
+// the board filter. WIP limits are enforced and shown against this count.
+func (m *Model) wipCount(projectID string, status board.TicketStatus) int {
+	count := 0
+	for _, t := range m.globalStore.GetByStatus(status) {
+		if t.ProjectID == projectID {
+			count++
+		}
+	}
+	return count
+}
+
+// statusWIP returns the WIP usage a status column's header shows: that of
+// the visible project closest to its limit for the status, since limits
+// apply per project.
+func (m *Model) statusWIP(status board.TicketStatus) wipUsage {
+	var usage wipUsage
+	if m.filterOrphaned {
+		return usage
+	}
+	for _, p := range m.globalStore.Projects() {
+		if len(m.filterProjectIDs) > 0 && !m.filterProjectIDs[p.ID] {
+			continue
+		}
+		for _, col := range p.GetColumns() {
+			if col.Status != status || col.Limit <= 0 {
+				continue
+			}
+			count := m.wipCount(p.ID, status)
+			if usage.limit == 0 || count*usage.limit > usage.count*col.Limit {
+				usage = wipUsage{count: count, limit: col.Limit}
+			}
+		}
+	}
+	return usage
+}
+
+// wipLimitReached reports whether moving ticket into status would exceed the
+// WIP limit of that column in the ticket's project, returning a message
+// saying so. It only applies when behavior.enforce_wip_limits is on; every
+// ticket of the project in that status counts, whatever the current filter.
+func (m *Model) wipLimitReached(ticket *board.Ticket, status board.TicketStatus) (string, bool) {
+	if !m.config.Behavior.EnforceWIPLimits || ticket.Status == status {
+		return "", false
+	}
+
+	for _, col := range m.ticketColumns(ticket) {
+		if col.Status != status || col.Limit <= 0 {
+			continue
+		}
+		if count := m.wipCount(ticket.ProjectID, status); count >= col.Limit {
+			return fmt.Sprintf("%s is full (%d/%d)", col.Name, count, col.Limit), true
+		}
+	}
+	return "", false
+}
+
+// blockedFromStarting reports whether ticket must wait on unfinished blockers,
+// notifying which ones. It only applies when behavior.enforce_blockers is on.
+func (m *Model) blockedFromStarting(ticket *board.Ticket) bool {
+	if !m.config.Behavior.EnforceBlockers {
+		return false
+	}
+	open := m.globalStore.OpenBlockers(ticket.ID)
+	if len(open) == 0 {
+		return false
+	}
+	names := make([]string, len(open))
+	for i, t := range open {
+		names[i] = t.Title
+	}
+	m.notify("Blocked by: " + strings.Join(names, ", "))
+	return true
+}
+
+func (m *Model) quickMoveTicket() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	if len(m.selectedTickets) > 0 {
+		return m, m.moveMarkedTickets(true)
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+
+	nextStatus := m.nextStatus(ticket)
+	if nextStatus == ticket.Status {
+		return m, nil
+	}
+
+	if msg, full := m.wipLimitReached(ticket, nextStatus); full {
+		m.notify(msg)
+		return m, nil
+	}
+
+	if nextStatus == board.StatusInProgress && m.blockedFromStarting(ticket) {
+		return m, nil
+	}
+
+	if nextStatus == board.StatusInProgress && m.needsBranchPreview(ticket) {
+		return m, m.openBranchPreview(ticket)
+	}
+
+	if nextStatus == board.StatusInProgress && ticket.WorktreePath == "" {
+		return m, m.startTicketWork(ticket, nextStatus)
+	}
+
+	m.recordMove(ticket)
+	m.finishMove(ticket, nextStatus)
+	return m, nil
+}
+
+func (m *Model) quickMoveTicketBackward() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	if len(m.selectedTickets) > 0 {
+		return m, m.moveMarkedTickets(false)
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+
+	prevStatus := m.previousStatus(ticket)
+	if prevStatus == ticket.Status {
+		return m, nil
+	}
+
+	if msg, full := m.wipLimitReached(ticket, prevStatus); full {
+		m.notify(msg)
+		return m, nil
+	}
+
+	m.recordMove(ticket)
+	m.globalStore.Move(ticket.ID, prevStatus)
+	m.refreshColumnTickets()
+	m.selectTicketByID(ticket.ID)
+	m.saveTicket(ticket)
+	m.notify("Moved to " + string(prevStatus))
+
+	return m, nil
+}
+
+// moveMarkedTickets moves every marked ticket one column forward or
+// backward. Tickets held back by WIP limits or blockers stay put.
+func (m *Model) moveMarkedTickets(forward bool) tea.Cmd {
+	var cmds []tea.Cmd
+	moved, skipped := 0, 0
+	for _, t := range m.markedTickets() {
+		status := m.previousStatus(t)
+		if forward {
+			status = m.nextStatus(t)
+		}
+		if _, full := m.wipLimitReached(t, status); status == t.Status || full {
+			skipped++
+			continue
+		}
+		if status == board.StatusInProgress && m.blockedFromStarting(t) {
+			skipped++
+			continue
+		}
+		moved++
+		if forward && status == board.StatusInProgress && t.WorktreePath == "" {
+			cmds = append(cmds, m.startTicketWork(t, status))
+			continue
+		}
+		m.recordMove(t)
+		m.globalStore.Move(t.ID, status)
+		m.saveTicket(t)
+	}
+	m.refreshColumnTickets()
+
+	msg := fmt.Sprintf("Moved %d ticket(s)", moved)
+	if skipped > 0 {
+		msg += fmt.Sprintf(", %d skipped", skipped)
+	}
+	m.notify(msg)
+	return tea.Batch(cmds...)
+}
+
+// checkBranchRename offers to rename a retitled ticket's branch when it was
+// generated from oldTitle and is still safe to rename: an OpenKanban-created
+// worktree, no agent running and no commits beyond the base branch. The
+// commit count is checked in the background; branchRenamableMsg reports a
+// branch that can be renamed.
+func (m *Model) checkBranchRename(ticket *board.Ticket, oldTitle string) tea.Cmd {
+	if ticket.Title == oldTitle || !ticket.UseWorktree || ticket.WorktreeExternal {
+		return nil
+	}
+	if ticket.WorktreePath == "" || ticket.BranchName == "" || ticket.BaseBranch == "" {
+		return nil
+	}
+	if pane, ok := m.panes[ticket.ID]; ok && pane.Running() {
+		return nil
+	}
+
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil || m.worktreeMgrs[proj.ID] == nil {
+		return nil
+	}
+	if ticket.BranchName != m.generateBranchNameFromTitle(oldTitle, ticket.Number, proj) {
+		return nil
+	}
+	newBranch := m.generateBranchNameFromTitle(ticket.Title, ticket.Number, proj)
+	if newBranch == ticket.BranchName {
+		return nil
+	}
+
+	mgr := m.worktreeMgrs[proj.ID]
+	ticketID, branch, base := ticket.ID, ticket.BranchName, ticket.BaseBranch
+	return func() tea.Msg {
+		ahead, err := mgr.CommitsAhead(branch, base)
+		if err != nil || ahead > 0 {
+			return nil
+		}
+		return branchRenamableMsg{ticketID: ticketID, branch: branch, newBranch: newBranch}
+	}
+}
+
+// handleBranchRenamable asks before renaming the branch, unless the ticket
+// has changed or another dialog has opened since the check started.
+func (m *Model) handleBranchRenamable(msg branchRenamableMsg) {
+	ticket, _ := m.globalStore.Get(msg.ticketID)
+	if ticket == nil || ticket.BranchName != msg.branch || m.mode != ModeNormal || m.showConfirm {
+		return
+	}
+	m.showConfirm = true
+	m.confirmMsg = "Rename branch " + msg.branch + " to " + msg.newBranch + " and move its worktree?"
+	m.confirmFn = func() tea.Cmd {
+		return m.renameTicketBranch(ticket, msg.newBranch)
+	}
+}
+
+// renameTicketBranch renames the ticket's branch and moves its worktree in
+// the background.
+func (m *Model) renameTicketBranch(ticket *board.Ticket, newBranch string) tea.Cmd {
+	if pane, ok := m.panes[ticket.ID]; ok && pane.Running() {
+		m.notify("Stop the agent before renaming its branch")
+		return nil
+	}
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil || m.worktreeMgrs[proj.ID] == nil {
+		m.notify(errOrphanedTicket.Error())
+		return nil
+	}
+
+	mgr := m.worktreeMgrs[proj.ID]
+	ticketID, path, branch := ticket.ID, ticket.WorktreePath, ticket.BranchName
+	m.startGitOp("Renaming " + branch)
+	return func() tea.Msg {
+		newPath, err := mgr.RenameWorktreeBranch(path, branch, newBranch)
+		return branchRenamedMsg{ticketID: ticketID, branch: branch, newBranch: newBranch, path: newPath, err: err}
+	}
+}
+
+func (m *Model) handleBranchRenamed(msg branchRenamedMsg) {
+	m.endGitOp()
+	if msg.err != nil {
+		m.notify("Rename failed: " + msg.err.Error())
+		return
+	}
+	ticket, _ := m.globalStore.Get(msg.ticketID)
+	if ticket == nil || ticket.BranchName != msg.branch {
+		m.notify("Renamed branch to " + msg.newBranch + ", but the ticket changed meanwhile")
+		return
+	}
+	ticket.WorktreePath = msg.path
+	ticket.BranchName = msg.newBranch
+	ticket.Touch()
+	m.saveTicket(ticket)
+	m.notify("Renamed branch to " + msg.newBranch)
+}
+
+// startTicketWork prepares the ticket's branch and then moves it to status.
+// Worktrees are created in the background with a status bar indicator, and
+// the move happens once git finishes.
+func (m *Model) startTicketWork(ticket *board.Ticket, status board.TicketStatus) tea.Cmd {
+	if !ticket.UseWorktree {
+		if err := m.setupMainRepoBranch(ticket); err != nil {
+			m.notify("Branch setup failed: " + err.Error())
+			return nil
+		}
+		m.finishMove(ticket, status)
+		return nil
+	}
+
+	if m.pendingWorktrees[ticket.ID] {
+		m.notify("Worktree is still being created")
+		return nil
+	}
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil {
+		m.notify("Worktree failed: " + errOrphanedTicket.Error())
+		return nil
+	}
+	mgr := m.worktreeMgrs[proj.ID]
+	if mgr == nil {
+		m.notify("Worktree failed: worktree manager not found")
+		return nil
+	}
+
+	ticketID := ticket.ID
+	fromStatus := ticket.Status
+	branchName := m.generateBranchName(ticket, proj)
+	baseBranch := ticket.BaseBranch
+	m.pendingWorktrees[ticketID] = true
+	m.startGitOp("Creating worktree")
+
+	return func() tea.Msg {
+		if baseBranch == "" {
+			baseBranch, _ = mgr.GetDefaultBranch()
+		}
+		path, err := mgr.CreateWorktree(branchName, baseBranch)
+		return worktreeCreatedMsg{
+			ticketID:   ticketID,
+			projectID:  proj.ID,
+			fromStatus: fromStatus,
+			status:     status,
+			path:       path,
+			branchName: branchName,
+			baseBranch: baseBranch,
+			err:        err,
+		}
+	}
+}
+
+// handleWorktreeCreated records a worktree created in the background and
+// finishes the move that asked for it. The move is skipped when the ticket
+// changed column meanwhile; a worktree created for a ticket deleted
+// meanwhile is removed again with its branch.
+func (m *Model) handleWorktreeCreated(msg worktreeCreatedMsg) tea.Cmd {
+	m.endGitOp()
+	delete(m.pendingWorktrees, msg.ticketID)
+
+	ticket, _ := m.globalStore.Get(msg.ticketID)
+	if ticket == nil {
+		if msg.err != nil || msg.linked {
+			return nil
+		}
+		return m.removeAbandonedWorktree(msg)
+	}
+	if msg.err != nil {
+		m.notify("Worktree failed: " + msg.err.Error())
+		return nil
+	}
+
+	ticket.WorktreePath = msg.path
+	ticket.BranchName = msg.branchName
+	ticket.BaseBranch = msg.baseBranch
+	if msg.linked {
+		m.saveTicket(ticket)
+		m.notify("Linked to branch " + msg.branchName)
+		return nil
+	}
+	if ticket.Status != msg.fromStatus {
+		m.saveTicket(ticket)
+		m.refreshColumnTickets()
+		m.notify("Worktree ready; not moved to " + string(msg.status) + " since the ticket moved meanwhile")
+		return nil
+	}
+	m.finishMove(ticket, msg.status)
+	return nil
+}
+
+// abandonedWorktreeMsg reports the removal of a worktree whose ticket was
+// deleted while it was being created.
+type abandonedWorktreeMsg struct {
+	err error
+}
+
+// removeAbandonedWorktree removes the worktree and branch created for a
+// ticket that no longer exists.
+func (m *Model) removeAbandonedWorktree(msg worktreeCreatedMsg) tea.Cmd {
+	mgr := m.worktreeMgrs[msg.projectID]
+	if mgr == nil {
+		return nil
+	}
+	m.startGitOp("Removing worktree")
+	return func() tea.Msg {
+		if err := mgr.RemoveWorktree(msg.path); err != nil {
+			return abandonedWorktreeMsg{err: err}
+		}
+		return abandonedWorktreeMsg{err: mgr.DeleteBranch(msg.branchName)}
+	}
+}
+
+func (m *Model) handleAbandonedWorktree(msg abandonedWorktreeMsg) {
+	m.endGitOp()
+	if msg.err != nil {
+		m.notify("Failed to remove worktree of deleted ticket: " + msg.err.Error())
+	}
+}
+
+func (m *Model) finishMove(ticket *board.Ticket, status board.TicketStatus) {
+	m.globalStore.Move(ticket.ID, status)
+	m.refreshColumnTickets()
+	m.selectTicketByID(ticket.ID)
+	m.saveTicket(ticket)
+	m.notify("Moved to " + string(status))
+}
+
+// undoLimit is how many moves and deletes u can revert.
+const undoLimit = 20
+
+// undoAction is the state of a ticket before a move or delete.
+type undoAction struct {
+	ticketID board.TicketID
+
+	// Moves restore the ticket's column and timestamps.
+	status      board.TicketStatus
+	startedAt   *time.Time
+	completedAt *time.Time
+	updatedAt   time.Time
+	historyLen  int
+
+	// Deletes re-add the removed ticket and its blocker references.
+	deleted         *board.Ticket
+	blocks          []board.TicketID
+	worktreeRemoved bool
+	branchDeleted   bool
+}
+
+func (m *Model) pushUndo(action undoAction) {
+	m.undoStack = append(m.undoStack, action)
+	if len(m.undoStack) > undoLimit {
+		m.undoStack = m.undoStack[len(m.undoStack)-undoLimit:]
+	}
+}
+
+// recordMove remembers ticket's column before it is moved.
+func (m *Model) recordMove(ticket *board.Ticket) {
+	m.pushUndo(undoAction{
+		ticketID:    ticket.ID,
+		status:      ticket.Status,
+		startedAt:   ticket.StartedAt,
+		completedAt: ticket.CompletedAt,
+		updatedAt:   ticket.UpdatedAt,
+		historyLen:  len(ticket.History),
+	})
+}
+
+// recordDelete remembers ticket, and the tickets it blocks, before it is
+// deleted. Removed worktrees and branches can't be brought back, so undo
+// only restores the ticket itself.
+func (m *Model) recordDelete(ticket *board.Ticket, worktreeRemoved, branchDeleted bool) {
+	var blocks []board.TicketID
+	for _, t := range m.globalStore.GetBlocks(ticket.ID) {
+		blocks = append(blocks, t.ID)
+	}
+	m.pushUndo(undoAction{
+		ticketID:        ticket.ID,
+		deleted:         ticket,
+		blocks:          blocks,
+		worktreeRemoved: worktreeRemoved,
+		branchDeleted:   branchDeleted,
+	})
+}
+
+// undo reverts the most recent move or delete.
+func (m *Model) undo() {
+	if m.denyReadOnly() {
+		return
+	}
+	if len(m.undoStack) == 0 {
+		m.notify("Nothing to undo")
+		return
+	}
+	action := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+
+	if action.deleted != nil {
+		m.undoDelete(action)
+		return
+	}
+
+	ticket, _ := m.globalStore.Get(action.ticketID)
+	if ticket == nil {
+		m.notify("Can't undo move: ticket no longer exists")
+		return
+	}
+	ticket.Status = action.status
+	ticket.StartedAt = action.startedAt
+	ticket.CompletedAt = action.completedAt
+	ticket.UpdatedAt = action.updatedAt
+	ticket.History = ticket.History[:min(action.historyLen, len(ticket.History))]
+	m.refreshColumnTickets()
+	m.selectTicketByID(ticket.ID)
+	m.saveTicket(ticket)
+	m.notify("Undid move: back to " + string(ticket.Status))
+}
+
+func (m *Model) undoDelete(action undoAction) {
+	ticket := action.deleted
+	if _, err := m.globalStore.Get(ticket.ID); err == nil {
+		m.notify("Can't undo delete: ticket already exists")
+		return
+	}
+	ticket.AgentStatus = board.AgentNone
+	if action.worktreeRemoved {
+		ticket.WorktreePath = ""
+	}
+	if err := m.globalStore.Add(ticket); err != nil {
+		m.notify("Can't undo delete: project no longer exists")
+		return
+	}
+	for _, id := range action.blocks {
+		if blocked, _ := m.globalStore.Get(id); blocked != nil && !slices.Contains(blocked.BlockedBy, ticket.ID) {
+			blocked.BlockedBy = append(blocked.BlockedBy, ticket.ID)
+		}
+	}
+	m.globalStore.SaveAll()
+	m.refreshColumnTickets()
+	m.selectTicketByID(ticket.ID)
+
+	var removed string
+	switch {
+	case action.worktreeRemoved && action.branchDeleted:
+		removed = "worktree and branch"
+	case action.worktreeRemoved:
+		removed = "worktree"
+	case action.branchDeleted:
+		removed = "branch"
+	}
+	if removed != "" {
+		m.notify("Restored " + ticket.Title + " (ticket only; its " + removed + " can't be restored)")
+		return
+	}
+	m.notify("Restored: " + ticket.Title)
+}
+
+// startGitOp shows label with a spinner in the status bar until the
+// matching endGitOp.
+func (m *Model) startGitOp(label string) {
+	m.gitOps++
+	m.gitOpLabel = label
+}
+
+func (m *Model) endGitOp() {
+	m.gitOps = max(m.gitOps-1, 0)
+	if m.gitOps == 0 {
+		m.gitOpLabel = ""
+	}
+}
+
+// needsBranchPreview reports whether starting ticket should first show the
+// generated branch name for confirmation (behavior.confirm_branch_name).
+func (m *Model) needsBranchPreview(ticket *board.Ticket) bool {
+	if !m.config.Behavior.ConfirmBranchName {
+		return false
+	}
+	if ticket.WorktreePath != "" {
+		return false
+	}
+	return m.globalStore.GetProjectForTicket(ticket) != nil
+}
+
+func (m *Model) openBranchPreview(ticket *board.Ticket) tea.Cmd {
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	m.branchPreviewTicketID = ticket.ID
+	m.branchPreviewInput.SetValue(m.generateBranchName(ticket, proj))
+	m.branchPreviewInput.CursorEnd()
+	m.mode = ModeBranchPreview
+	return m.branchPreviewInput.Focus()
+}
+
+func (m *Model) handleBranchPreviewMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		return m.confirmBranchPreview()
+	case "esc", "ctrl+c":
+		m.branchPreviewInput.Blur()
+		m.mode = ModeNormal
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.branchPreviewInput, cmd = m.branchPreviewInput.Update(msg)
+	return m, cmd
+}
+
+// confirmBranchPreview starts the previewed ticket on the branch name
+// entered by the user.
+func (m *Model) confirmBranchPreview() (tea.Model, tea.Cmd) {
+	ticket, _ := m.globalStore.Get(m.branchPreviewTicketID)
+	if ticket == nil {
+		m.branchPreviewInput.Blur()
+		m.mode = ModeNormal
+		return m, nil
+	}
+
+	branchName := strings.TrimSpace(m.branchPreviewInput.Value())
+	if err := git.ValidateBranchName(branchName); err != nil {
+		m.notify("Error: " + err.Error())
+		return m, nil
+	}
+
+	m.branchPreviewInput.Blur()
+	m.mode = ModeNormal
+
+	ticket.BranchName = branchName
+	return m, m.startTicketWork(ticket, board.StatusInProgress)
+}
+
+// externalWorktree is a validated, user-supplied worktree waiting to be
+// attached to a ticket. A zero path detaches the current one.
+type externalWorktree struct {
+	path       string
+	branchName string
+	baseBranch string
+}
+
+func (w *externalWorktree) apply(ticket *board.Ticket) {
+	if w.path == "" {
+		ticket.WorktreePath = ""
+		if ticket.WorktreeExternal {
+			ticket.BranchName = ""
+			ticket.BaseBranch = ""
+		}
+		ticket.WorktreeExternal = false
+		return
+	}
+	ticket.WorktreePath = w.path
+	ticket.BranchName = w.branchName
+	ticket.BaseBranch = w.baseBranch
+	ticket.UseWorktree = true
+	ticket.WorktreeExternal = true
+}
+
+// errManagedWorktree refuses to detach or replace a worktree OpenKanban
+// created, which would leave it on disk untracked.
+var errManagedWorktree = errors.New("remove the ticket's worktree first (W, then d)")
+
+// resolveWorktreePath validates a worktree path entered in the edit form.
+// It returns nil when the path is unchanged.
+func (m *Model) resolveWorktreePath(ticket *board.Ticket, raw string) (*externalWorktree, error) {
+	path := strings.TrimSpace(raw)
+	if path == "" {
+		if ticket.WorktreePath == "" {
+			return nil, nil
+		}
+		if m.hasManagedWorktree(ticket) {
+			return nil, errManagedWorktree
+		}
+		return &externalWorktree{}, nil
+	}
+
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if absPath == ticket.WorktreePath {
+		return nil, nil
+	}
+	if m.hasManagedWorktree(ticket) {
+		return nil, errManagedWorktree
+	}
+
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil {
+		return nil, errOrphanedTicket
+	}
+	mgr := m.worktreeMgrs[proj.ID]
+	if mgr == nil {
+		return nil, fmt.Errorf("worktree manager not found")
+	}
+	if err := mgr.ValidateExternalWorktree(absPath); err != nil {
+		return nil, err
+	}
+
+	branchName, err := git.CurrentBranch(absPath)
+	if err != nil {
+		return nil, err
+	}
+	baseBranch, _ := mgr.GetDefaultBranch()
+
+	return &externalWorktree{path: absPath, branchName: branchName, baseBranch: baseBranch}, nil
+}
+
+// hasManagedWorktree reports whether ticket has a worktree OpenKanban created
+// for it, as opposed to an external one or its project's main checkout.
+func (m *Model) hasManagedWorktree(ticket *board.Ticket) bool {
+	if ticket.WorktreePath == "" || ticket.WorktreeExternal {
+		return false
+	}
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	return proj == nil || ticket.WorktreePath != proj.RepoPath
+}
+
+// currentBaseBranch returns the branch checked out in proj's repo, so new
+// tickets branch off whatever the user is working on, falling back to the
+// default branch when HEAD is detached or unreadable.
+func (m *Model) currentBaseBranch(proj *project.Project) string {
+	if proj == nil {
+		return ""
+	}
+	if branch, err := git.CurrentBranch(proj.RepoPath); err == nil && branch != "" && branch != "HEAD" {
+		return branch
+	}
+	if mgr := m.worktreeMgrs[proj.ID]; mgr != nil {
+		branch, _ := mgr.GetDefaultBranch()
+		return branch
+	}
+	return ""
+}
+
+// syncBaseBranchPrefill refills the new ticket's base branch after the
+// form's project changes, unless the user has edited it.
+func (m *Model) syncBaseBranchPrefill() {
+	if m.mode != ModeCreateTicket || m.selectedProject == nil || m.selectedProject.ID == m.baseBranchProjectID {
+		return
+	}
+	if m.baseBranchInput.Value() == m.baseBranchPrefill {
+		m.baseBranchPrefill = m.currentBaseBranch(m.selectedProject)
+		m.baseBranchInput.SetValue(m.baseBranchPrefill)
+	}
+	m.baseBranchProjectID = m.selectedProject.ID
+}
+
+func (m *Model) setupMainRepoBranch(ticket *board.Ticket) error {
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil {
+		return errOrphanedTicket
+	}
+
+	mgr := m.worktreeMgrs[proj.ID]
+	if mgr == nil {
+		return fmt.Errorf("worktree manager not found")
+	}
+
+	branchName := m.generateBranchName(ticket, proj)
+	baseBranch := ticket.BaseBranch
+	if baseBranch == "" {
+		baseBranch, _ = mgr.GetDefaultBranch()
+	}
+
+	ticket.WorktreePath = proj.RepoPath
+	ticket.BranchName = branchName
+	ticket.BaseBranch = baseBranch
+	return nil
+}
+
+// generateBranchNameFromTitle expands the branch template for a ticket
+// with the given title and number; the number fills in {id}.
+func (m *Model) generateBranchNameFromTitle(title string, number int, proj *project.Project) string {
+	maxLen := m.getSlugMaxLength(proj)
+	slug := board.Slugify(title, maxLen)
+
+	template := m.getBranchTemplate(proj)
+	prefix := m.getBranchPrefix(proj)
+	var id string
+	if proj != nil {
+		id = proj.TicketKey(number)
+	}
+
+	result := strings.ReplaceAll(template, "{prefix}", prefix)
+	result = strings.ReplaceAll(result, "{slug}", slug)
+	result = strings.ReplaceAll(result, "{id}", id)
+
+	return result
+}
+
+func (m *Model) generateBranchName(ticket *board.Ticket, proj *project.Project) string {
+	if ticket.BranchName != "" {
+		return ticket.BranchName
+	}
+	return m.generateBranchNameFromTitle(ticket.Title, ticket.Number, proj)
+}
+
+// allocateAgentPort picks a port from behavior.agent_port_base and
+// agent_port_range that no ticket or quick agent holds and nothing on the
+// host is listening on.
+func (m *Model) allocateAgentPort() (int, error) {
+	usedPorts := make(map[int]bool)
+	for _, t := range m.globalStore.All() {
+		if t.AgentPort > 0 {
+			usedPorts[t.AgentPort] = true
+		}
+	}
+	for _, quick := range m.quickAgents {
+		if quick.port > 0 {
+			usedPorts[quick.port] = true
+		}
+	}
+
+	return agent.AllocatePort(m.config.Behavior.AgentPortBase, m.config.Behavior.AgentPortRange, usedPorts)
+}
+
+func (m *Model) spawnAgent() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+
+	if ticket.Status != board.StatusInProgress {
+		m.notify("Press Space to move to In Progress first")
+		return m, nil
+	}
+
+	if _, exists := m.panes[ticket.ID]; exists {
+		m.notify("Agent already running — press Enter to attach")
+		return m, nil
+	}
+
+	if m.blockedFromStarting(ticket) {
+		return m, nil
+	}
+
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil {
+		m.notify(errOrphanedTicket.Error())
+		return m, nil
+	}
+
+	if m.mainRepoAgentRunning(ticket, proj) {
+		m.notify("Another main-repo agent is running in this project")
+		return m, nil
+	}
+
+	agentType := ticket.NextAgentType()
+	if agentType == "" {
+		agentType = m.config.Defaults.DefaultAgent
+	}
+	agentCfg, ok := m.config.Agents[agentType]
+	if !ok {
+		m.notify("Agent '" + agentType + "' not configured")
+		return m, nil
+	}
+
+	target := spawnTarget{ticket: ticket, proj: proj, agentType: agentType, agentCfg: agentCfg}
+	if m.needsSpawnPromptPreview(target) {
+		return m, m.openSpawnPromptPreview(target)
+	}
+	return m, m.continueSpawn(target)
+}
+
+// continueSpawn starts target's agent, or queues it when all agent slots
+// are taken.
+func (m *Model) continueSpawn(target spawnTarget) tea.Cmd {
+	ticket, proj, agentType, agentCfg := target.ticket, target.proj, target.agentType, target.agentCfg
+	if m.agentSlotsFull() {
+		m.queueSpawn(ticket)
+		return nil
+	}
+
+	if m.config.Behavior.ConfirmParallelAgents {
+		if running := m.runningAgentsInProject(proj.ID); running > 0 {
+			m.showConfirm = true
+			m.confirmMsg = fmt.Sprintf("%d agent(s) already running in this repo — continue?", running)
+			m.confirmFn = func() tea.Cmd {
+				return m.startSpawn(ticket, proj, agentType, agentCfg)
+			}
+			return nil
+		}
+	}
+
+	return m.startSpawn(ticket, proj, agentType, agentCfg)
+}
+
+// promptAgents are the agent commands given the init prompt when they start
+// a new session.
+var promptAgents = map[string]bool{
+	"claude":       true,
+	"opencode":     true,
+	"gemini":       true,
+	"codex":        true,
+	"qwen":         true,
+	"cursor-agent": true,
+}
+
+// agentCommandType returns the agent type prepareSpawn dispatches on: the
+// base name of the agent's command.
+func agentCommandType(agentCfg config.AgentConfig) string {
+	return filepath.Base(agentCfg.Command)
+}
+
+// needsSpawnPromptPreview reports whether spawning target should first
+// show its init prompt for editing (behavior.confirm_spawn_prompt). Only
+// new sessions of agents that take a prompt have one to show.
+func (m *Model) needsSpawnPromptPreview(target spawnTarget) bool {
+	if !m.config.Behavior.ConfirmSpawnPrompt || !promptAgents[agentCommandType(target.agentCfg)] {
+		return false
+	}
+	ticket := target.ticket
+	switching := ticket.AgentSpawnedAt != nil && ticket.AgentType != "" && target.agentType != ticket.AgentType
+	return ticket.AgentSpawnedAt == nil || switching
+}
+
+// spawnPromptFor renders the init prompt target's agent would be given,
+// filling in the branch names the spawn will use if the ticket has none yet.
+func (m *Model) spawnPromptFor(target spawnTarget) string {
+	preview := *target.ticket
+	preview.BranchName = m.generateBranchName(target.ticket, target.proj)
+	if preview.BaseBranch == "" {
+		if mgr := m.worktreeMgrs[target.proj.ID]; mgr != nil {
+			preview.BaseBranch, _ = mgr.GetDefaultBranch()
+		}
+	}
+	promptTemplate := m.config.GetEffectiveInitPrompt(agentCommandType(target.agentCfg))
+	return agent.BuildContextPrompt(promptTemplate, &preview)
+}
+
+func (m *Model) openSpawnPromptPreview(target spawnTarget) tea.Cmd {
+	m.pendingSpawnPrompt = &target
+	m.spawnPromptInput.SetWidth(max(min(m.width-16, 100), 30))
+	m.spawnPromptInput.SetHeight(max(min(m.height-14, 20), 5))
+	m.spawnPromptInput.SetValue(m.spawnPromptFor(target))
+	m.mode = ModeSpawnPrompt
+	return m.spawnPromptInput.Focus()
+}
+
+func (m *Model) handleSpawnPromptMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+s":
+		return m, m.confirmSpawnPrompt()
+	case "ctrl+r":
+		if m.pendingSpawnPrompt != nil {
+			m.spawnPromptInput.SetValue(m.spawnPromptFor(*m.pendingSpawnPrompt))
+		}
+		return m, nil
+	case "ctrl+c":
+		m.closeSpawnPromptPreview()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.spawnPromptInput, cmd = m.spawnPromptInput.Update(msg)
+	return m, cmd
+}
+
+// confirmSpawnPrompt spawns the pending ticket with the edited prompt. An
+// empty prompt starts the agent without one.
+func (m *Model) confirmSpawnPrompt() tea.Cmd {
+	target := m.pendingSpawnPrompt
+	prompt := strings.TrimSpace(m.spawnPromptInput.Value())
+	m.closeSpawnPromptPreview()
+	if target == nil {
+		return nil
+	}
+	if _, exists := m.panes[target.ticket.ID]; exists {
+		m.notify("Agent already running — press Enter to attach")
+		return nil
+	}
+	m.spawnPrompts[target.ticket.ID] = prompt
+	return m.continueSpawn(*target)
+}
+
+func (m *Model) closeSpawnPromptPreview() {
+	m.spawnPromptInput.Blur()
+	m.pendingSpawnPrompt = nil
+	if m.mode == ModeSpawnPrompt {
+		m.mode = ModeNormal
+	}
+}
+
+// mainRepoAgentRunning reports whether ticket would share proj's main repo
+// with another ticket's agent. Tickets using worktrees never conflict.
+func (m *Model) mainRepoAgentRunning(ticket *board.Ticket, proj *project.Project) bool {
+	if ticket.UseWorktree {
+		return false
+	}
+	for otherID := range m.panes {
+		if otherID == ticket.ID {
+			continue
+		}
+		other, _ := m.globalStore.Get(otherID)
+		if other != nil && !other.UseWorktree {
+			otherProj := m.globalStore.GetProjectForTicket(other)
+			if otherProj != nil && otherProj.ID == proj.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// activeAgentCount counts running agents plus those still being spawned.
+func (m *Model) activeAgentCount() int {
+	count := m.RunningAgentCount() + len(m.bulkSpawns)
+	if m.spawningTicketID != "" {
+		count++
+	}
+	return count
+}
+
+// agentSlotsFull reports whether behavior.max_concurrent_agents is set and
+// already reached.
+func (m *Model) agentSlotsFull() bool {
+	limit := m.config.Behavior.MaxConcurrentAgents
+	return limit > 0 && m.activeAgentCount() >= limit
+}
+
+// queueSpawn adds ticket to the spawn queue so it starts once an agent
+// slot frees up.
+func (m *Model) queueSpawn(ticket *board.Ticket) {
+	if pos := slices.Index(m.spawnQueue, ticket.ID); pos >= 0 {
+		m.notify(fmt.Sprintf("Already queued (%d of %d) — press S to unqueue", pos+1, len(m.spawnQueue)))
+		return
+	}
+	m.spawnQueue = append(m.spawnQueue, ticket.ID)
+	m.notify(fmt.Sprintf("Agent limit reached (%d) — queued %s (%d of %d)",
+		m.config.Behavior.MaxConcurrentAgents, ticket.Title, len(m.spawnQueue), len(m.spawnQueue)))
+}
+
+// unqueueSpawn removes id from the spawn queue, reporting whether it was
+// queued.
+func (m *Model) unqueueSpawn(id board.TicketID) bool {
+	pos := slices.Index(m.spawnQueue, id)
+	if pos < 0 {
+		return false
+	}
+	m.spawnQueue = slices.Delete(m.spawnQueue, pos, pos+1)
+	return true
+}
+
+// startQueuedSpawns starts queued tickets in the background while agent
+// slots are free. Tickets that can no longer be spawned, or have left their
+// project's working columns, are dropped; those waiting on a blocker or the
+// main repo stay queued.
+func (m *Model) startQueuedSpawns() tea.Cmd {
+	if len(m.spawnQueue) == 0 {
+		return nil
+	}
+	if len(m.bulkSpawns) == 0 {
+		m.bulkStarted = 0
+		m.bulkFailures = nil
+	}
+
+	var cmds []tea.Cmd
+	var remaining []board.TicketID
+	for _, id := range m.spawnQueue {
+		ticket, _ := m.globalStore.Get(id)
+		if ticket == nil || !m.inWorkingColumn(ticket) {
+			continue
+		}
+		if _, exists := m.panes[id]; exists || m.spawningTicketID == id {
+			continue
+		}
+		if _, spawning := m.bulkSpawns[id]; spawning {
+			continue
+		}
+		proj := m.globalStore.GetProjectForTicket(ticket)
+		agentType := ticket.NextAgentType()
+		if agentType == "" {
+			agentType = m.config.Defaults.DefaultAgent
+		}
+		agentCfg, configured := m.config.Agents[agentType]
+		if proj == nil || !configured {
+			continue
+		}
+		blocked := m.config.Behavior.EnforceBlockers && len(m.globalStore.OpenBlockers(id)) > 0
+		if m.agentSlotsFull() || blocked || m.mainRepoAgentRunning(ticket, proj) {
+			remaining = append(remaining, id)
+			continue
+		}
+
+		m.bulkSpawns[id] = agentType
+		m.startGitOp("Spawning agents")
+		cmds = append(cmds, m.prepareSpawn(ticket, proj, agentType, agentCfg))
+	}
+	m.spawnQueue = remaining
+
+	if len(cmds) == 0 {
+		return nil
+	}
+	m.notify(fmt.Sprintf("Starting %d queued agent(s)…", len(cmds)))
+	return tea.Batch(cmds...)
+}
+
+// applySpawnReady records a finished spawn on its ticket and starts the
+// agent in its pane.
+func (m *Model) applySpawnReady(msg spawnReadyMsg, agentType string) tea.Cmd {
+	ticket, _ := m.globalStore.Get(msg.ticketID)
+	if ticket != nil {
+		if ticket.AgentType != "" && ticket.AgentType != agentType {
+			// A switched agent starts a new session.
+			ticket.AgentSpawnedAt = nil
+			ticket.AgentSessionID = ""
+		}
+		ticket.AgentType = agentType
+		if ticket.AgentOverride == agentType {
+			ticket.AgentOverride = ""
+		}
+		ticket.AgentStatus = board.AgentNone
+		if ticket.AgentSpawnedAt == nil {
+			now := time.Now()
+			ticket.AgentSpawnedAt = &now
+		}
+		if msg.worktreePath != "" && ticket.WorktreePath == "" {
+			ticket.WorktreePath = msg.worktreePath
+			ticket.BranchName = msg.branchName
+			ticket.BaseBranch = msg.baseBranch
+		}
+		m.saveTicket(ticket)
+	}
+
+	m.lastSpawns[msg.ticketID] = spawnRecord{
+		agentType:   agentType,
+		command:     msg.command,
+		args:        slices.Clone(msg.args),
+		workdir:     msg.pane.GetWorkdir(),
+		sessionName: msg.pane.GetSessionName(),
+		env:         msg.pane.Env(),
+		scrollback:  msg.pane.ScrollbackSize(),
+	}
+	m.panes[msg.ticketID] = msg.pane
+	// The window may have resized while the worktree was being set up.
+	msg.pane.SetSize(m.agentPaneSize())
+	return msg.pane.Start(msg.command, msg.args...)
+}
+
+// spawnRecord is the exact invocation of a ticket's last agent.
+type spawnRecord struct {
+	agentType   string
+	command     string
+	args        []string
+	workdir     string
+	sessionName string
+	env         map[string]string
+	scrollback  int
+}
+
+// respawnLast starts the selected ticket's last agent command again with
+// the same arguments and directory, without re-reading the agent config or
+// rebuilding the prompt. The previous agent must have exited.
+func (m *Model) respawnLast() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+	rec, ok := m.lastSpawns[ticket.ID]
+	if !ok {
+		m.notify("No agent has run for this ticket this session")
+		return m, nil
+	}
+	if pane, ok := m.panes[ticket.ID]; ok && pane.Running() {
+		m.notify("Agent is still running; stop it with S first")
+		return m, nil
+	}
+	if _, err := os.Stat(rec.workdir); err != nil {
+		m.notify("Failed to re-run agent: " + rec.workdir + " no longer exists")
+		return m, nil
+	}
+
+	m.unqueueSpawn(ticket.ID)
+
+	width, height := m.agentPaneSize()
+	pane := terminal.New(string(ticket.ID), width, height, rec.scrollback)
+	pane.SetWorkdir(rec.workdir)
+	pane.SetSessionName(rec.sessionName)
+	pane.SetEnv(rec.env)
+	if m.config.Behavior.LogAgentOutput {
+		pane.SetLogFile(agent.AgentLogPath(string(ticket.ID)))
+		pane.SetLogTimestamps(m.config.Logging.TimestampLog)
+	}
+	agent.CleanupStatusFile(rec.sessionName)
+
+	m.notify("Re-running " + rec.agentType + " with its last command")
+	start := m.applySpawnReady(spawnReadyMsg{
+		ticketID: ticket.ID,
+		pane:     pane,
+		command:  rec.command,
+		args:     rec.args,
+	}, rec.agentType)
+	if rec.agentType == "opencode" {
+		return m, tea.Sequence(m.ensureOpencodeServer(), start)
+	}
+	return m, start
+}
+
+// restartAgent stops the selected ticket's agent, if any, and spawns it
+// again as a new session: session detection is skipped and the init prompt
+// is sent again. The ticket keeps its worktree and branch.
+func (m *Model) restartAgent() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+	if _, running := m.panes[ticket.ID]; !running && ticket.AgentSpawnedAt == nil {
+		m.notify("No agent to restart — press s to spawn one")
+		return m, nil
+	}
+	if ticket.Status != board.StatusInProgress {
+		m.notify("Only In Progress tickets can run agents")
+		return m, nil
+	}
+	if m.restarting[ticket.ID] || m.spawningTicketID == ticket.ID {
+		return m, nil
+	}
+	if _, spawning := m.bulkSpawns[ticket.ID]; spawning {
+		m.notify("Agent is still starting")
+		return m, nil
+	}
+
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil {
+		m.notify(errOrphanedTicket.Error())
+		return m, nil
+	}
+	if m.mainRepoAgentRunning(ticket, proj) {
+		m.notify("Another main-repo agent is running in this project")
+		return m, nil
+	}
+
+	agentType := ticket.NextAgentType()
+	if agentType == "" {
+		agentType = m.config.Defaults.DefaultAgent
+	}
+	agentCfg, ok := m.config.Agents[agentType]
+	if !ok {
+		m.notify("Agent '" + agentType + "' not configured")
+		return m, nil
+	}
+
+	ticket.AgentStatus = board.AgentNone
+	ticket.AgentSpawnedAt = nil
+	ticket.AgentSessionID = ""
+	m.saveTicket(ticket)
+
+	m.notify("Restarting " + agentType + " with a fresh session")
+
+	// The old pane's exit arrives under the same ID as the new pane, so
+	// wait for it before spawning; queueRestart picks the ticket up then.
+	if pane, ok := m.panes[ticket.ID]; ok {
+		m.restarting[ticket.ID] = true
+		pane.Stop()
+		return m, nil
+	}
+	return m, m.startSpawn(ticket, proj, agentType, agentCfg)
+}
+
+// queueRestart finishes a restart once the old pane of ticketID has exited,
+// queueing the ticket to spawn again. It reports whether ticketID was
+// restarting.
+func (m *Model) queueRestart(ticketID board.TicketID) bool {
+	if !m.restarting[ticketID] {
+		return false
+	}
+	delete(m.restarting, ticketID)
+	delete(m.panes, ticketID)
+	if !slices.Contains(m.spawnQueue, ticketID) {
+		m.spawnQueue = append(m.spawnQueue, ticketID)
+	}
+	return true
+}
+
+// confirmBulkSpawn asks before starting agents in the background for every
+// In Progress ticket on the board that has none, up to the column's WIP
+// limit.
+func (m *Model) confirmBulkSpawn() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	column := slices.IndexFunc(m.columns, func(c board.Column) bool {
+		return c.Status == board.StatusInProgress
+	})
+	if column < 0 || column >= len(m.columnTickets) {
+		m.notify("No In Progress column on the board")
+		return m, nil
+	}
+	if len(m.bulkSpawns) > 0 {
+		m.notify("Agents are still spawning")
+		return m, nil
+	}
+
+	limit := m.columns[column].Limit
+	capacity := -1
+	if limit > 0 {
+		capacity = limit
+		for _, t := range m.columnTickets[column] {
+			if pane, ok := m.panes[t.ID]; ok && pane.Running() {
+				capacity--
+			}
+		}
+	}
+	return m.confirmSpawnTickets(m.columnTickets[column], capacity, "In Progress")
+}
+
+// spawnTarget is a ticket ready to spawn with a resolved agent.
+type spawnTarget struct {
+	ticket    *board.Ticket
+	proj      *project.Project
+	agentType string
+	agentCfg  config.AgentConfig
+}
+
+// confirmSpawnTickets asks before starting agents in the background for the
+// In Progress tickets among candidates that have none, at most capacity of
+// them unless it is negative. what describes the tickets in the prompt.
+func (m *Model) confirmSpawnTickets(candidates []*board.Ticket, capacity int, what string) (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	slots := -1
+	if maxAgents := m.config.Behavior.MaxConcurrentAgents; maxAgents > 0 {
+		slots = max(maxAgents-m.activeAgentCount(), 0)
+	}
+
+	var targets []spawnTarget
+	var queued []*board.Ticket
+	skipped := 0
+	mainRepoProjects := make(map[string]bool)
+	for id := range m.panes {
+		if other, _ := m.globalStore.Get(id); other != nil && !other.UseWorktree {
+			mainRepoProjects[other.ProjectID] = true
+		}
+	}
+	for _, t := range candidates {
+		if _, exists := m.panes[t.ID]; exists || slices.Contains(m.spawnQueue, t.ID) {
+			continue
+		}
+		if t.Status != board.StatusInProgress {
+			skipped++
+			continue
+		}
+		proj := m.globalStore.GetProjectForTicket(t)
+		agentType := t.NextAgentType()
+		if agentType == "" {
+			agentType = m.config.Defaults.DefaultAgent
+		}
+		agentCfg, configured := m.config.Agents[agentType]
+		blocked := m.config.Behavior.EnforceBlockers && len(m.globalStore.OpenBlockers(t.ID)) > 0
+		if proj == nil || !configured || blocked || (!t.UseWorktree && mainRepoProjects[proj.ID]) {
+			skipped++
+			continue
+		}
+		if capacity >= 0 && len(targets)+len(queued) >= capacity {
+			skipped++
+			continue
+		}
+		if slots >= 0 && len(targets) >= slots {
+			queued = append(queued, t)
+			continue
+		}
+		if !t.UseWorktree {
+			mainRepoProjects[proj.ID] = true
+		}
+		targets = append(targets, spawnTarget{ticket: t, proj: proj, agentType: agentType, agentCfg: agentCfg})
+	}
+
+	if len(targets) == 0 && len(queued) == 0 {
+		m.notify(fmt.Sprintf("No %s tickets to spawn (%d skipped)", what, skipped))
+		return m, nil
+	}
+
+	m.showConfirm = true
+	m.confirmMsg = fmt.Sprintf("Spawn agents for %d %s ticket(s)?", len(targets)+len(queued), what)
+	if len(queued) > 0 {
+		m.confirmMsg += fmt.Sprintf(" (%d queued until agents free up)", len(queued))
+	}
+	if skipped > 0 {
+		m.confirmMsg += fmt.Sprintf(" (%d skipped: not In Progress, blocked, unconfigured or over the WIP limit)", skipped)
+	}
+	m.confirmFn = func() tea.Cmd {
+		for _, t := range queued {
+			m.spawnQueue = append(m.spawnQueue, t.ID)
+		}
+		if len(targets) == 0 {
+			m.notify(fmt.Sprintf("Queued %d agent(s)", len(queued)))
+			return nil
+		}
+		m.bulkStarted = 0
+		m.bulkFailures = nil
+		var cmds []tea.Cmd
+		for _, target := range targets {
+			m.bulkSpawns[target.ticket.ID] = target.agentType
+			m.startGitOp("Spawning agents")
+			cmds = append(cmds, m.prepareSpawn(target.ticket, target.proj, target.agentType, target.agentCfg))
+		}
+		m.notify(fmt.Sprintf("Spawning %d agent(s)…", len(targets)))
+		return tea.Batch(cmds...)
+	}
+	return m, nil
+}
+
+func (m *Model) handleBulkSpawnReady(msg spawnReadyMsg) tea.Cmd {
+	agentType := m.bulkSpawns[msg.ticketID]
+	delete(m.bulkSpawns, msg.ticketID)
+	m.endGitOp()
+	m.bulkStarted++
+	cmd := m.applySpawnReady(msg, agentType)
+	m.finishBulkSpawn()
+	return cmd
+}
+
+func (m *Model) handleBulkSpawnError(msg spawnErrorMsg) {
+	delete(m.bulkSpawns, msg.ticketID)
+	m.endGitOp()
+	title := string(msg.ticketID)
+	if ticket, _ := m.globalStore.Get(msg.ticketID); ticket != nil {
+		title = ticket.Title
+	}
+	failure := title + ": " + msg.err
+	m.bulkFailures = append(m.bulkFailures, failure)
+	m.notify("Spawn failed for " + failure)
+	m.finishBulkSpawn()
+}
+
+// finishBulkSpawn summarizes a bulk spawn once every ticket has reported.
+func (m *Model) finishBulkSpawn() {
+	if len(m.bulkSpawns) > 0 {
+		return
+	}
+	if len(m.bulkFailures) == 0 {
+		m.notify(fmt.Sprintf("Started %d agent(s)", m.bulkStarted))
+		return
+	}
+	m.notify(fmt.Sprintf("Started %d agent(s), %d failed: %s", m.bulkStarted, len(m.bulkFailures), strings.Join(m.bulkFailures, "; ")))
+}
+
+func (m *Model) startSpawn(ticket *board.Ticket, proj *project.Project, agentType string, agentCfg config.AgentConfig) tea.Cmd {
+	m.unqueueSpawn(ticket.ID)
+	m.mode = ModeSpawning
+	m.spawningTicketID = ticket.ID
+	m.spawningAgent = agentType
+
+	return tea.Batch(m.spinner.Tick, m.prepareSpawn(ticket, proj, agentType, agentCfg))
+}
+
+// runningAgentsInProject counts running agents for tickets in projectID.
+func (m *Model) runningAgentsInProject(projectID string) int {
+	count := 0
+	for ticketID, pane := range m.panes {
+		if !pane.Running() {
+			continue
+		}
+		if t, _ := m.globalStore.Get(ticketID); t != nil && t.ProjectID == projectID {
+			count++
+		}
+	}
+	return count
+}
+
+// prepareSpawn sets up the ticket's branch or worktree in the background and
+// builds the pane and command for agentName, the configured agent key.
+func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentName string, agentCfg config.AgentConfig) tea.Cmd {
+	ticketID := ticket.ID
+	worktreePath := ticket.WorktreePath
+	branchName := ticket.BranchName
+	baseBranch := ticket.BaseBranch
+	useWorktree := ticket.UseWorktree
+	width, height := m.agentPaneSize()
+	scrollback := m.config.GetScrollbackLines(agentName)
+	logOutput := m.config.Behavior.LogAgentOutput
+	logTimestamps := m.config.Logging.TimestampLog
+	// Switching agent type leaves nothing for the new agent to resume.
+	switching := ticket.AgentSpawnedAt != nil && ticket.AgentType != "" && agentName != ticket.AgentType
+
+	agentType := agentCommandType(agentCfg)
+
+	// A port kept from an earlier run may since have been taken by
+	// something else on the host.
+	agentPort := ticket.AgentPort
+	if agentType == "opencode" && (agentPort == 0 || !agent.PortFree(agentPort)) {
+		ticket.AgentPort = 0
+		port, err := m.allocateAgentPort()
+		if err != nil {
+			m.saveTicket(ticket)
+			return func() tea.Msg {
+				return spawnErrorMsg{ticketID: ticketID, err: "port allocation failed: " + err.Error()}
+			}
+		}
+		agentPort = port
+		ticket.AgentPort = agentPort
+		m.saveTicket(ticket)
+	}
+
+	// A prompt confirmed in the spawn preview is sent as it was edited.
+	confirmedPrompt, hasConfirmedPrompt := m.spawnPrompts[ticketID]
+	delete(m.spawnPrompts, ticketID)
+
+	mgr := m.worktreeMgrs[proj.ID]
+	cfg := m.config
+	agentMgr := m.agentMgr
+
+	return func() tea.Msg {
+		if mgr == nil {
+			return spawnErrorMsg{ticketID: ticketID, err: "worktree manager not found"}
+		}
+		if agentType == "opencode" {
+			_ = agentMgr.EnsureOpencodeServer() // Best effort; the header shows its health
+		}
+
+		generatedBranch := branchName
+		if generatedBranch == "" {
+			generatedBranch = m.generateBranchNameFromTitle(ticket.Title, ticket.Number, proj)
+		}
+
+		base, _ := mgr.GetDefaultBranch()
+		if baseBranch != "" {
+			base = baseBranch
+		}
+
+		if useWorktree {
+			if worktreePath == "" {
+				path, err := mgr.CreateWorktree(generatedBranch, base)
+				if err != nil {
+					return spawnErrorMsg{ticketID: ticketID, err: "worktree failed: " + err.Error()}
+				}
+				worktreePath = path
+			}
+		} else {
+			if err := mgr.SetupBranch(generatedBranch, base); err != nil {
+				return spawnErrorMsg{ticketID: ticketID, err: "branch setup failed: " + err.Error()}
+			}
+			worktreePath = proj.RepoPath
+		}
+		branchName = generatedBranch
+		baseBranch = base
+
+		pane := terminal.New(string(ticketID), width, height, scrollback)
+		pane.SetWorkdir(worktreePath)
+		// Ticket env.* meta entries override the agent's env.
+		env := maps.Clone(agentCfg.Env)
+		if ticketEnv := ticket.AgentEnv(); ticketEnv != nil {
+			if env == nil {
+				env = map[string]string{}
+			}
+			maps.Copy(env, ticketEnv)
+		}
+		pane.SetEnv(env)
+		if logOutput {
+			pane.SetLogFile(agent.AgentLogPath(string(ticketID)))
+			pane.SetLogTimestamps(logTimestamps)
+		}
+
+		// Set session name for terminal identification (priority: AgentSessionID > branch > ticket)
+		sessionName := string(ticketID)
+		if branchName != "" {
+			sessionName = branchName
+		}
+		if ticket.AgentSessionID != "" && !switching {
+			sessionName = ticket.AgentSessionID
+		}
+		pane.SetSessionName(sessionName)
+
+		// Clean up any stale status file from previous sessions that may not have
+		// been properly cleaned up (e.g., if the app was closed while an agent was running)
+		agent.CleanupStatusFile(sessionName)
+
+		isNewSession := ticket.AgentSpawnedAt == nil || switching
+		argValues := agent.ArgValues{
+			Worktree: worktreePath,
+			Branch:   branchName,
+			Title:    ticket.Title,
+			Port:     agentPort,
+		}
+		configArgs := agentCfg.Args
+		// Older configs carry empty opencode args; the port is required
+		// for status polling, so fall back to the built-in template.
+		if len(configArgs) == 0 && agentType == "opencode" {
+			configArgs = config.DefaultAgentArgs("opencode")
+		}
+		args := agent.ExpandArgs(configArgs, argValues)
+		args = append(args, agent.ExpandArgs(ticket.AgentArgs, argValues)...)
+		if agentType == "opencode" {
+			args = agent.OpencodeArgs(args, worktreePath, agentPort)
+		}
+
+		promptTemplate := cfg.GetEffectiveInitPrompt(agentType)
+		buildPrompt := func() string {
+			if hasConfirmedPrompt {
+				return confirmedPrompt
+			}
+			return agent.BuildContextPrompt(promptTemplate, ticket)
+		}
+
+		switch agentType {
+		case "claude":
+			if isNewSession && promptTemplate != "" {
+				prompt := buildPrompt()
+				if prompt != "" {
+					args = append(args, prompt)
+				}
+			} else if !isNewSession {
+				hasFlag := false
+				for _, arg := range args {
+					if arg == "--continue" || arg == "-c" {
+						hasFlag = true
+						break
+					}
+				}
+				if !hasFlag {
+					args = append(args, "--continue")
+				}
+			}
+		case "opencode":
+			command := agentCfg.Command
+			sessionID := agent.FindOpencodeSession(worktreePath)
+
+			if isNewSession {
+				if promptTemplate != "" {
+					prompt := buildPrompt()
+					if prompt != "" {
+						args = append(args, "--prompt", prompt)
+					}
+				}
+			} else if sessionID != "" {
+				args = append(args, "--session", sessionID)
+			} else {
+				args = append(args, "--continue")
+			}
+			return spawnReadyMsg{
+				ticketID:     ticketID,
+				pane:         pane,
+				command:      command,
+				args:         args,
+				worktreePath: worktreePath,
+				branchName:   branchName,
+				baseBranch:   baseBranch,
+			}
+		case "gemini":
+			command := agentCfg.Command
+			if !isNewSession {
+				sessionID := agent.FindGeminiSession(worktreePath)
+				if sessionID != "" {
+					args = append(args, "--resume")
+				}
+			} else if promptTemplate != "" {
+				prompt := buildPrompt()
+				if prompt != "" {
+					args = append(args, "-i", prompt)
+				}
+			}
+			return spawnReadyMsg{
+				ticketID:     ticketID,
+				pane:         pane,
+				command:      command,
+				args:         args,
+				worktreePath: worktreePath,
+				branchName:   branchName,
+				baseBranch:   baseBranch,
+			}
+		case "codex":
+			command := agentCfg.Command
+			if !isNewSession {
+				sessionID := agent.FindCodexSession(worktreePath)
+				if sessionID != "" {
+					resume := []string{"resume", sessionID}
+					if sessionID == "last" {
+						resume = []string{"resume", "--last"}
+					}
+					args = append(resume, args...)
+				}
+			} else if promptTemplate != "" {
+				prompt := buildPrompt()
+				if prompt != "" {
+					args = append(args, prompt)
+				}
+			}
+			return spawnReadyMsg{
+				ticketID:     ticketID,
+				pane:         pane,
+				command:      command,
+				args:         args,
+				worktreePath: worktreePath,
+				branchName:   branchName,
+				baseBranch:   baseBranch,
+			}
+		case "qwen":
+			if !isNewSession {
+				if agent.FindQwenSession(worktreePath) != "" {
+					args = append(args, "--continue")
+				}
+			} else if promptTemplate != "" {
+				prompt := buildPrompt()
+				if prompt != "" {
+					args = append(args, "-i", prompt)
+				}
+			}
+		case "cursor-agent":
+			if !isNewSession {
+				if agent.FindCursorSession(worktreePath) != "" {
+					args = append([]string{"resume"}, args...)
+				}
+			} else if promptTemplate != "" {
+				prompt := buildPrompt()
+				if prompt != "" {
+					args = append(args, prompt)
+				}
+			}
+		}
+
+		return spawnReadyMsg{
+			ticketID:     ticketID,
+			pane:         pane,
+			command:      agentCfg.Command,
+			args:         args,
+			worktreePath: worktreePath,
+			branchName:   branchName,
+			baseBranch:   baseBranch,
+		}
+	}
+}
+
+func (m *Model) stopAgent() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+
+	if m.unqueueSpawn(ticket.ID) {
+		delete(m.spawnPrompts, ticket.ID)
+		m.notify("Removed from spawn queue")
+		return m, nil
+	}
+
+	if pane, ok := m.panes[ticket.ID]; ok {
+		pane.Stop()
+		delete(m.panes, ticket.ID)
+	}
+
+	ticket.AgentStatus = board.AgentNone
+	m.saveTicket(ticket)
+	m.notify("Agent stopped")
+	return m, m.startQueuedSpawns()
+}
+
+// bumpPriority moves the selected ticket's priority by delta within 1-5,
+// where a negative delta raises it.
+func (m *Model) bumpPriority(delta int) (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+
+	current := ticket.Priority
+	if current <= 0 {
+		current = board.DefaultPriority
+	}
+	priority := min(max(current+delta, 1), 5)
+	if priority == ticket.Priority {
+		return m, nil
+	}
+
+	ticket.Priority = priority
+	ticket.Touch()
+	m.saveTicket(ticket)
+	m.refreshColumnTickets()
+	m.selectTicketByID(ticket.ID)
+	m.notify(fmt.Sprintf("Priority: P%d", priority))
+	return m, nil
+}
+
+// reorderTicket swaps the selected ticket with its neighbor delta rows away.
+// Priority still decides the order between buckets, so only tickets of the
+// same priority and status can trade places.
+func (m *Model) reorderTicket(delta int) (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
+	}
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+	tickets := m.columnTickets[m.activeColumn]
+	target := m.activeTicket + delta
+	if target < 0 || target >= len(tickets) {
+		return m, nil
+	}
+	neighbor := tickets[target]
+	if neighbor.EffectivePriority() != ticket.EffectivePriority() || neighbor.Status != ticket.Status {
+		m.notify("Can't reorder past a ticket with a different priority or status")
+		return m, nil
+	}
+
+	// Renumber the whole bucket, including tickets hidden by a filter or
+	// search, moving the ticket just past its displayed neighbor. Hidden
+	// tickets keep their places relative to each other.
+	var bucket []*board.Ticket
+	for _, t := range m.globalStore.GetByStatus(ticket.Status) {
+		if t.EffectivePriority() == ticket.EffectivePriority() {
+			bucket = append(bucket, t)
 		}
 	}
+	board.SortTickets(bucket)
+	i := slices.Index(bucket, ticket)
+	bucket = slices.Delete(bucket, i, i+1)
+	j := slices.Index(bucket, neighbor)
+	if delta > 0 {
+		j++
+	}
+	bucket = slices.Insert(bucket, j, ticket)
+	for i, t := range bucket {
+		t.Order = i + 1
+	}
 
-	if allSelected || len(m.filterProjectIDs) == 0 {
-		m.filterProjectIDs = make(map[string]bool)
-		for _, p := range projects {
-			m.filterProjectIDs[p.ID] = true
-		}
-		m.notify("All projects selected")
-	} else {
-		m.filterProjectIDs = make(map[string]bool)
-		m.notify("All projects deselected")
+	if err := m.globalStore.SaveAll(); err != nil {
+		m.notify("Failed to save: " + err.Error())
 	}
-	m.filterQuery = ""
 	m.refreshColumnTickets()
+	m.selectTicketByID(ticket.ID)
+	return m, nil
 }
 
-func (m *Model) moveColumn(delta int) {
-	m.activeColumn += delta
-	m.activeColumn = max(m.activeColumn, 0)
-	if m.activeColumn >= len(m.columns) {
-		m.activeColumn = len(m.columns) - 1
+// openAgentPicker lists the configured agents for the selected ticket. Before
+// the first spawn the pick becomes the ticket's agent; afterwards it is kept
+// as a pending override that the next spawn starts as a new session.
+func (m *Model) openAgentPicker() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
 	}
-	m.activeTicket = 0
-	m.ensureColumnVisible()
-	m.ensureTicketVisible()
-}
-
-func (m *Model) ensureColumnVisible() {
-	colWidth := m.calcColumnWidth()
-	visibleCols := m.visibleColumnCount(colWidth)
-
-	if m.activeColumn < m.scrollOffset {
-		m.scrollOffset = m.activeColumn
-	} else if m.activeColumn >= m.scrollOffset+visibleCols {
-		m.scrollOffset = m.activeColumn - visibleCols + 1
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
 	}
-
-	maxOffset := max(len(m.columns)-visibleCols, 0)
-	if m.scrollOffset > maxOffset {
-		m.scrollOffset = maxOffset
+	if _, exists := m.panes[ticket.ID]; exists {
+		m.notify("Stop the agent before switching agent type")
+		return m, nil
 	}
-}
 
-func (m *Model) headerHeight() int {
-	const (
-		content      = 1
-		borderBottom = 1
-		spacing      = 2
-	)
-	return content + borderBottom + spacing
+	current := ticket.NextAgentType()
+	if current == "" {
+		current = m.getDefaultAgent()
+	}
+	m.agentPickerIndex = max(slices.Index(m.getAgentNames(), current), 0)
+	m.sidebarFocused = false
+	m.mode = ModeAgentPicker
+	return m, nil
 }
 
-func (m *Model) calcColumnWidth() int {
-	boardW := m.boardWidth()
-	if boardW == 0 || len(m.columns) == 0 {
-		return minColumnWidth
+func (m *Model) handleAgentPickerMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	agents := m.getAgentNames()
+	switch msg.String() {
+	case "j", "down":
+		if m.agentPickerIndex < len(agents)-1 {
+			m.agentPickerIndex++
+		}
+	case "k", "up":
+		if m.agentPickerIndex > 0 {
+			m.agentPickerIndex--
+		}
+	case "enter":
+		m.mode = ModeNormal
+		if ticket := m.selectedTicket(); ticket != nil && m.agentPickerIndex < len(agents) {
+			m.setTicketAgent(ticket, agents[m.agentPickerIndex])
+		}
+	case "q", "a":
+		m.mode = ModeNormal
 	}
-
-	numCols := len(m.columns)
-	totalOverhead := numCols * columnOverhead
-	colWidth := (boardW - totalOverhead) / numCols
-
-	return max(colWidth, minColumnWidth)
+	return m, nil
 }
 
-func (m *Model) visibleColumnCount(colWidth int) int {
-	boardW := m.boardWidth()
-	if boardW == 0 {
-		return len(m.columns)
-	}
-	visible := boardW / (colWidth + columnOverhead)
-	visible = max(visible, 1)
-	if visible > len(m.columns) {
-		visible = len(m.columns)
+// setTicketAgent makes name the agent for ticket's next spawn. A ticket that
+// never spawned simply changes agent; otherwise name is stored as the
+// ticket's AgentOverride, or the override is dropped when name is the agent
+// it already runs.
+func (m *Model) setTicketAgent(ticket *board.Ticket, name string) {
+	switch {
+	case ticket.AgentSpawnedAt == nil:
+		ticket.AgentType = name
+		ticket.AgentOverride = ""
+		m.notify("Agent: " + name)
+	case name == ticket.AgentType:
+		ticket.AgentOverride = ""
+		m.notify("Agent: " + name + " (switch cancelled)")
+	default:
+		ticket.AgentOverride = name
+		m.notify("Next spawn switches to " + name + " with a new session")
 	}
-	return visible
+	m.saveTicket(ticket)
 }
 
-func (m *Model) distributeWidth(numCols int) (baseWidth, remainder int) {
-	boardW := m.boardWidth()
-	if numCols == 0 || boardW == 0 {
-		return minColumnWidth, 0
+// archiveTicket moves the selected ticket to the archive, keeping its
+// worktree and branch. Archived tickets are hidden unless shown in settings.
+func (m *Model) archiveTicket() (tea.Model, tea.Cmd) {
+	if m.denyReadOnly() {
+		return m, nil
 	}
-	borders := numCols * 2
-	margins := numCols - 1
-	available := boardW - borders - margins
-	baseWidth = available / numCols
-	remainder = available % numCols
-	if baseWidth < minColumnWidth {
-		baseWidth = minColumnWidth
-		remainder = 0
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
 	}
-	return baseWidth, remainder
-}
-
-func (m *Model) moveTicket(delta int) {
-	if len(m.columnTickets) <= m.activeColumn {
-		return
+	if ticket.Status == board.StatusArchived {
+		return m.restoreTicket(ticket)
 	}
-	tickets := m.columnTickets[m.activeColumn]
-	m.activeTicket += delta
-	m.activeTicket = max(m.activeTicket, 0)
-	if m.activeTicket >= len(tickets) {
-		m.activeTicket = max(len(tickets)-1, 0)
+	if pane, ok := m.panes[ticket.ID]; ok && pane.Running() {
+		m.notify("Stop the agent before archiving")
+		return m, nil
 	}
-	m.ensureTicketVisible()
-}
 
-func (m *Model) visibleTicketCount() int {
-	availableHeight := m.columnContentHeight()
-	if availableHeight <= 0 {
-		return 1
+	m.globalStore.Move(ticket.ID, board.StatusArchived)
+	m.refreshColumnTickets()
+	if m.config.UI.ShowArchived {
+		m.selectTicketByID(ticket.ID)
+	} else if m.activeColumn < len(m.columnTickets) {
+		m.activeTicket = min(m.activeTicket, max(len(m.columnTickets[m.activeColumn])-1, 0))
 	}
-	count := availableHeight / ticketHeight
-	return max(count, 1)
+	m.saveTicket(ticket)
+	m.notify("Archived: " + ticket.Title)
+	return m, nil
 }
 
-func (m *Model) columnContentHeight() int {
-	boardHeight := m.height - 4
-	contentHeight := boardHeight - columnHeaderHeight - 4
-	return contentHeight
+// restoreTicket moves an archived ticket back to Done.
+func (m *Model) restoreTicket(ticket *board.Ticket) (tea.Model, tea.Cmd) {
+	if msg, full := m.wipLimitReached(ticket, board.StatusDone); full {
+		m.notify(msg)
+		return m, nil
+	}
+	m.globalStore.Move(ticket.ID, board.StatusDone)
+	m.refreshColumnTickets()
+	m.selectTicketByID(ticket.ID)
+	m.saveTicket(ticket)
+	m.notify("Restored: " + ticket.Title)
+	return m, nil
 }
 
-func (m *Model) ensureTicketVisible() {
-	if m.activeColumn < 0 || m.activeColumn >= len(m.columnOffsets) {
-		return
+// togglePaneOutput pauses or resumes output processing for the selected
+// ticket's agent. Attaching always resumes it.
+func (m *Model) togglePaneOutput() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
 	}
 
-	offset := m.columnOffsets[m.activeColumn]
-	visible := m.visibleTicketCount()
-
-	if m.activeTicket < offset {
-		m.columnOffsets[m.activeColumn] = m.activeTicket
-	} else if m.activeTicket >= offset+visible {
-		m.columnOffsets[m.activeColumn] = m.activeTicket - visible + 1
+	pane, ok := m.panes[ticket.ID]
+	if !ok || !pane.Running() {
+		m.notify("No agent running")
+		return m, nil
 	}
 
-	m.columnOffsets[m.activeColumn] = max(m.columnOffsets[m.activeColumn], 0)
+	if pane.Paused() {
+		pane.Resume()
+		m.notify("Output resumed")
+	} else {
+		pane.Pause()
+		m.notify("Output paused — attach to resume")
+	}
+	return m, nil
 }
 
-func (m *Model) createNewTicket() (tea.Model, tea.Cmd) {
-	m.mode = ModeCreateTicket
-	m.ticketFormField = formFieldTitle
-	m.editingTicketID = ""
-	m.branchLocked = false
-	m.agentLocked = false
-	m.showAddProjectForm = false
-
-	if len(m.filterProjectIDs) == 1 {
-		for id := range m.filterProjectIDs {
-			m.selectedProject = m.globalStore.GetProject(id)
-			break
-		}
-	} else if m.selectedProject == nil {
-		projects := m.globalStore.Projects()
-		if len(projects) > 0 {
-			m.selectedProject = projects[0]
-		}
+func (m *Model) selectedTicket() *board.Ticket {
+	if len(m.columnTickets) <= m.activeColumn {
+		return nil
+	}
+	tickets := m.columnTickets[m.activeColumn]
+	if len(tickets) <= m.activeTicket {
+		return nil
 	}
+	return tickets[m.activeTicket]
+}
 
-	m.projectListIndex = 0
-	if m.selectedProject != nil {
-		for i, p := range m.globalStore.Projects() {
-			if p.ID == m.selectedProject.ID {
-				m.projectListIndex = i
-				break
+func (m *Model) selectTicketByID(ticketID board.TicketID) {
+	for colIdx, tickets := range m.columnTickets {
+		for ticketIdx, t := range tickets {
+			if t.ID == ticketID {
+				m.activeColumn = colIdx
+				m.activeTicket = ticketIdx
+				m.ensureTicketVisible()
+				return
 			}
 		}
 	}
-
-	m.ticketAgent = m.getDefaultAgent()
-	m.agentListIndex = m.getAgentIndex(m.ticketAgent)
-
-	m.titleInput.Reset()
-	m.descInput.Reset()
-	m.branchInput.Reset()
-	m.labelsInput.Reset()
-	m.ticketPriority = 3
-	m.ticketUseWorktree = true
-
-	m.initBlockerCandidates("")
-	m.selectedBlockers = make(map[board.TicketID]bool)
-	m.blockerListIndex = 0
-	m.blockerFilterInput.Reset()
-	m.formScrollOffset = 0
-
-	m.blurAllFormFields()
-	m.titleInput.Focus()
-	return m, m.titleInput.Cursor.BlinkCmd()
 }
 
-func (m *Model) editTicket() (tea.Model, tea.Cmd) {
-	ticket := m.selectedTicket()
-	if ticket == nil {
-		m.notify("No ticket selected")
-		return m, nil
+func (m *Model) refreshColumnTickets() {
+	m.statusColumns = m.boardStatusColumns()
+	if m.groupByProject {
+		m.refreshProjectColumns()
+	} else {
+		m.refreshStatusColumns()
 	}
 
-	m.mode = ModeEditTicket
-	m.ticketFormField = formFieldTitle
-	m.editingTicketID = ticket.ID
-	m.branchLocked = ticket.WorktreePath != ""
-	m.agentLocked = ticket.AgentSpawnedAt != nil
-	m.selectedProject = m.globalStore.GetProjectForTicket(ticket)
-	m.titleInput.SetValue(ticket.Title)
-	m.descInput.SetValue(ticket.Description)
-	if ticket.BranchName != "" {
-		m.branchInput.SetValue(ticket.BranchName)
-	} else if m.selectedProject != nil {
-		m.branchInput.SetValue(m.generateBranchNameFromTitle(ticket.Title, m.selectedProject))
+	if len(m.columnOffsets) != len(m.columns) {
+		m.columnOffsets = make([]int, len(m.columns))
 	}
-	m.labelsInput.SetValue(strings.Join(ticket.Labels, ", "))
-	m.ticketPriority = ticket.Priority
-	if m.ticketPriority < 1 || m.ticketPriority > 5 {
-		m.ticketPriority = 3
+	if m.activeColumn >= len(m.columns) {
+		m.activeColumn = max(len(m.columns)-1, 0)
 	}
-	m.ticketUseWorktree = ticket.UseWorktree
-	if ticket.AgentType != "" {
-		m.ticketAgent = ticket.AgentType
-	} else {
-		m.ticketAgent = m.getDefaultAgent()
+}
+
+func (m *Model) refreshStatusColumns() {
+	m.columns = m.statusColumns
+	m.columnTickets = make([][]*board.Ticket, len(m.columns))
+	m.columnWIP = make([]wipUsage, len(m.columns))
+	for i, col := range m.columns {
+		m.columnWIP[i] = m.statusWIP(col.Status)
+		allForStatus := m.globalStore.GetByStatus(col.Status)
+		var filtered []*board.Ticket
+		scores := make(map[board.TicketID]int)
+		for _, t := range allForStatus {
+			score, ok := m.filterScore(t)
+			if !ok {
+				continue
+			}
+			filtered = append(filtered, t)
+			scores[t.ID] = score
+		}
+		board.SortTickets(filtered)
+		// Searching ranks the best matches first; equal scores keep the
+		// usual order.
+		sort.SliceStable(filtered, func(a, b int) bool {
+			return scores[filtered[a].ID] > scores[filtered[b].ID]
+		})
+		m.columnTickets[i] = filtered
 	}
-	m.agentListIndex = m.getAgentIndex(m.ticketAgent)
+}
 
-	m.initBlockerCandidates(ticket.ID)
-	m.selectedBlockers = make(map[board.TicketID]bool)
-	for _, blockerID := range ticket.BlockedBy {
-		m.selectedBlockers[blockerID] = true
+// refreshProjectColumns lays the board out with one column per project,
+// tickets ordered by their position in the status columns.
+func (m *Model) refreshProjectColumns() {
+	m.columnWIP = nil
+	statusRank := make(map[board.TicketStatus]int, len(m.statusColumns))
+	for i, col := range m.statusColumns {
+		statusRank[col.Status] = i
 	}
-	m.blockerListIndex = 0
-	m.blockerFilterInput.Reset()
-	m.formScrollOffset = 0
 
-	m.blurAllFormFields()
-	m.titleInput.Focus()
-	return m, m.titleInput.Cursor.BlinkCmd()
-}
+	var columns []board.Column
+	columnIndex := make(map[string]int)
+	if !m.filterOrphaned {
+		for _, p := range m.globalStore.Projects() {
+			if len(m.filterProjectIDs) > 0 && !m.filterProjectIDs[p.ID] {
+				continue
+			}
+			columnIndex[p.ID] = len(columns)
+			columns = append(columns, board.Column{ID: p.ID, Name: p.Name})
+		}
+	}
 
-func (m *Model) attachToAgent() (tea.Model, tea.Cmd) {
-	ticket := m.selectedTicket()
-	if ticket == nil {
-		m.notify("No ticket selected")
-		return m, nil
+	tickets := make([][]*board.Ticket, len(columns))
+	var orphans []*board.Ticket
+	for _, t := range m.globalStore.All() {
+		if _, shown := statusRank[t.Status]; !shown || !m.ticketMatchesFilter(t) {
+			continue
+		}
+		if idx, ok := columnIndex[t.ProjectID]; ok {
+			tickets[idx] = append(tickets[idx], t)
+		} else if m.globalStore.IsOrphaned(t) {
+			orphans = append(orphans, t)
+		}
+	}
+	if len(orphans) > 0 {
+		columns = append(columns, board.Column{ID: orphanedColumnID, Name: "Orphaned"})
+		tickets = append(tickets, orphans)
 	}
 
-	pane, ok := m.panes[ticket.ID]
-	if !ok || !pane.Running() {
-		m.notify("No agent running — press 's' to spawn")
-		return m, nil
+	for _, column := range tickets {
+		board.SortTickets(column)
+		sort.SliceStable(column, func(i, j int) bool {
+			return statusRank[column[i].Status] < statusRank[column[j].Status]
+		})
 	}
 
-	m.mode = ModeAgentView
-	m.focusedPane = ticket.ID
-	paneHeight := m.height - 2
-	pane.SetSize(m.width, paneHeight)
-	return m, nil
+	m.columns = columns
+	m.columnTickets = tickets
 }
 
-func (m *Model) handleDoubleClick() (tea.Model, tea.Cmd) {
+// toggleTicketMark marks or unmarks the selected ticket for a batch action.
+func (m *Model) toggleTicketMark() {
 	ticket := m.selectedTicket()
 	if ticket == nil {
-		return m, nil
+		return
+	}
+	if m.selectedTickets[ticket.ID] {
+		delete(m.selectedTickets, ticket.ID)
+	} else {
+		m.selectedTickets[ticket.ID] = true
+	}
+	if len(m.selectedTickets) == 0 {
+		m.notify("Selection cleared")
+		return
 	}
+	m.notify(fmt.Sprintf("%d selected — Esc to clear", len(m.selectedTickets)))
+}
 
-	pane, ok := m.panes[ticket.ID]
-	if ok && pane.Running() {
-		return m.attachToAgent()
+// markedTickets returns the marked tickets still on the board, in board
+// order. Marks on tickets that were deleted or filtered out are ignored.
+func (m *Model) markedTickets() []*board.Ticket {
+	var marked []*board.Ticket
+	for _, column := range m.columnTickets {
+		for _, t := range column {
+			if m.selectedTickets[t.ID] {
+				marked = append(marked, t)
+			}
+		}
 	}
+	return marked
+}
 
-	return m.spawnAgent()
+func (m *Model) clearTicketMarks() {
+	clear(m.selectedTickets)
 }
 
-func (m *Model) confirmDeleteTicket() (tea.Model, tea.Cmd) {
-	ticket := m.selectedTicket()
-	if ticket == nil {
-		return m, nil
+func (m *Model) toggleGroupByProject() {
+	selected := m.selectedTicket()
+	m.groupByProject = !m.groupByProject
+	m.activeColumn = 0
+	m.activeTicket = 0
+	m.scrollOffset = 0
+	m.columnOffsets = nil
+	m.refreshColumnTickets()
+	if selected != nil {
+		m.selectTicketByID(selected.ID)
+	}
+	m.ensureColumnVisible()
+	if m.groupByProject {
+		m.notify("Grouped by project")
+	} else {
+		m.notify("Grouped by status")
 	}
+}
 
-	proj := m.globalStore.GetProjectForTicket(ticket)
-	hasUncommitted := false
-	if ticket.WorktreePath != "" && m.config.Cleanup.DeleteWorktree && proj != nil {
-		if mgr := m.worktreeMgrs[proj.ID]; mgr != nil {
-			var err error
-			hasUncommitted, err = mgr.HasUncommittedChanges(ticket.WorktreePath)
-			if err != nil {
-				hasUncommitted = false
+// boardStatusColumns returns the status columns for the current filter. A
+// single filtered project shows its own columns; otherwise the defaults are
+// merged with every visible project's custom columns so no ticket is hidden.
+func (m *Model) boardStatusColumns() []board.Column {
+	sets := [][]board.Column{board.DefaultColumns()}
+	if !m.filterOrphaned {
+		for _, p := range m.globalStore.Projects() {
+			if len(m.filterProjectIDs) > 0 && !m.filterProjectIDs[p.ID] {
+				continue
 			}
+			sets = append(sets, p.GetColumns())
 		}
 	}
 
-	if hasUncommitted && !m.config.Cleanup.ForceWorktreeRemoval {
-		m.showConfirm = true
-		m.confirmMsg = "Worktree has uncommitted changes. Force delete?"
-		m.confirmFn = func() tea.Cmd {
-			m.performTicketCleanup(ticket)
-			return nil
-		}
+	var columns []board.Column
+	if len(m.filterProjectIDs) == 1 && len(sets) == 2 {
+		columns = append(columns, sets[1]...)
 	} else {
-		m.showConfirm = true
-		m.confirmMsg = "Delete ticket: " + ticket.Title + "?"
-		m.confirmFn = func() tea.Cmd {
-			m.performTicketCleanup(ticket)
-			return nil
-		}
+		columns = board.MergeColumns(sets...)
 	}
-	return m, nil
+	if m.config.UI.ShowArchived {
+		columns = append(columns, board.ArchivedColumn())
+	}
+	return columns
 }
 
-func (m *Model) performTicketCleanup(ticket *board.Ticket) {
-	ticketTitle := ticket.Title // Capture before deletion
-
-	if pane, ok := m.panes[ticket.ID]; ok {
-		pane.Stop()
-		delete(m.panes, ticket.ID)
+// setShowArchived adds or removes the Archived column and persists the
+// choice, except in read-only mode.
+func (m *Model) setShowArchived(show bool) {
+	selected := m.selectedTicket()
+	m.config.UI.ShowArchived = show
+	if !m.readOnly {
+		m.config.Save("")
 	}
-
-	proj := m.globalStore.GetProjectForTicket(ticket)
-	if proj != nil {
-		mgr := m.worktreeMgrs[proj.ID]
-		if mgr != nil {
-			if ticket.WorktreePath != "" && m.config.Cleanup.DeleteWorktree {
-				err := mgr.RemoveWorktree(ticket.WorktreePath)
-				if err != nil {
-					m.notify("Failed to remove worktree: " + err.Error())
-				}
-			}
-
-			if ticket.BranchName != "" && m.config.Cleanup.DeleteBranch {
-				err := mgr.DeleteBranch(ticket.BranchName)
-				if err != nil {
-					m.notify("Failed to delete branch: " + err.Error())
-				}
-			}
-		}
+	m.refreshColumnTickets()
+	if selected != nil {
+		m.selectTicketByID(selected.ID)
 	}
+	m.ensureColumnVisible()
+}
 
-	m.globalStore.RemoveBlockerReferences(ticket.ID)
-	m.globalStore.Delete(ticket.ID)
+// setShowSnoozed shows or hides snoozed tickets and persists the choice,
+// except in read-only mode.
+func (m *Model) setShowSnoozed(show bool) {
+	selected := m.selectedTicket()
+	m.config.UI.ShowSnoozed = show
+	if !m.readOnly {
+		m.config.Save("")
+	}
 	m.refreshColumnTickets()
-	m.globalStore.SaveAll()
-	m.notify("Deleted: " + ticketTitle)
+	if selected != nil {
+		m.selectTicketByID(selected.ID)
+	}
 }
 
-func (m *Model) quickMoveTicket() (tea.Model, tea.Cmd) {
-	ticket := m.selectedTicket()
-	if ticket == nil {
-		return m, nil
+// countsTowardBoard reports whether t is included in board and sidebar
+// counts; archived and snoozed tickets only count while they are shown.
+func (m *Model) countsTowardBoard(t *board.Ticket) bool {
+	if t.IsSnoozed(time.Now()) && !m.config.UI.ShowSnoozed {
+		return false
 	}
+	return t.Status != board.StatusArchived || m.config.UI.ShowArchived
+}
 
-	nextStatus := m.nextStatus(ticket.Status)
-	if nextStatus == ticket.Status {
-		return m, nil
+// snoozeTargets returns the marked tickets, or the selected ticket when
+// none are marked.
+func (m *Model) snoozeTargets() []*board.Ticket {
+	if marked := m.markedTickets(); len(marked) > 0 {
+		return marked
+	}
+	if ticket := m.selectedTicket(); ticket != nil {
+		return []*board.Ticket{ticket}
 	}
+	return nil
+}
 
-	if nextStatus == board.StatusInProgress && ticket.WorktreePath == "" {
-		if ticket.UseWorktree {
-			if err := m.setupWorktree(ticket); err != nil {
-				m.notify("Worktree failed: " + err.Error())
-				return m, nil
-			}
-		} else {
-			if err := m.setupMainRepoBranch(ticket); err != nil {
-				m.notify("Branch setup failed: " + err.Error())
-				return m, nil
-			}
-		}
+// commandSnooze handles ":snooze <when>", hiding the marked or selected
+// tickets until then.
+func (m *Model) commandSnooze(args []string) {
+	if m.denyReadOnly() {
+		return
+	}
+	tickets := m.snoozeTargets()
+	if len(tickets) == 0 {
+		m.notify("No ticket selected")
+		return
+	}
+	if len(args) == 0 {
+		m.notify("Error: usage: snooze <+3d|YYYY-MM-DD|YYYY-MM-DD HH:MM>")
+		return
+	}
+	until, err := board.ParseSnoozeTime(strings.Join(args, " "), time.Now())
+	if err != nil {
+		m.notify("Error: " + err.Error())
+		return
 	}
 
-	m.globalStore.Move(ticket.ID, nextStatus)
+	for _, t := range tickets {
+		t.SnoozedUntil = &until
+		t.Touch()
+		m.saveTicket(t)
+	}
+	m.clearTicketMarks()
 	m.refreshColumnTickets()
-	m.selectTicketByID(ticket.ID)
-	m.saveTicket(ticket)
-	m.notify("Moved to " + string(nextStatus))
-
-	return m, nil
+	if m.activeColumn < len(m.columnTickets) {
+		m.activeTicket = min(m.activeTicket, max(len(m.columnTickets[m.activeColumn])-1, 0))
+	}
+	m.notify(fmt.Sprintf("Snoozed %d ticket(s) until %s", len(tickets), until.Format("Jan 2 15:04")))
 }
 
-func (m *Model) quickMoveTicketBackward() (tea.Model, tea.Cmd) {
-	ticket := m.selectedTicket()
-	if ticket == nil {
-		return m, nil
+// unsnoozeTickets brings the marked or selected snoozed tickets back now.
+func (m *Model) unsnoozeTickets() {
+	if m.denyReadOnly() {
+		return
 	}
-
-	prevStatus := m.previousStatus(ticket.Status)
-	if prevStatus == ticket.Status {
-		return m, nil
+	woken := 0
+	for _, t := range m.snoozeTargets() {
+		if t.SnoozedUntil == nil {
+			continue
+		}
+		t.SnoozedUntil = nil
+		t.Touch()
+		m.saveTicket(t)
+		woken++
+	}
+	if woken == 0 {
+		m.notify("No snoozed ticket selected")
+		return
 	}
-
-	m.globalStore.Move(ticket.ID, prevStatus)
 	m.refreshColumnTickets()
-	m.selectTicketByID(ticket.ID)
-	m.saveTicket(ticket)
-	m.notify("Moved to " + string(prevStatus))
+	m.notify(fmt.Sprintf("Unsnoozed %d ticket(s)", woken))
+}
 
-	return m, nil
+// wakeSnoozedTickets returns tickets whose snooze has ended to the board,
+// announcing them. It runs on the status poll tick.
+func (m *Model) wakeSnoozedTickets() {
+	if m.readOnly {
+		return
+	}
+	now := time.Now()
+	var woken []string
+	for _, t := range m.globalStore.All() {
+		if t.SnoozedUntil == nil || t.IsSnoozed(now) {
+			continue
+		}
+		t.SnoozedUntil = nil
+		m.saveTicket(t)
+		woken = append(woken, t.Title)
+	}
+	if len(woken) == 0 {
+		return
+	}
+	sort.Strings(woken)
+	selected := m.selectedTicket()
+	m.refreshColumnTickets()
+	if selected != nil {
+		m.selectTicketByID(selected.ID)
+	}
+	m.notify("Back from snooze: " + strings.Join(woken, ", "))
 }
 
-func (m *Model) setupWorktree(ticket *board.Ticket) error {
-	proj := m.globalStore.GetProjectForTicket(ticket)
-	if proj == nil {
-		return fmt.Errorf("project not found for ticket")
+// boardTicketCount returns the number of tickets on the board, ignoring
+// archived ones unless they are shown.
+func (m *Model) boardTicketCount() int {
+	count := 0
+	for _, t := range m.globalStore.All() {
+		if m.countsTowardBoard(t) {
+			count++
+		}
 	}
+	return count
+}
 
-	mgr := m.worktreeMgrs[proj.ID]
-	if mgr == nil {
-		return fmt.Errorf("worktree manager not found")
+// statusColumnName returns the display name of the status column for status.
+func (m *Model) statusColumnName(status board.TicketStatus) string {
+	for _, col := range m.statusColumns {
+		if col.Status == status {
+			return col.Name
+		}
 	}
+	return string(status)
+}
 
-	branchName := m.generateBranchName(ticket, proj)
-	baseBranch, _ := mgr.GetDefaultBranch()
+func (m *Model) ticketMatchesFilter(t *board.Ticket) bool {
+	_, ok := m.filterScore(t)
+	return ok
+}
 
-	path, err := mgr.CreateWorktree(branchName, baseBranch)
-	if err != nil {
-		return err
+// filterScore reports whether t passes the board filters and, for the
+// filter's search text, how well it matches; higher scores match better.
+func (m *Model) filterScore(t *board.Ticket) (int, bool) {
+	if !m.config.UI.ShowSnoozed && t.IsSnoozed(time.Now()) {
+		return 0, false
+	}
+	if m.filterOrphaned && !m.globalStore.IsOrphaned(t) {
+		return 0, false
+	}
+	if len(m.filterProjectIDs) > 0 && !m.filterProjectIDs[t.ProjectID] {
+		return 0, false
+	}
+	if m.savedFilter != nil && !m.savedFilter.Matches(t) {
+		return 0, false
+	}
+	if m.filterQuery == "" {
+		return 0, true
 	}
 
-	ticket.WorktreePath = path
-	ticket.BranchName = branchName
-	ticket.BaseBranch = baseBranch
-	return nil
-}
+	query, ok := matchDueFilter(strings.ToLower(m.filterQuery), t)
+	if !ok {
+		return 0, false
+	}
+	query, ok = matchLabelFilter(query, t)
+	if !ok {
+		return 0, false
+	}
+	if query == "" {
+		return 0, true
+	}
 
-func (m *Model) setupMainRepoBranch(ticket *board.Ticket) error {
-	proj := m.globalStore.GetProjectForTicket(ticket)
-	if proj == nil {
-		return fmt.Errorf("project not found for ticket")
+	if strings.HasPrefix(query, "@") {
+		parts := strings.SplitN(query, " ", 2)
+		projectName := strings.TrimPrefix(parts[0], "@")
+		proj := m.globalStore.GetProjectForTicket(t)
+		if proj == nil || !strings.Contains(strings.ToLower(proj.Name), projectName) {
+			return 0, false
+		}
+		if len(parts) == 1 {
+			return 0, true
+		}
+		query = strings.TrimSpace(parts[1])
 	}
 
-	mgr := m.worktreeMgrs[proj.ID]
-	if mgr == nil {
-		return fmt.Errorf("worktree manager not found")
+	// Each word may match the title or the description on its own, or be
+	// the ticket's short ID.
+	key := strings.ToLower(m.ticketKey(t))
+	total := 0
+	for _, term := range strings.Fields(query) {
+		if key != "" && term == key {
+			total += 100
+			continue
+		}
+		titleScore, inTitle := fuzzyMatch(term, t.Title)
+		descScore, inDesc := fuzzyMatch(term, t.Description)
+		if !inTitle && !inDesc {
+			return 0, false
+		}
+		// Title matches count double so they rank above description ones.
+		if inTitle {
+			titleScore *= 2
+		}
+		total += max(titleScore, descScore)
 	}
+	return total, true
+}
 
-	branchName := m.generateBranchName(ticket, proj)
-	baseBranch, _ := mgr.GetDefaultBranch()
+// fuzzyMatch reports whether the characters of query appear in target in
+// order, ignoring case, so "atln" matches "Authentication login". The score
+// favors consecutive characters, matches at word starts, and exact
+// substrings. An empty query matches everything with a score of 0.
+func fuzzyMatch(query, target string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(target))
+
+	score := 0
+	qi := 0
+	prevMatch := -2
+	for ti, r := range t {
+		if qi == len(q) {
+			break
+		}
+		if r != q[qi] {
+			continue
+		}
+		score++
+		if ti == prevMatch+1 {
+			score += 2
+		}
+		if ti == 0 || (!unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1])) {
+			score += 3
+		}
+		prevMatch = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	if strings.Contains(string(t), string(q)) {
+		score += 2 * len(q)
+	}
+	return score, true
+}
 
-	ticket.WorktreePath = proj.RepoPath
-	ticket.BranchName = branchName
-	ticket.BaseBranch = baseBranch
-	return nil
+// matchDueFilter strips due: tokens from query and reports whether t satisfies
+// them. due:overdue keeps tickets past their due date; due:soon keeps tickets
+// due within the next day.
+func matchDueFilter(query string, t *board.Ticket) (string, bool) {
+	if !strings.Contains(query, "due:") {
+		return query, true
+	}
+
+	now := time.Now()
+	var rest []string
+	for _, field := range strings.Fields(query) {
+		switch field {
+		case "due:overdue":
+			if !t.IsOverdue(now) {
+				return "", false
+			}
+		case "due:soon":
+			if !t.IsDueSoon(now) {
+				return "", false
+			}
+		default:
+			rest = append(rest, field)
+		}
+	}
+	return strings.Join(rest, " "), true
 }
 
-func (m *Model) generateBranchNameFromTitle(title string, proj *project.Project) string {
-	maxLen := m.getSlugMaxLength(proj)
-	slug := board.Slugify(title, maxLen)
+// matchLabelFilter strips #label tokens from query and reports whether t has
+// every one of those labels, ignoring case.
+func matchLabelFilter(query string, t *board.Ticket) (string, bool) {
+	if !strings.Contains(query, "#") {
+		return query, true
+	}
 
-	template := m.getBranchTemplate(proj)
-	prefix := m.getBranchPrefix(proj)
+	var rest []string
+	for _, field := range strings.Fields(query) {
+		label, ok := strings.CutPrefix(field, "#")
+		if !ok || label == "" {
+			rest = append(rest, field)
+			continue
+		}
+		if !slices.ContainsFunc(t.Labels, func(l string) bool { return strings.EqualFold(l, label) }) {
+			return "", false
+		}
+	}
+	return strings.Join(rest, " "), true
+}
 
-	result := strings.ReplaceAll(template, "{prefix}", prefix)
-	result = strings.ReplaceAll(result, "{slug}", slug)
+// ticketStatusOrder returns the statuses a ticket moves through: its
+// project's columns, then Archived.
+func (m *Model) ticketStatusOrder(ticket *board.Ticket) []board.TicketStatus {
+	columns := m.ticketColumns(ticket)
+	order := make([]board.TicketStatus, 0, len(columns))
+	for _, col := range columns {
+		order = append(order, col.Status)
+	}
+	return order
+}
 
-	return result
+// inWorkingColumn reports whether ticket is in one of its project's columns
+// from In Progress up to, but not including, Done, where agents work.
+func (m *Model) inWorkingColumn(ticket *board.Ticket) bool {
+	order := m.ticketStatusOrder(ticket)
+	pos := slices.Index(order, ticket.Status)
+	start := slices.Index(order, board.StatusInProgress)
+	end := slices.Index(order, board.StatusDone)
+	return pos >= 0 && start >= 0 && pos >= start && (end < 0 || pos < end)
 }
 
-func (m *Model) generateBranchName(ticket *board.Ticket, proj *project.Project) string {
-	if ticket.BranchName != "" {
-		return ticket.BranchName
+// ticketColumns returns the columns of the ticket's project, followed by
+// Archived.
+func (m *Model) ticketColumns(ticket *board.Ticket) []board.Column {
+	columns := board.DefaultColumns()
+	if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+		columns = proj.GetColumns()
 	}
-	return m.generateBranchNameFromTitle(ticket.Title, proj)
+	return append(slices.Clone(columns), board.ArchivedColumn())
 }
 
-func (m *Model) allocateAgentPort() int {
-	usedPorts := make(map[int]bool)
-	for _, t := range m.globalStore.All() {
-		if t.AgentPort > 0 {
-			usedPorts[t.AgentPort] = true
+func (m *Model) nextStatus(ticket *board.Ticket) board.TicketStatus {
+	order := m.ticketStatusOrder(ticket)
+	for i, status := range order {
+		if status == ticket.Status && i+1 < len(order) {
+			return order[i+1]
 		}
 	}
+	return ticket.Status
+}
 
-	port := agentPortBase
-	for usedPorts[port] {
-		port++
+func (m *Model) previousStatus(ticket *board.Ticket) board.TicketStatus {
+	order := m.ticketStatusOrder(ticket)
+	for i, status := range order {
+		if status == ticket.Status && i > 0 {
+			return order[i-1]
+		}
 	}
-	return port
+	return ticket.Status
 }
 
-func (m *Model) spawnAgent() (tea.Model, tea.Cmd) {
-	ticket := m.selectedTicket()
-	if ticket == nil {
-		return m, nil
-	}
+func (m *Model) notify(msg string) {
+	m.notification = msg
+	m.notifyTime = time.Now()
+}
 
-	if ticket.Status != board.StatusInProgress {
-		m.notify("Press Space to move to In Progress first")
-		return m, nil
+// denyReadOnly reports whether the model is read-only, telling the user so.
+// Actions that change anything call it first.
+func (m *Model) denyReadOnly() bool {
+	if m.readOnly {
+		m.notify("Read-only mode: changes are disabled")
 	}
+	return m.readOnly
+}
 
-	if _, exists := m.panes[ticket.ID]; exists {
-		m.notify("Agent already running — press Enter to attach")
-		return m, nil
+func (m *Model) saveTicket(ticket *board.Ticket) {
+	if err := m.globalStore.Save(ticket); err != nil {
+		m.notify("Failed to save: " + err.Error())
 	}
+}
 
-	proj := m.globalStore.GetProjectForTicket(ticket)
-	if proj == nil {
-		m.notify("Project not found for this ticket")
-		return m, nil
+func (m *Model) resetSpawnState(ticketID board.TicketID) {
+	if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
+		ticket.AgentSpawnedAt = nil
+		ticket.AgentStatus = board.AgentNone
+		m.saveTicket(ticket)
 	}
+	m.mode = ModeNormal
+	m.spawningTicketID = ""
+	m.spawningAgent = ""
+	delete(m.panes, ticketID)
+}
 
-	if !ticket.UseWorktree {
-		for otherID := range m.panes {
-			if otherID == ticket.ID {
-				continue
-			}
-			other, _ := m.globalStore.Get(otherID)
-			if other != nil && !other.UseWorktree {
-				otherProj := m.globalStore.GetProjectForTicket(other)
-				if otherProj != nil && otherProj.ID == proj.ID {
-					m.notify("Another main-repo agent is running in this project")
-					return m, nil
-				}
-			}
+func (m *Model) RunningAgentCount() int {
+	count := 0
+	for _, pane := range m.panes {
+		if pane.Running() {
+			count++
 		}
 	}
+	return count
+}
 
-	agentType := ticket.AgentType
-	if agentType == "" {
-		agentType = m.config.Defaults.DefaultAgent
+func (m *Model) getAgentNames() []string {
+	names := make([]string, 0, len(m.config.Agents))
+	for name := range m.config.Agents {
+		names = append(names, name)
 	}
-	agentCfg, ok := m.config.Agents[agentType]
-	if !ok {
-		m.notify("Agent '" + agentType + "' not configured")
-		return m, nil
+	if len(names) == 0 {
+		return []string{"opencode", "claude", "gemini", "codex", "aider", "qwen", "cursor-agent"}
 	}
+	sort.Strings(names)
+	return names
+}
 
-	// Start opencode server on-demand if spawning opencode agent
-	if agentType == "opencode" {
-		_ = m.opencodeServer.Start() // Best effort, ignore errors
+func (m *Model) getDefaultAgent() string {
+	return m.config.Defaults.DefaultAgent
+}
+
+func (m *Model) getBranchPrefix(proj *project.Project) string {
+	if proj != nil && proj.Settings.BranchPrefix != "" {
+		return proj.Settings.BranchPrefix
+	}
+	if m.config.Defaults.BranchPrefix != "" {
+		return m.config.Defaults.BranchPrefix
 	}
+	return "task/"
+}
 
-	m.mode = ModeSpawning
-	m.spawningTicketID = ticket.ID
-	m.spawningAgent = agentType
+func (m *Model) getBranchTemplate(proj *project.Project) string {
+	if proj != nil && proj.Settings.BranchTemplate != "" {
+		return proj.Settings.BranchTemplate
+	}
+	if m.config.Defaults.BranchTemplate != "" {
+		return m.config.Defaults.BranchTemplate
+	}
+	return "{prefix}{slug}"
+}
 
-	return m, tea.Batch(m.spinner.Tick, m.prepareSpawn(ticket, proj, agentCfg))
+// effectiveSetting is one resolved value shown in the effective config view,
+// with the setting it came from.
+type effectiveSetting struct {
+	name   string
+	value  string
+	source string
 }
 
-func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentCfg config.AgentConfig) tea.Cmd {
-	ticketID := ticket.ID
-	worktreePath := ticket.WorktreePath
-	branchName := ticket.BranchName
-	baseBranch := ticket.BaseBranch
-	useWorktree := ticket.UseWorktree
-	width, height := m.width, m.height-2
+// effectiveConfig is the resolved spawn configuration of a ticket, with the
+// init prompt a new session would be given.
+type effectiveConfig struct {
+	settings     []effectiveSetting
+	prompt       string
+	promptSource string
+}
 
-	agentType := agentCfg.Command
-	if strings.Contains(agentType, "/") {
-		agentType = filepath.Base(agentType)
+// effectiveSettings resolves what a spawn of ticket's agent would use, the
+// same way spawnAgent and prepareSpawn do, recording where each value came
+// from. It also returns the init prompt a new session would be given.
+func (m *Model) effectiveSettings(ticket *board.Ticket) (settings []effectiveSetting, prompt, promptSource string) {
+	add := func(name, value, source string) {
+		settings = append(settings, effectiveSetting{name, value, source})
 	}
+	proj := m.globalStore.GetProjectForTicket(ticket)
 
-	agentPort := ticket.AgentPort
-	if agentPort == 0 && agentType == "opencode" {
-		agentPort = m.allocateAgentPort()
-		ticket.AgentPort = agentPort
-		m.saveTicket(ticket)
+	agentName, source := ticket.AgentType, "ticket"
+	if ticket.AgentOverride != "" {
+		agentName, source = ticket.AgentOverride, "ticket (switch on next spawn)"
+	}
+	if agentName == "" {
+		agentName, source = m.config.Defaults.DefaultAgent, "defaults.default_agent"
 	}
+	add("Agent", agentName, source)
 
-	mgr := m.worktreeMgrs[proj.ID]
-	cfg := m.config
+	agentCfg, ok := m.config.Agents[agentName]
+	if !ok {
+		add("Command", "(not configured)", "agents."+agentName)
+		return settings, "", ""
+	}
+	agentKey := "agents." + agentName
+	add("Command", agentCfg.Command, agentKey+".command")
 
-	return func() tea.Msg {
-		if mgr == nil {
-			return spawnErrorMsg{ticketID: ticketID, err: "worktree manager not found"}
-		}
+	commandName := agentCfg.Command
+	if strings.Contains(commandName, "/") {
+		commandName = filepath.Base(commandName)
+	}
 
-		generatedBranch := branchName
-		if generatedBranch == "" {
-			maxLen := m.getSlugMaxLength(proj)
-			slug := board.Slugify(ticket.Title, maxLen)
-			template := m.getBranchTemplate(proj)
-			prefix := m.getBranchPrefix(proj)
-			generatedBranch = strings.ReplaceAll(template, "{prefix}", prefix)
-			generatedBranch = strings.ReplaceAll(generatedBranch, "{slug}", slug)
+	var branch string
+	if ticket.BranchName != "" {
+		branch = ticket.BranchName
+		add("Branch", branch, "ticket")
+	} else {
+		branch = m.generateBranchNameFromTitle(ticket.Title, ticket.Number, proj)
+		add("Branch", branch, "generated on first spawn")
+
+		template, source := "{prefix}{slug}", "built-in"
+		if proj != nil && proj.Settings.BranchTemplate != "" {
+			template, source = proj.Settings.BranchTemplate, "project branch_template"
+		} else if m.config.Defaults.BranchTemplate != "" {
+			template, source = m.config.Defaults.BranchTemplate, "defaults.branch_template"
 		}
+		add("  Template", template, source)
 
-		base, _ := mgr.GetDefaultBranch()
-		if baseBranch != "" {
-			base = baseBranch
+		prefix, source := "task/", "built-in"
+		if proj != nil && proj.Settings.BranchPrefix != "" {
+			prefix, source = proj.Settings.BranchPrefix, "project branch_prefix"
+		} else if m.config.Defaults.BranchPrefix != "" {
+			prefix, source = m.config.Defaults.BranchPrefix, "defaults.branch_prefix"
 		}
+		add("  Prefix", prefix, source)
 
-		if useWorktree {
-			if worktreePath == "" {
-				path, err := mgr.CreateWorktree(generatedBranch, base)
-				if err != nil {
-					return spawnErrorMsg{ticketID: ticketID, err: "worktree failed: " + err.Error()}
-				}
-				worktreePath = path
-			}
-		} else {
-			if err := mgr.SetupBranch(generatedBranch, base); err != nil {
-				return spawnErrorMsg{ticketID: ticketID, err: "branch setup failed: " + err.Error()}
-			}
-			worktreePath = proj.RepoPath
+		slugLen, source := 40, "built-in"
+		if proj != nil && proj.Settings.SlugMaxLength > 0 {
+			slugLen, source = proj.Settings.SlugMaxLength, "project slug_max_length"
+		} else if m.config.Defaults.SlugMaxLength > 0 {
+			slugLen, source = m.config.Defaults.SlugMaxLength, "defaults.slug_max_length"
 		}
-		branchName = generatedBranch
-		baseBranch = base
+		add("  Slug length", strconv.Itoa(slugLen), source)
+	}
 
-		pane := terminal.New(string(ticketID), width, height, 0)
-		pane.SetWorkdir(worktreePath)
+	if ticket.BaseBranch != "" {
+		add("Base branch", ticket.BaseBranch, "ticket")
+	} else if mgr := m.worktreeMgrs[ticket.ProjectID]; mgr != nil {
+		base, _ := mgr.GetDefaultBranch()
+		add("Base branch", base, "repository default branch")
+	}
 
-		// Set session name for terminal identification (priority: AgentSessionID > branch > ticket)
-		sessionName := string(ticketID)
-		if branchName != "" {
-			sessionName = branchName
-		}
-		if ticket.AgentSessionID != "" {
-			sessionName = ticket.AgentSessionID
+	workdir := ticket.WorktreePath
+	switch {
+	case !ticket.UseWorktree && proj != nil:
+		workdir = proj.RepoPath
+		add("Directory", workdir, "main repo (ticket has no worktree)")
+	case workdir != "":
+		add("Directory", workdir, "ticket worktree")
+	case proj != nil:
+		add("Directory", filepath.Join(proj.GetWorktreeDir(), "…"), "new worktree in project worktree_dir")
+	}
+
+	port := ticket.AgentPort
+	if port > 0 {
+		add("Port", strconv.Itoa(port), "ticket")
+	} else if commandName == "opencode" {
+		b := m.config.Behavior
+		add("Port", fmt.Sprintf("allocated on spawn from %d-%d", b.AgentPortBase, b.AgentPortBase+b.AgentPortRange-1),
+			"behavior.agent_port_base, behavior.agent_port_range")
+	}
+
+	configArgs, source := agentCfg.Args, agentKey+".args"
+	if len(configArgs) == 0 && commandName == "opencode" {
+		configArgs, source = config.DefaultAgentArgs("opencode"), "built-in (agents.opencode.args is empty)"
+	}
+	values := agent.ArgValues{Worktree: workdir, Branch: branch, Title: ticket.Title, Port: port}
+	if len(configArgs) > 0 {
+		add("Args", strings.Join(agent.ExpandArgs(configArgs, values), " "), source)
+	}
+	if len(ticket.AgentArgs) > 0 {
+		add("Extra args", strings.Join(agent.ExpandArgs(ticket.AgentArgs, values), " "), "ticket")
+	}
+	if commandName == "opencode" {
+		given := append(agent.ExpandArgs(configArgs, values), agent.ExpandArgs(ticket.AgentArgs, values)...)
+		if added := agent.OpencodeArgs(given, workdir, port)[len(given):]; len(added) > 0 {
+			add("Added args", strings.Join(added, " "), "required by opencode")
 		}
-		pane.SetSessionName(sessionName)
-
-		// Clean up any stale status file from previous sessions that may not have
-		// been properly cleaned up (e.g., if the app was closed while an agent was running)
-		agent.CleanupStatusFile(sessionName)
-
-		isNewSession := ticket.AgentSpawnedAt == nil
-		args := make([]string, len(agentCfg.Args))
-		copy(args, agentCfg.Args)
-
-		promptTemplate := cfg.GetEffectiveInitPrompt(agentType)
-
-		switch agentType {
-		case "claude":
-			if isNewSession && promptTemplate != "" {
-				prompt := agent.BuildContextPrompt(promptTemplate, ticket)
-				if prompt != "" {
-					args = append(args, prompt)
-				}
-			} else if !isNewSession {
-				hasFlag := false
-				for _, arg := range args {
-					if arg == "--continue" || arg == "-c" {
-						hasFlag = true
-						break
-					}
-				}
-				if !hasFlag {
-					args = append(args, "--continue")
-				}
-			}
-		case "opencode":
-			command := agentCfg.Command
-			sessionID := agent.FindOpencodeSession(worktreePath)
+	}
 
-			args = []string{worktreePath, "--port", fmt.Sprintf("%d", agentPort)}
-			if isNewSession {
-				if promptTemplate != "" {
-					prompt := agent.BuildContextPrompt(promptTemplate, ticket)
-					if prompt != "" {
-						args = append(args, "--prompt", prompt)
-					}
-				}
-			} else if sessionID != "" {
-				args = append(args, "--session", sessionID)
-			} else {
-				args = append(args, "--continue")
-			}
-			return spawnReadyMsg{
-				ticketID:     ticketID,
-				pane:         pane,
-				command:      command,
-				args:         args,
-				worktreePath: worktreePath,
-				branchName:   branchName,
-				baseBranch:   baseBranch,
-			}
-		case "gemini":
-			command := agentCfg.Command
-			if !isNewSession {
-				sessionID := agent.FindGeminiSession(worktreePath)
-				if sessionID != "" {
-					args = append(args, "--resume")
-				}
-			} else if promptTemplate != "" {
-				prompt := agent.BuildContextPrompt(promptTemplate, ticket)
-				if prompt != "" {
-					args = append(args, "-i", prompt)
-				}
-			}
-			return spawnReadyMsg{
-				ticketID:     ticketID,
-				pane:         pane,
-				command:      command,
-				args:         args,
-				worktreePath: worktreePath,
-				branchName:   branchName,
-				baseBranch:   baseBranch,
-			}
-		case "codex":
-			command := agentCfg.Command
-			if !isNewSession {
-				sessionID := agent.FindCodexSession(worktreePath)
-				if sessionID != "" {
-					if sessionID == "last" {
-						args = []string{"resume", "--last"}
-					} else {
-						args = []string{"resume", sessionID}
-					}
-					args = append(args, agentCfg.Args...)
-				}
-			} else if promptTemplate != "" {
-				prompt := agent.BuildContextPrompt(promptTemplate, ticket)
-				if prompt != "" {
-					args = append(args, prompt)
-				}
-			}
-			return spawnReadyMsg{
-				ticketID:     ticketID,
-				pane:         pane,
-				command:      command,
-				args:         args,
-				worktreePath: worktreePath,
-				branchName:   branchName,
-				baseBranch:   baseBranch,
-			}
-		}
+	session, source := string(ticket.ID), "ticket ID"
+	if ticket.BranchName != "" {
+		session, source = ticket.BranchName, "branch"
+	}
+	if ticket.AgentSessionID != "" {
+		session, source = ticket.AgentSessionID, "ticket agent session"
+	}
+	add("Env", "OPENKANBAN_SESSION="+session, source)
 
-		return spawnReadyMsg{
-			ticketID:     ticketID,
-			pane:         pane,
-			command:      agentCfg.Command,
-			args:         args,
-			worktreePath: worktreePath,
-			branchName:   branchName,
-			baseBranch:   baseBranch,
-		}
+	if agentCfg.StatusFile != "" {
+		add("Status file", agentCfg.StatusFile, agentKey+".status_file")
+	}
+	if agentCfg.PollIntervalSeconds > 0 {
+		add("Poll interval", fmt.Sprintf("%ds", agentCfg.PollIntervalSeconds), agentKey+".poll_interval_seconds")
+	} else {
+		add("Poll interval", fmt.Sprintf("%ds", max(m.config.Opencode.PollInterval, 1)), "opencode.poll_interval")
+	}
+	if agentCfg.ScrollbackLines > 0 {
+		add("Scrollback", strconv.Itoa(agentCfg.ScrollbackLines), agentKey+".scrollback_lines")
+	} else if m.config.UI.ScrollbackLines > 0 {
+		add("Scrollback", strconv.Itoa(m.config.UI.ScrollbackLines), "ui.scrollback_lines")
+	} else {
+		add("Scrollback", "10000", "built-in")
+	}
+
+	if ticket.AgentSpawnedAt != nil {
+		return settings, "", "none: the agent resumes its previous session"
+	}
+	switch {
+	case m.config.Agents[commandName].InitPrompt != "":
+		promptSource = "agents." + commandName + ".init_prompt"
+	case m.config.Defaults.InitPrompt != "":
+		promptSource = "defaults.init_prompt"
+	default:
+		promptSource = "built-in"
 	}
+	return settings, agent.BuildContextPrompt(m.config.GetEffectiveInitPrompt(commandName), ticket), promptSource
 }
 
-func (m *Model) stopAgent() (tea.Model, tea.Cmd) {
-	ticket := m.selectedTicket()
-	if ticket == nil {
+func (m *Model) openEffectiveConfig() (tea.Model, tea.Cmd) {
+	if m.selectedTicket() == nil {
 		return m, nil
 	}
+	m.sidebarFocused = false
+	settings, prompt, promptSource := m.effectiveSettings(m.selectedTicket())
+	m.effectiveConfig = effectiveConfig{settings: settings, prompt: prompt, promptSource: promptSource}
+	m.effectiveConfigScroll = 0
+	m.mode = ModeEffectiveConfig
+	return m, nil
+}
 
-	if pane, ok := m.panes[ticket.ID]; ok {
-		pane.Stop()
-		delete(m.panes, ticket.ID)
+func (m *Model) handleEffectiveConfigMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		body, _, _ := m.effectiveConfigBody()
+		m.effectiveConfigScroll = min(m.effectiveConfigScroll+1, max(len(body)-m.effectiveConfigVisibleLines(), 0))
+	case "k", "up":
+		m.effectiveConfigScroll = max(m.effectiveConfigScroll-1, 0)
+	case "e":
+		m.mode = ModeNormal
+		return m.editTicket()
+	case "O":
+		m.mode = ModeSettings
+		m.settingsIndex = 0
+		m.settingsEditing = false
+	case "q", "C":
+		m.mode = ModeNormal
 	}
-
-	ticket.AgentStatus = board.AgentNone
-	m.saveTicket(ticket)
-	m.notify("Agent stopped")
 	return m, nil
 }
 
-func (m *Model) selectedTicket() *board.Ticket {
-	if len(m.columnTickets) <= m.activeColumn {
-		return nil
+func (m *Model) getSlugMaxLength(proj *project.Project) int {
+	if proj != nil && proj.Settings.SlugMaxLength > 0 {
+		return proj.Settings.SlugMaxLength
 	}
-	tickets := m.columnTickets[m.activeColumn]
-	if len(tickets) <= m.activeTicket {
-		return nil
+	if m.config.Defaults.SlugMaxLength > 0 {
+		return m.config.Defaults.SlugMaxLength
 	}
-	return tickets[m.activeTicket]
+	return 40
 }
 
-func (m *Model) selectTicketByID(ticketID board.TicketID) {
-	for colIdx, tickets := range m.columnTickets {
-		for ticketIdx, t := range tickets {
-			if t.ID == ticketID {
-				m.activeColumn = colIdx
-				m.activeTicket = ticketIdx
-				m.ensureTicketVisible()
-				return
-			}
+func (m *Model) getAgentIndex(agentName string) int {
+	agents := m.getAgentNames()
+	for i, name := range agents {
+		if name == agentName {
+			return i
 		}
 	}
+	return 0
 }
 
-func (m *Model) refreshColumnTickets() {
-	m.columnTickets = make([][]*board.Ticket, len(m.columns))
-	for i, col := range m.columns {
-		allForStatus := m.globalStore.GetByStatus(col.Status)
-		var filtered []*board.Ticket
-		for _, t := range allForStatus {
-			if !m.ticketMatchesFilter(t) {
-				continue
-			}
-			filtered = append(filtered, t)
-		}
-		m.columnTickets[i] = filtered
+const gracefulShutdownTimeout = 3 * time.Second
+
+func (m *Model) Cleanup() {
+	panes := make([]*terminal.Pane, 0, len(m.panes)+len(m.editorPanes)+len(m.quickAgents))
+	for _, pane := range m.panes {
+		panes = append(panes, pane)
+	}
+	for _, pane := range m.editorPanes {
+		panes = append(panes, pane)
+	}
+	for _, quick := range m.quickAgents {
+		panes = append(panes, quick.pane)
 	}
 
-	if len(m.columnOffsets) != len(m.columns) {
-		m.columnOffsets = make([]int, len(m.columns))
+	// Stop them together so shutdown waits one timeout, not one per agent.
+	var wg sync.WaitGroup
+	for _, pane := range panes {
+		if !pane.Running() {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pane.StopGraceful(gracefulShutdownTimeout)
+		}()
 	}
+	wg.Wait()
+
+	m.opencodeServer.Stop()
 }
 
-func (m *Model) ticketMatchesFilter(t *board.Ticket) bool {
-	if len(m.filterProjectIDs) > 0 && !m.filterProjectIDs[t.ProjectID] {
-		return false
+// runningEditorCount returns how many editors opened with o are still open.
+func (m *Model) runningEditorCount() int {
+	count := 0
+	for _, pane := range m.editorPanes {
+		if pane.Running() {
+			count++
+		}
 	}
-	if m.filterQuery == "" {
-		return true
+	return count
+}
+
+func (m *Model) pollAgentStatusesAsync() tea.Cmd {
+	type paneInfo struct {
+		ticketID        board.TicketID
+		agentType       string
+		worktreePath    string
+		branchName      string
+		agentPort       int
+		agentSessionID  string
+		running         bool
+		terminalContent string
 	}
 
-	query := strings.ToLower(m.filterQuery)
+	// Agents with a longer poll_interval_seconds are skipped until their
+	// interval has passed; half a tick of slack keeps them on the nearest
+	// tick rather than drifting one late.
+	tick := m.agentMgr.StatusPollInterval()
+	now := time.Now()
 
-	if strings.HasPrefix(query, "@") {
-		parts := strings.SplitN(query, " ", 2)
-		projectName := strings.TrimPrefix(parts[0], "@")
-		proj := m.globalStore.GetProjectForTicket(t)
-		if proj == nil || !strings.Contains(strings.ToLower(proj.Name), projectName) {
-			return false
+	var panes []paneInfo
+	for ticketID, pane := range m.panes {
+		ticket, _ := m.globalStore.Get(ticketID)
+		if ticket == nil {
+			continue
 		}
-		if len(parts) == 1 {
-			return true
+		running := pane.Running()
+		if last, ok := m.lastPolled[ticketID]; ok && running && now.Sub(last) < m.agentMgr.AgentPollInterval(ticket.AgentType)-tick/2 {
+			continue
 		}
-		query = strings.TrimSpace(parts[1])
+		if running {
+			m.lastPolled[ticketID] = now
+		} else {
+			delete(m.lastPolled, ticketID)
+		}
+		worktreePath := pane.GetWorkdir()
+		if worktreePath == "" {
+			worktreePath = ticket.WorktreePath
+		}
+		panes = append(panes, paneInfo{
+			ticketID:        ticketID,
+			agentType:       ticket.AgentType,
+			worktreePath:    worktreePath,
+			branchName:      ticket.BranchName,
+			agentPort:       ticket.AgentPort,
+			agentSessionID:  ticket.AgentSessionID,
+			running:         running,
+			terminalContent: pane.GetContent(),
+		})
 	}
 
-	title := strings.ToLower(t.Title)
-	desc := strings.ToLower(t.Description)
-	return strings.Contains(title, query) || strings.Contains(desc, query)
-}
+	detector := m.statusDetector
+	globalStore := m.globalStore
 
-func (m *Model) nextStatus(current board.TicketStatus) board.TicketStatus {
-	switch current {
-	case board.StatusBacklog:
-		return board.StatusInProgress
-	case board.StatusInProgress:
-		return board.StatusDone
-	default:
-		return current
+	return func() tea.Msg {
+		results := make(agentStatusResultMsg)
+		for _, p := range panes {
+			if !p.running {
+				results[p.ticketID] = board.AgentNone
+				continue
+			}
+
+			sessionID := p.agentSessionID
+			if sessionID == "" && p.agentType == "opencode" && p.worktreePath != "" {
+				if id := agent.FindOpencodeSession(p.worktreePath); id != "" {
+					sessionID = id
+					if ticket, _ := globalStore.Get(p.ticketID); ticket != nil {
+						ticket.AgentSessionID = sessionID
+						globalStore.Save(ticket)
+					}
+				}
+			}
+			if sessionID == "" {
+				sessionID = p.branchName
+			}
+			if sessionID == "" {
+				sessionID = string(p.ticketID)
+			}
+
+			status := detector.DetectStatusWithPort(p.agentType, sessionID, p.worktreePath, p.agentPort, true, p.terminalContent)
+			results[p.ticketID] = status
+		}
+		return results
 	}
 }
 
-func (m *Model) previousStatus(current board.TicketStatus) board.TicketStatus {
-	switch current {
-	case board.StatusDone:
-		return board.StatusInProgress
-	case board.StatusInProgress:
-		return board.StatusBacklog
-	default:
-		return current
+// pollBranchDriftAsync compares each ticket's worktree with its base branch
+// in the background.
+func (m *Model) pollBranchDriftAsync() tea.Cmd {
+	type driftInfo struct {
+		ticketID     board.TicketID
+		mgr          *git.WorktreeManager
+		worktreePath string
+		baseBranch   string
+	}
+
+	var infos []driftInfo
+	for _, ticket := range m.globalStore.All() {
+		if ticket.WorktreePath == "" || ticket.BaseBranch == "" {
+			continue
+		}
+		mgr := m.worktreeMgrs[ticket.ProjectID]
+		if mgr == nil {
+			continue
+		}
+		infos = append(infos, driftInfo{
+			ticketID:     ticket.ID,
+			mgr:          mgr,
+			worktreePath: ticket.WorktreePath,
+			baseBranch:   ticket.BaseBranch,
+		})
+	}
+	if len(infos) == 0 {
+		return func() tea.Msg { return branchDriftResultMsg{} }
+	}
+
+	return func() tea.Msg {
+		results := make(branchDriftResultMsg)
+		for _, info := range infos {
+			ahead, behind, err := info.mgr.AheadBehind(info.worktreePath, info.baseBranch)
+			if err != nil {
+				continue
+			}
+			results[info.ticketID] = branchDrift{ahead: ahead, behind: behind}
+		}
+		return results
 	}
 }
 
-func (m *Model) notify(msg string) {
-	m.notification = msg
-	m.notifyTime = time.Now()
+// ticketKey returns the ticket's short ID, such as "ENG-12", or "" for an
+// orphaned ticket.
+func (m *Model) ticketKey(ticket *board.Ticket) string {
+	if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+		return proj.TicketKey(ticket.Number)
+	}
+	return ""
 }
 
-func (m *Model) saveTicket(ticket *board.Ticket) {
-	if err := m.globalStore.Save(ticket); err != nil {
-		m.notify("Failed to save: " + err.Error())
+// contextProject picks the project for project-wide actions such as the
+// worktrees view: the selected ticket's, else the single filtered project,
+// else the only project.
+func (m *Model) contextProject() *project.Project {
+	if ticket := m.selectedTicket(); ticket != nil {
+		if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+			return proj
+		}
+	}
+	if len(m.filterProjectIDs) == 1 {
+		for id := range m.filterProjectIDs {
+			if proj := m.globalStore.GetProject(id); proj != nil {
+				return proj
+			}
+		}
+	}
+	if projects := m.globalStore.Projects(); len(projects) == 1 {
+		return projects[0]
 	}
+	return nil
 }
 
-func (m *Model) resetSpawnState(ticketID board.TicketID) {
-	if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
-		ticket.AgentSpawnedAt = nil
-		ticket.AgentStatus = board.AgentNone
-		m.saveTicket(ticket)
+func (m *Model) openWorktreesView(proj *project.Project) (tea.Model, tea.Cmd) {
+	if proj == nil {
+		m.notify("Select a ticket or project first")
+		return m, nil
 	}
-	m.mode = ModeNormal
-	m.spawningTicketID = ""
-	m.spawningAgent = ""
-	delete(m.panes, ticketID)
+	m.sidebarFocused = false
+	m.mode = ModeWorktrees
+	m.worktreeProject = proj
+	m.worktreeRows = nil
+	m.worktreeIndex = 0
+	m.worktreeErr = nil
+	m.worktreeLoading = true
+	return m, m.loadWorktreesAsync(proj)
 }
 
-func (m *Model) RunningAgentCount() int {
-	count := 0
-	for _, pane := range m.panes {
-		if pane.Running() {
-			count++
+// loadWorktreesAsync lists proj's git worktrees, matching each to the
+// ticket whose WorktreePath it is, with dirty state and base drift.
+func (m *Model) loadWorktreesAsync(proj *project.Project) tea.Cmd {
+	mgr := m.worktreeMgrs[proj.ID]
+	if mgr == nil {
+		return func() tea.Msg {
+			return worktreesLoadedMsg{projectID: proj.ID, err: errors.New("no worktree manager for project")}
 		}
 	}
-	return count
-}
 
-func (m *Model) getAgentNames() []string {
-	names := make([]string, 0, len(m.config.Agents))
-	for name := range m.config.Agents {
-		names = append(names, name)
+	type ticketRef struct {
+		id         board.TicketID
+		baseBranch string
 	}
-	if len(names) == 0 {
-		return []string{"opencode", "claude", "gemini", "codex", "aider"}
+	byPath := make(map[string]ticketRef)
+	for _, t := range m.globalStore.All() {
+		if t.ProjectID == proj.ID && t.WorktreePath != "" {
+			byPath[filepath.Clean(t.WorktreePath)] = ticketRef{id: t.ID, baseBranch: t.BaseBranch}
+		}
+	}
+	repoPath := filepath.Clean(proj.RepoPath)
+
+	return func() tea.Msg {
+		worktrees, err := mgr.ListWorktrees()
+		if err != nil {
+			return worktreesLoadedMsg{projectID: proj.ID, err: err}
+		}
+		defaultBranch, _ := mgr.GetDefaultBranch()
+
+		rows := make([]worktreeRow, 0, len(worktrees))
+		for _, wt := range worktrees {
+			path := filepath.Clean(wt.Path)
+			row := worktreeRow{path: path, branch: wt.Branch, main: path == repoPath}
+			base := defaultBranch
+			if ref, ok := byPath[path]; ok {
+				row.ticketID = ref.id
+				if ref.baseBranch != "" {
+					base = ref.baseBranch
+				}
+			}
+			row.dirty, _ = mgr.HasUncommittedChanges(path)
+			if !row.main && wt.Branch != "" && base != "" {
+				if ahead, behind, err := mgr.AheadBehind(path, base); err == nil {
+					row.ahead, row.behind, row.driftKnown = ahead, behind, true
+				}
+			}
+			rows = append(rows, row)
+		}
+		return worktreesLoadedMsg{projectID: proj.ID, rows: rows}
 	}
-	sort.Strings(names)
-	return names
 }
 
-func (m *Model) getDefaultAgent() string {
-	return m.config.Defaults.DefaultAgent
+func (m *Model) handleWorktreesLoaded(msg worktreesLoadedMsg) {
+	if m.worktreeProject == nil || m.worktreeProject.ID != msg.projectID {
+		return
+	}
+	m.worktreeLoading = false
+	m.worktreeErr = msg.err
+	m.worktreeRows = msg.rows
+	m.worktreeIndex = min(m.worktreeIndex, max(len(m.worktreeRows)-1, 0))
+}
+
+func (m *Model) handleWorktreesMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		if m.worktreeIndex < len(m.worktreeRows)-1 {
+			m.worktreeIndex++
+		}
+	case "k", "up":
+		if m.worktreeIndex > 0 {
+			m.worktreeIndex--
+		}
+	case "r":
+		m.worktreeLoading = true
+		return m, m.loadWorktreesAsync(m.worktreeProject)
+	case "d":
+		m.confirmRemoveWorktree()
+	case "o":
+		if row := m.selectedWorktree(); row != nil {
+			if err := openInFileManager(row.path); err != nil {
+				m.notify("Failed to open worktree: " + err.Error())
+			}
+		}
+	case "enter":
+		return m.jumpToWorktreeTicket()
+	case "q":
+		m.mode = ModeNormal
+	}
+	return m, nil
 }
 
-func (m *Model) getBranchPrefix(proj *project.Project) string {
-	if proj != nil && proj.Settings.BranchPrefix != "" {
-		return proj.Settings.BranchPrefix
-	}
-	if m.config.Defaults.BranchPrefix != "" {
-		return m.config.Defaults.BranchPrefix
+func (m *Model) selectedWorktree() *worktreeRow {
+	if m.worktreeIndex < 0 || m.worktreeIndex >= len(m.worktreeRows) {
+		return nil
 	}
-	return "task/"
+	return &m.worktreeRows[m.worktreeIndex]
 }
 
-func (m *Model) getBranchTemplate(proj *project.Project) string {
-	if proj != nil && proj.Settings.BranchTemplate != "" {
-		return proj.Settings.BranchTemplate
+// jumpToWorktreeTicket closes the worktrees view and selects the ticket
+// that owns the highlighted worktree.
+func (m *Model) jumpToWorktreeTicket() (tea.Model, tea.Cmd) {
+	row := m.selectedWorktree()
+	if row == nil {
+		return m, nil
 	}
-	if m.config.Defaults.BranchTemplate != "" {
-		return m.config.Defaults.BranchTemplate
+	if row.ticketID == "" {
+		m.notify("No ticket tracks this worktree")
+		return m, nil
 	}
-	return "{prefix}{slug}"
+	m.mode = ModeNormal
+	m.selectTicketByID(row.ticketID)
+	if ticket := m.selectedTicket(); ticket == nil || ticket.ID != row.ticketID {
+		m.notify("Ticket is hidden by the current filter")
+	}
+	return m, nil
 }
 
-func (m *Model) getSlugMaxLength(proj *project.Project) int {
-	if proj != nil && proj.Settings.SlugMaxLength > 0 {
-		return proj.Settings.SlugMaxLength
+func (m *Model) confirmRemoveWorktree() {
+	if m.denyReadOnly() {
+		return
 	}
-	if m.config.Defaults.SlugMaxLength > 0 {
-		return m.config.Defaults.SlugMaxLength
+	row := m.selectedWorktree()
+	if row == nil {
+		return
+	}
+	if row.main {
+		m.notify("Can't remove the main checkout")
+		return
+	}
+	if pane, ok := m.panes[row.ticketID]; ok && pane.Running() {
+		m.notify("Stop the ticket's agent before removing its worktree")
+		return
 	}
-	return 40
-}
 
-func (m *Model) getAgentIndex(agentName string) int {
-	agents := m.getAgentNames()
-	for i, name := range agents {
-		if name == agentName {
-			return i
+	mgr := m.worktreeMgrs[m.worktreeProject.ID]
+	path, ticketID := row.path, row.ticketID
+	m.showConfirm = true
+	m.confirmMsg = fmt.Sprintf("Remove worktree %s?", filepath.Base(path))
+	if row.dirty {
+		m.confirmMsg += " It has uncommitted changes that will be lost."
+	}
+	if ticketID == "" {
+		m.confirmMsg += " No ticket tracks it."
+	}
+	m.confirmFn = func() tea.Cmd {
+		m.startGitOp("Removing worktree")
+		return func() tea.Msg {
+			return worktreeRemovedMsg{path: path, ticketID: ticketID, err: mgr.RemoveWorktree(path)}
 		}
 	}
-	return 0
 }
 
-const gracefulShutdownTimeout = 3 * time.Second
-
-func (m *Model) Cleanup() {
-	for _, pane := range m.panes {
-		if pane.Running() {
-			pane.StopGraceful(gracefulShutdownTimeout)
-		}
+func (m *Model) handleWorktreeRemoved(msg worktreeRemovedMsg) tea.Cmd {
+	m.endGitOp()
+	if msg.err != nil {
+		m.notify("Failed to remove worktree: " + msg.err.Error())
+		return nil
+	}
+	if ticket, _ := m.globalStore.Get(msg.ticketID); ticket != nil && filepath.Clean(ticket.WorktreePath) == msg.path {
+		ticket.WorktreePath = ""
+		m.saveTicket(ticket)
+	}
+	m.notify("Removed worktree " + filepath.Base(msg.path))
+	if m.mode != ModeWorktrees || m.worktreeProject == nil {
+		return nil
 	}
+	return m.loadWorktreesAsync(m.worktreeProject)
 }
 
-func (m *Model) pollAgentStatusesAsync() tea.Cmd {
-	type paneInfo struct {
-		ticketID        board.TicketID
-		agentType       string
-		worktreePath    string
-		branchName      string
-		agentPort       int
-		agentSessionID  string
-		running         bool
-		terminalContent string
+// openInFileManager opens path with the platform's default handler.
+func openInFileManager(path string) error {
+	opener := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		opener = "open"
 	}
-
-	var panes []paneInfo
-	for ticketID, pane := range m.panes {
-		ticket, _ := m.globalStore.Get(ticketID)
-		if ticket == nil {
-			continue
-		}
-		worktreePath := pane.GetWorkdir()
-		if worktreePath == "" {
-			worktreePath = ticket.WorktreePath
-		}
-		panes = append(panes, paneInfo{
-			ticketID:        ticketID,
-			agentType:       ticket.AgentType,
-			worktreePath:    worktreePath,
-			branchName:      ticket.BranchName,
-			agentPort:       ticket.AgentPort,
-			agentSessionID:  ticket.AgentSessionID,
-			running:         pane.Running(),
-			terminalContent: pane.GetContent(),
-		})
+	cmd := exec.Command(opener, path)
+	if err := cmd.Start(); err != nil {
+		return err
 	}
+	go cmd.Wait()
+	return nil
+}
 
-	detector := m.statusDetector
-	globalStore := m.globalStore
+// pollWorkSummaryAsync counts the focused ticket's uncommitted files and
+// commits ahead of its base branch while an agent is attached.
+func (m *Model) pollWorkSummaryAsync() tea.Cmd {
+	if m.mode != ModeAgentView || m.focusedPane == "" {
+		return nil
+	}
+	ticket, _ := m.globalStore.Get(m.focusedPane)
+	if ticket == nil {
+		return nil
+	}
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil {
+		return nil
+	}
+	mgr := m.worktreeMgrs[proj.ID]
+	if mgr == nil {
+		return nil
+	}
+	worktreePath := ticket.WorktreePath
+	if worktreePath == "" {
+		worktreePath = proj.RepoPath
+	}
+	ticketID := ticket.ID
+	branch, base := ticket.BranchName, ticket.BaseBranch
 
 	return func() tea.Msg {
-		results := make(agentStatusResultMsg)
-		for _, p := range panes {
-			if !p.running {
-				results[p.ticketID] = board.AgentNone
-				continue
-			}
-
-			sessionID := p.agentSessionID
-			if sessionID == "" && p.agentType == "opencode" && p.worktreePath != "" {
-				if id := agent.FindOpencodeSession(p.worktreePath); id != "" {
-					sessionID = id
-					if ticket, _ := globalStore.Get(p.ticketID); ticket != nil {
-						ticket.AgentSessionID = sessionID
-						globalStore.Save(ticket)
-					}
-				}
-			}
-			if sessionID == "" {
-				sessionID = p.branchName
-			}
-			if sessionID == "" {
-				sessionID = string(p.ticketID)
+		files, err := mgr.CountUncommittedFiles(worktreePath)
+		if err != nil {
+			return workSummaryMsg{ticketID: ticketID}
+		}
+		summary := workSummaryMsg{ticketID: ticketID, files: files, commits: -1, valid: true}
+		if branch != "" && base != "" {
+			if ahead, err := mgr.CommitsAhead(branch, base); err == nil {
+				summary.commits = ahead
 			}
-
-			status := detector.DetectStatusWithPort(p.agentType, sessionID, p.worktreePath, p.agentPort, true, p.terminalContent)
-			results[p.ticketID] = status
 		}
-		return results
+		return summary
 	}
 }
 
@@ -2991,15 +8917,184 @@ func (m *Model) handleTerminalMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 	}
+	for _, pane := range m.editorPanes {
+		if cmd := pane.Update(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	for _, quick := range m.quickAgents {
+		if cmd := quick.pane.Update(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
 	return m, tea.Batch(cmds...)
 }
 
 type agentStatusMsg time.Time
+type diffTickMsg time.Time
+
+type diffResultMsg struct {
+	ticketID board.TicketID
+	content  string
+	err      error
+}
+
+// worktreeCreatedMsg reports the end of a background worktree creation
+// started by startTicketWork, or by linkTicketBranch when linked is set.
+type worktreeCreatedMsg struct {
+	ticketID  board.TicketID
+	projectID string
+	// fromStatus is the ticket's status when the worktree was requested;
+	// status is where it moves once the worktree exists.
+	fromStatus board.TicketStatus
+	status     board.TicketStatus
+	path       string
+	branchName string
+	baseBranch string
+	err        error
+	linked     bool
+}
+
+// ticketCleanupMsg reports the end of a deleted ticket's worktree and
+// branch removal.
+type ticketCleanupMsg struct {
+	title       string
+	worktreeErr error
+	branchErr   error
+	// branchKept names a branch left in place by
+	// cleanup.keep_unmerged_branches because it isn't merged.
+	branchKept string
+}
+
+// branchMergedMsg reports the end of a background merge started by
+// confirmMergeBranch.
+type branchMergedMsg struct {
+	branch string
+	base   string
+	err    error
+}
+
+// branchRebasedMsg reports the end of a background rebase started by
+// commandRebase.
+type branchRebasedMsg struct {
+	branch string
+	base   string
+	err    error
+}
+
+// branchListMsg reports the local and remote-tracking branches of a
+// project, loaded by loadFormBranches for the ticket form.
+type branchListMsg struct {
+	projectID string
+	branches  []string
+	remote    []string
+	err       error
+}
+
+// branchRenamableMsg reports that a retitled ticket's branch can be renamed
+// to newBranch, found by checkBranchRename.
+type branchRenamableMsg struct {
+	ticketID  board.TicketID
+	branch    string
+	newBranch string
+}
+
+// branchRenamedMsg reports the end of a background branch rename started by
+// renameTicketBranch; path is the worktree's new path.
+type branchRenamedMsg struct {
+	ticketID  board.TicketID
+	branch    string
+	newBranch string
+	path      string
+	err       error
+}
+
+// worktreeCommittedMsg reports the end of a background commit started by
+// commandCommit.
+type worktreeCommittedMsg struct {
+	title string
+	err   error
+}
+
+// deleteMarkedCheckMsg reports how many of the marked tickets about to be
+// deleted have uncommitted changes or unmerged branches, found by
+// checkDeleteMarked.
+type deleteMarkedCheckMsg struct {
+	ticketIDs []board.TicketID
+	dirty     int
+	unmerged  int
+}
+
+// deleteCheckMsg reports whether the ticket about to be deleted has
+// uncommitted changes or an unmerged branch, found by confirmDeleteTicket.
+type deleteCheckMsg struct {
+	ticketID     board.TicketID
+	dirty        bool
+	unmergedBase string
+}
+
+// pullRequestMsg reports the URL of a pull request opened by
+// confirmCreatePullRequest.
+type pullRequestMsg struct {
+	url string
+	err error
+}
+
 type agentStatusResultMsg map[board.TicketID]board.AgentStatus
+
+// branchDrift is how far a ticket's branch has moved from its base.
+type branchDrift struct {
+	ahead  int
+	behind int
+}
+
+type branchDriftResultMsg map[board.TicketID]branchDrift
+
+// workSummaryMsg carries a ticket's uncommitted file count and commits ahead
+// of its base branch; commits is -1 when the base is unknown.
+type workSummaryMsg struct {
+	ticketID board.TicketID
+	files    int
+	commits  int
+	valid    bool
+}
+
+// worktreeRow is one git worktree in the worktrees view. ticketID is empty
+// for the main checkout and for orphans no ticket tracks.
+type worktreeRow struct {
+	path       string
+	branch     string
+	ticketID   board.TicketID
+	main       bool
+	dirty      bool
+	ahead      int
+	behind     int
+	driftKnown bool
+}
+
+type worktreesLoadedMsg struct {
+	projectID string
+	rows      []worktreeRow
+	err       error
+}
+
+type worktreeRemovedMsg struct {
+	path     string
+	ticketID board.TicketID
+	err      error
+}
+
 type notificationMsg time.Time
 type shutdownCompleteMsg struct{}
+
+// ShutdownMsg asks the model to stop all agents and quit, as after an
+// external SIGTERM.
+type ShutdownMsg struct{}
 type updateCheckMsg update.CheckResult
 
+// agentsDetectedMsg is which configured agents' commands are on PATH.
+type agentsDetectedMsg map[string]bool
+
 type spawnReadyMsg struct {
 	ticketID     board.TicketID
 	pane         *terminal.Pane
@@ -3015,8 +9110,180 @@ type spawnErrorMsg struct {
 	err      string
 }
 
+func tickDiff() tea.Cmd {
+	return tea.Tick(diffRefreshInterval, func(t time.Time) tea.Msg {
+		return diffTickMsg(t)
+	})
+}
+
 func tickAgentStatus(d time.Duration) tea.Cmd {
 	return tea.Tick(d, func(t time.Time) tea.Msg {
 		return agentStatusMsg(t)
 	})
 }
+
+func (m *Model) openSavedFilters() (tea.Model, tea.Cmd) {
+	if m.filterRegistry == nil {
+		m.notify("Saved filters are unavailable")
+		return m, nil
+	}
+	m.sidebarFocused = false
+	m.mode = ModeSavedFilters
+	m.savedFilterIndex = 0
+	m.filterNaming = false
+	return m, nil
+}
+
+func (m *Model) handleSavedFiltersMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.filterNaming {
+		return m.handleFilterNaming(msg)
+	}
+
+	filters := m.filterRegistry.List()
+	switch msg.String() {
+	case "j", "down":
+		if m.savedFilterIndex < len(filters)-1 {
+			m.savedFilterIndex++
+		}
+	case "k", "up":
+		if m.savedFilterIndex > 0 {
+			m.savedFilterIndex--
+		}
+	case "enter":
+		if f := m.selectedSavedFilter(); f != nil {
+			m.applySavedFilter(f)
+			m.mode = ModeNormal
+			m.notify("Filter: " + f.Name)
+		}
+	case "n":
+		if m.denyReadOnly() {
+			return m, nil
+		}
+		if !m.hasActiveFilter() {
+			m.notify("Filter the board first, then save it here")
+			return m, nil
+		}
+		m.filterNaming = true
+		m.filterNameInput.SetValue("")
+		m.filterNameInput.Focus()
+		return m, textinput.Blink
+	case "*":
+		if m.denyReadOnly() {
+			return m, nil
+		}
+		if f := m.selectedSavedFilter(); f != nil {
+			id, label := f.ID, "Default filter: "+f.Name
+			if f.IsDefault {
+				id, label = "", "No default filter"
+			}
+			if err := m.filterRegistry.SetDefault(id); err != nil {
+				m.notify("Failed to save filters: " + err.Error())
+				return m, nil
+			}
+			m.notify(label)
+		}
+	case "d":
+		if m.denyReadOnly() {
+			return m, nil
+		}
+		if f := m.selectedSavedFilter(); f != nil {
+			m.showConfirm = true
+			m.confirmMsg = "Delete saved filter " + f.Name + "?"
+			m.confirmFn = func() tea.Cmd {
+				if err := m.filterRegistry.Delete(f.ID); err != nil {
+					m.notify("Failed to delete filter: " + err.Error())
+					return nil
+				}
+				if m.savedFilter != nil && m.savedFilter.ID == f.ID {
+					m.savedFilter = nil
+					m.refreshColumnTickets()
+				}
+				m.savedFilterIndex = min(m.savedFilterIndex, max(len(m.filterRegistry.Filters)-1, 0))
+				m.notify("Deleted filter: " + f.Name)
+				return nil
+			}
+		}
+	case "q":
+		m.mode = ModeNormal
+	}
+	return m, nil
+}
+
+func (m *Model) handleFilterNaming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.filterNaming = false
+		m.filterNameInput.Blur()
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(m.filterNameInput.Value())
+		if name == "" {
+			m.notify("Filter name cannot be empty")
+			return m, nil
+		}
+		f := m.currentFilterAsSaved(name)
+		if err := m.filterRegistry.Add(f); err != nil {
+			m.notify("Failed to save filter: " + err.Error())
+			return m, nil
+		}
+		m.filterNaming = false
+		m.filterNameInput.Blur()
+		m.savedFilter = m.narrowingFilter(f)
+		m.notify("Saved filter: " + name)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.filterNameInput, cmd = m.filterNameInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) selectedSavedFilter() *project.SavedFilter {
+	filters := m.filterRegistry.List()
+	if m.savedFilterIndex < 0 || m.savedFilterIndex >= len(filters) {
+		return nil
+	}
+	return filters[m.savedFilterIndex]
+}
+
+// currentFilterAsSaved captures the board's project filter, search query,
+// and any applied saved filter's statuses and labels as a new saved filter.
+func (m *Model) currentFilterAsSaved(name string) *project.SavedFilter {
+	f := project.NewFilter(name)
+	for id := range m.filterProjectIDs {
+		f.ProjectIDs = append(f.ProjectIDs, id)
+	}
+	sort.Strings(f.ProjectIDs)
+	f.Query = m.filterQuery
+	if m.savedFilter != nil {
+		f.Statuses = slices.Clone(m.savedFilter.Statuses)
+		f.Labels = slices.Clone(m.savedFilter.Labels)
+	}
+	return f
+}
+
+// applySavedFilter replaces the board's filters with f. Its projects and
+// query become the regular project filter and search, so they can still be
+// changed from the sidebar and with /.
+func (m *Model) applySavedFilter(f *project.SavedFilter) {
+	m.filterOrphaned = false
+	m.filterProjectIDs = make(map[string]bool)
+	for _, id := range f.ProjectIDs {
+		if m.globalStore.GetProject(id) != nil {
+			m.filterProjectIDs[id] = true
+		}
+	}
+	m.filterQuery = f.Query
+	m.savedFilter = m.narrowingFilter(f)
+	m.refreshColumnTickets()
+}
+
+// narrowingFilter returns the part of f checked on top of the project and
+// query filters: its statuses and labels, along with its name for the
+// header.
+func (m *Model) narrowingFilter(f *project.SavedFilter) *project.SavedFilter {
+	narrow := *f
+	narrow.ProjectIDs = nil
+	narrow.Query = ""
+	return &narrow
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -37,6 +38,10 @@ func (m *Model) View() string {
 		return m.renderAgentView()
 	}
 
+	if m.mode == ModeOnboarding {
+		return m.renderWithOverlay(m.renderOnboarding())
+	}
+
 	var b strings.Builder
 
 	b.WriteString(m.renderHeader())
@@ -53,6 +58,9 @@ func (m *Model) View() string {
 	if m.showHelp {
 		return m.renderWithOverlay(m.renderHelp())
 	}
+	if m.removingProject != nil {
+		return m.renderWithOverlay(m.renderRemoveProjectDialog())
+	}
 	if m.showConfirm {
 		return m.renderWithOverlay(m.renderConfirmDialog())
 	}
@@ -65,6 +73,27 @@ func (m *Model) View() string {
 	if m.mode == ModeCreateProject {
 		return m.renderWithOverlay(m.renderCreateProjectForm())
 	}
+	if m.mode == ModeWorktrees {
+		return m.renderWithOverlay(m.renderWorktreesView())
+	}
+	if m.mode == ModeSavedFilters {
+		return m.renderWithOverlay(m.renderSavedFiltersView())
+	}
+	if m.mode == ModeEffectiveConfig {
+		return m.renderWithOverlay(m.renderEffectiveConfigView())
+	}
+	if m.mode == ModeAgentPicker {
+		return m.renderWithOverlay(m.renderAgentPickerView())
+	}
+	if m.mode == ModeSpawnPrompt {
+		return m.renderWithOverlay(m.renderSpawnPromptView())
+	}
+	if m.mode == ModeConfigHealth {
+		return m.renderWithOverlay(m.renderConfigHealthView())
+	}
+	if m.mode == ModeDashboard {
+		return m.renderWithOverlay(m.renderDashboardView())
+	}
 
 	b.WriteString("\n")
 	b.WriteString(m.renderStatusBar())
@@ -81,17 +110,17 @@ func (m *Model) renderHeader() string {
 	var filterSection string
 	if m.mode == ModeFilter {
 		filterSection = m.renderFilterInput()
-	} else if m.filterQuery != "" || len(m.filterProjectIDs) > 0 {
+	} else if m.hasActiveFilter() {
 		filterSection = m.renderActiveFilter()
 	} else {
 		filterSection = m.renderFilterHint()
 	}
 
 	projectCount := len(m.globalStore.Projects())
-	ticketCount := m.globalStore.Count()
+	ticketCount := m.boardTicketCount()
 	visibleCount := m.countVisibleTickets()
 	var stats string
-	if m.filterQuery != "" || len(m.filterProjectIDs) > 0 {
+	if m.hasActiveFilter() {
 		stats = m.dimStyle().Render(fmt.Sprintf("showing %d of %d", visibleCount, ticketCount))
 	} else {
 		stats = m.dimStyle().Render(fmt.Sprintf("%d projects, %d tickets", projectCount, ticketCount))
@@ -100,10 +129,12 @@ func (m *Model) renderHeader() string {
 	left := lipgloss.JoinHorizontal(lipgloss.Center, logo, "  ", filterSection, "  ", stats)
 
 	workingCount, waitingCount, idleCount := 0, 0, 0
+	var agentTime time.Duration
 	for ticketID, pane := range m.panes {
 		if !pane.Running() {
 			continue
 		}
+		agentTime += time.Since(pane.StartedAt())
 		ticket, _ := m.globalStore.Get(ticketID)
 		if ticket == nil {
 			continue
@@ -147,9 +178,31 @@ func (m *Model) renderHeader() string {
 			Render(statusText)
 		activity = activityBadge
 	}
+	if agentTime > 0 {
+		total := m.dimStyle().Render("⏱ total " + formatDuration(agentTime))
+		if activity != "" {
+			activity = lipgloss.JoinHorizontal(lipgloss.Center, activity, "  ", total)
+		} else {
+			activity = total
+		}
+	}
+
+	if server := m.opencodeStatus; server.checked {
+		serverBadge := lipgloss.NewStyle().Foreground(m.colors.success).
+			Render(fmt.Sprintf("● opencode :%d", m.config.Opencode.ServerPort))
+		if !server.running {
+			serverBadge = lipgloss.NewStyle().Foreground(m.colors.err).
+				Render(fmt.Sprintf("✗ opencode :%d down", m.config.Opencode.ServerPort))
+		}
+		if activity != "" {
+			activity = lipgloss.JoinHorizontal(lipgloss.Center, activity, "  ", serverBadge)
+		} else {
+			activity = serverBadge
+		}
+	}
 
 	helpStyle := lipgloss.NewStyle().Foreground(m.colors.muted)
-	help := helpStyle.Render("? help  q quit")
+	help := helpStyle.Render("? help  " + m.quitKeyLabel() + " quit")
 
 	right := help
 	if activity != "" {
@@ -183,13 +236,9 @@ func (m *Model) renderBoard() string {
 
 	var columns []string
 
-	if startCol > 0 {
-		indicator := lipgloss.NewStyle().
-			Foreground(m.colors.muted).
-			Background(m.colors.surface).
-			Padding(0, 1).
-			Render(fmt.Sprintf("◀ %d", startCol))
-		columns = append(columns, indicator)
+	leftIndicator, rightIndicator := m.scrollIndicators()
+	if leftIndicator != "" {
+		columns = append(columns, leftIndicator)
 	}
 
 	for i := startCol; i < endCol; i++ {
@@ -209,23 +258,40 @@ func (m *Model) renderBoard() string {
 			ticketOffset = m.columnOffsets[i]
 		}
 
-		columns = append(columns, m.renderColumn(col, m.columnTickets[i], isActive, isDragTarget, isHovered, colWidth, isLast, ticketOffset))
+		var wip wipUsage
+		if i < len(m.columnWIP) {
+			wip = m.columnWIP[i]
+		}
+		columns = append(columns, m.renderColumn(col, m.columnTickets[i], wip, isActive, isDragTarget, isHovered, colWidth, isLast, ticketOffset))
 	}
 
-	if endCol < len(m.columns) {
-		remaining := len(m.columns) - endCol
-		indicator := lipgloss.NewStyle().
-			Foreground(m.colors.muted).
-			Background(m.colors.surface).
-			Padding(0, 1).
-			Render(fmt.Sprintf("%d ▶", remaining))
-		columns = append(columns, indicator)
+	if rightIndicator != "" {
+		columns = append(columns, rightIndicator)
 	}
 
 	return lipgloss.JoinHorizontal(lipgloss.Top, columns...)
 }
 
-func (m *Model) renderColumn(col board.Column, tickets []*board.Ticket, isActive, isDragTarget, isHovered bool, width int, isLast bool, ticketOffset int) string {
+// scrollIndicators renders the "◀ N" and "N ▶" markers for columns hidden
+// to the left and right of the board. Either is empty when nothing is hidden
+// on that side.
+func (m *Model) scrollIndicators() (left, right string) {
+	style := lipgloss.NewStyle().
+		Foreground(m.colors.muted).
+		Background(m.colors.surface).
+		Padding(0, 1)
+
+	endCol := min(m.scrollOffset+m.visibleColumnCount(m.calcColumnWidth()), len(m.columns))
+	if m.scrollOffset > 0 {
+		left = style.Render(fmt.Sprintf("◀ %d", m.scrollOffset))
+	}
+	if endCol < len(m.columns) {
+		right = style.Render(fmt.Sprintf("%d ▶", len(m.columns)-endCol))
+	}
+	return left, right
+}
+
+func (m *Model) renderColumn(col board.Column, tickets []*board.Ticket, wip wipUsage, isActive, isDragTarget, isHovered bool, width int, isLast bool, ticketOffset int) string {
 	headerColor := m.columnColor(col.Status)
 
 	columnIcons := map[board.TicketStatus]string{
@@ -234,6 +300,14 @@ func (m *Model) renderColumn(col board.Column, tickets []*board.Ticket, isActive
 		board.StatusDone:       "✅",
 	}
 	icon := columnIcons[col.Status]
+	if m.groupByProject {
+		icon = "📁"
+		headerColor = m.colors.info
+		if col.ID == orphanedColumnID {
+			icon = "⚠"
+			headerColor = m.colors.err
+		}
+	}
 	if icon == "" {
 		icon = "○"
 	}
@@ -245,9 +319,9 @@ func (m *Model) renderColumn(col board.Column, tickets []*board.Ticket, isActive
 
 	countStyle := lipgloss.NewStyle().Foreground(m.colors.muted)
 	countText := fmt.Sprintf("(%d)", len(tickets))
-	if col.Limit > 0 {
-		countText = fmt.Sprintf("(%d/%d)", len(tickets), col.Limit)
-		if len(tickets) >= col.Limit {
+	if wip.limit > 0 {
+		countText = fmt.Sprintf("(%d/%d)", wip.count, wip.limit)
+		if wip.count >= wip.limit {
 			countStyle = lipgloss.NewStyle().
 				Foreground(m.colors.base).
 				Background(m.colors.err).
@@ -285,7 +359,11 @@ func (m *Model) renderColumn(col board.Column, tickets []*board.Ticket, isActive
 		ticket := tickets[i]
 		isSelected := isActive && i == m.activeTicket
 		isTicketHovered := isHovered && i == m.hoverTicket
-		ticketViews = append(ticketViews, m.renderTicket(ticket, isSelected, isTicketHovered, width-4, headerColor))
+		accent := headerColor
+		if m.groupByProject {
+			accent = m.columnColor(ticket.Status)
+		}
+		ticketViews = append(ticketViews, m.renderTicket(ticket, isSelected, isTicketHovered, width-4, accent))
 	}
 
 	if hasMoreBelow {
@@ -295,15 +373,7 @@ func (m *Model) renderColumn(col board.Column, tickets []*board.Ticket, isActive
 
 	ticketsView := strings.Join(ticketViews, "\n")
 	if len(tickets) == 0 {
-		emptyIcon := "○"
-		emptyText := "Drag or Space to move here"
-		if col.Status == board.StatusBacklog {
-			emptyIcon = "+"
-			emptyText = "Press n to add a ticket"
-		} else if col.Status == board.StatusDone {
-			emptyIcon = "✓"
-			emptyText = "Finished tickets land here"
-		}
+		emptyIcon, emptyText := m.columnEmptyState(col)
 		emptyStyle := lipgloss.NewStyle().
 			Foreground(m.colors.muted).
 			Italic(true).
@@ -341,13 +411,21 @@ func (m *Model) renderColumn(col board.Column, tickets []*board.Ticket, isActive
 }
 
 func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, width int, columnColor lipgloss.Color) string {
+	if m.compactCards() {
+		return m.renderCompactTicket(ticket, isSelected, isHovered, width, columnColor)
+	}
+
 	pane, hasPane := m.panes[ticket.ID]
 	isRunning := hasPane && pane.Running()
 
 	effectiveStatus := ticket.AgentStatus
 
 	var projectBadge string
-	if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+	if m.groupByProject {
+		// The column already names the project; show where the ticket stands instead.
+		statusStyle := lipgloss.NewStyle().Foreground(m.columnColor(ticket.Status)).Bold(true)
+		projectBadge = statusStyle.Render("❨" + m.statusColumnName(ticket.Status) + "❩")
+	} else if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
 		shortName := proj.Name
 		if len(shortName) > 12 {
 			shortName = shortName[:10] + ".."
@@ -355,6 +433,9 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 		bracketStyle := lipgloss.NewStyle().Foreground(m.colors.info)
 		textStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
 		projectBadge = bracketStyle.Render("❨") + textStyle.Render(shortName) + bracketStyle.Render("❩")
+	} else if m.globalStore.IsOrphaned(ticket) {
+		orphanStyle := lipgloss.NewStyle().Foreground(m.colors.err)
+		projectBadge = orphanStyle.Render("❨⚠ orphaned❩")
 	}
 
 	var sessionBadge string
@@ -378,6 +459,16 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 			Foreground(m.colors.err).
 			Render("✗")
 	}
+	if isRunning && pane.Paused() {
+		sessionBadge = lipgloss.NewStyle().
+			Foreground(m.colors.muted).
+			Render("⏸")
+	}
+	if !isRunning && slices.Contains(m.spawnQueue, ticket.ID) {
+		sessionBadge = lipgloss.NewStyle().
+			Foreground(m.colors.info).
+			Render("⧗")
+	}
 
 	var priorityBadge string
 	if ticket.Priority > 0 && ticket.Priority <= 2 {
@@ -398,6 +489,9 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 	blocksCount := len(m.globalStore.GetBlocks(ticket.ID))
 	if blockedByCount > 0 || blocksCount > 0 {
 		depStyle := lipgloss.NewStyle().Foreground(m.colors.muted)
+		if len(m.globalStore.OpenBlockers(ticket.ID)) > 0 {
+			depStyle = lipgloss.NewStyle().Foreground(m.colors.err).Bold(true)
+		}
 		if blockedByCount > 0 && blocksCount > 0 {
 			depBadge = depStyle.Render(fmt.Sprintf("⛓%d↑%d↓", blockedByCount, blocksCount))
 		} else if blockedByCount > 0 {
@@ -407,16 +501,56 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 		}
 	}
 
+	var driftBadge string
+	if drift, ok := m.branchDrift[ticket.ID]; ok && ticket.WorktreePath != "" {
+		var parts []string
+		if drift.ahead > 0 {
+			parts = append(parts, lipgloss.NewStyle().Foreground(m.colors.success).Render(fmt.Sprintf("↑%d", drift.ahead)))
+		}
+		if drift.behind > 0 {
+			parts = append(parts, lipgloss.NewStyle().Foreground(m.colors.warning).Render(fmt.Sprintf("↓%d", drift.behind)))
+		}
+		driftBadge = strings.Join(parts, " ")
+	}
+
+	var dueBadge string
+	now := time.Now()
+	if ticket.IsOverdue(now) {
+		dueBadge = lipgloss.NewStyle().Foreground(m.colors.err).Bold(true).Render("⏰")
+	} else if ticket.IsDueSoon(now) {
+		dueBadge = lipgloss.NewStyle().Foreground(m.colors.warning).Render("⏰")
+	}
+
+	var snoozeBadge string
+	if ticket.IsSnoozed(now) {
+		snoozeBadge = m.dimStyle().Render("💤 " + ticket.SnoozedUntil.Format("Jan 2"))
+	}
+
 	var headerParts []string
+	if m.selectedTickets[ticket.ID] {
+		headerParts = append(headerParts, lipgloss.NewStyle().Foreground(m.colors.success).Bold(true).Render("✔"))
+	}
+	if key := m.ticketKey(ticket); key != "" {
+		headerParts = append(headerParts, lipgloss.NewStyle().Foreground(m.colors.muted).Render(key))
+	}
 	if priorityBadge != "" {
 		headerParts = append(headerParts, priorityBadge)
 	}
+	if dueBadge != "" {
+		headerParts = append(headerParts, dueBadge)
+	}
+	if snoozeBadge != "" {
+		headerParts = append(headerParts, snoozeBadge)
+	}
 	if projectBadge != "" {
 		headerParts = append(headerParts, projectBadge)
 	}
 	if depBadge != "" {
 		headerParts = append(headerParts, depBadge)
 	}
+	if driftBadge != "" {
+		headerParts = append(headerParts, driftBadge)
+	}
 	if sessionBadge != "" {
 		headerParts = append(headerParts, sessionBadge)
 	}
@@ -443,12 +577,17 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 	}
 
 	var statusParts []string
-	if ticket.AgentType != "" {
+	if ticket.AgentType != "" && !m.hideStatusDetail {
 		agentBadge := lipgloss.NewStyle().
 			Foreground(m.colors.base).
-			Background(m.colors.primary).
+			Background(m.agentColor(ticket.AgentType)).
 			Padding(0, 1).
 			Render(ticket.AgentType)
+		if ticket.AgentOverride != "" {
+			agentBadge += lipgloss.NewStyle().
+				Foreground(m.agentColor(ticket.AgentOverride)).
+				Render(" → " + ticket.AgentOverride)
+		}
 		statusParts = append(statusParts, agentBadge)
 	}
 
@@ -478,7 +617,11 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 			statusColor = m.colors.err
 		}
 		statusStyle := lipgloss.NewStyle().Foreground(statusColor)
-		statusParts = append(statusParts, statusStyle.Render(statusIcon+" "+statusText))
+		if m.hideStatusDetail {
+			statusParts = append(statusParts, statusStyle.Render("●"))
+		} else {
+			statusParts = append(statusParts, statusStyle.Render(statusIcon+" "+statusText))
+		}
 	}
 
 	statusLine := strings.Join(statusParts, " ")
@@ -553,23 +696,98 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 	return cardStyle.Render(content)
 }
 
+// renderCompactTicket draws ticket as a single line: the agent status icon,
+// the title cut to fit, and the agent badge. It is as wide as a full card.
+func (m *Model) renderCompactTicket(ticket *board.Ticket, isSelected, isHovered bool, width int, columnColor lipgloss.Color) string {
+	pane, hasPane := m.panes[ticket.ID]
+	isRunning := hasPane && pane.Running()
+
+	icon, iconColor := "○", m.colors.muted
+	switch ticket.AgentStatus {
+	case board.AgentIdle:
+		if hasPane {
+			icon, iconColor = "◆", m.colors.primary
+		}
+	case board.AgentWorking:
+		icon, iconColor = m.spinner.View(), m.colors.warning
+	case board.AgentWaiting:
+		icon, iconColor = "◐", m.colors.secondary
+	case board.AgentCompleted:
+		icon, iconColor = "✓", m.colors.success
+	case board.AgentError:
+		icon, iconColor = "✗", m.colors.err
+	}
+	switch {
+	case isRunning && pane.Paused():
+		icon, iconColor = "⏸", m.colors.muted
+	case !isRunning && slices.Contains(m.spawnQueue, ticket.ID):
+		icon, iconColor = "⧗", m.colors.info
+	case isRunning && ticket.AgentStatus == board.AgentNone:
+		icon, iconColor = "●", m.colors.success
+	}
+
+	marker := " "
+	if m.selectedTickets[ticket.ID] {
+		marker = lipgloss.NewStyle().Foreground(m.colors.success).Bold(true).Render("✔")
+	} else if isSelected {
+		marker = lipgloss.NewStyle().Foreground(columnColor).Bold(true).Render("▌")
+	}
+	left := marker + lipgloss.NewStyle().Foreground(iconColor).Render(icon) + " "
+
+	var badge string
+	if ticket.AgentType != "" && !m.hideStatusDetail {
+		badge = " " + lipgloss.NewStyle().
+			Foreground(m.agentColor(ticket.AgentType)).
+			Render(ticket.AgentType)
+	}
+
+	// Full cards are width wide plus their border.
+	lineWidth := width + 2
+	titleWidth := lineWidth - lipgloss.Width(left) - lipgloss.Width(badge)
+	if titleWidth < 8 {
+		badge = ""
+		titleWidth = lineWidth - lipgloss.Width(left)
+	}
+	title := strings.ReplaceAll(ticket.Title, "\n", " ")
+	if lipgloss.Width(title) > titleWidth {
+		title = lipgloss.NewStyle().MaxWidth(max(titleWidth-1, 0)).Render(title) + "…"
+	}
+
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.text).Bold(isSelected)
+	if isHovered && !isSelected {
+		titleStyle = titleStyle.Foreground(m.colors.subtext)
+	}
+	title = titleStyle.Width(titleWidth).Render(title)
+
+	return left + title + badge
+}
+
 func (m *Model) renderStatusBar() string {
 	type modeConfig struct {
 		icon string
 		bg   lipgloss.Color
 	}
 	modeConfigs := map[Mode]modeConfig{
-		ModeNormal:        {"◆", m.colors.primary},
-		ModeInsert:        {"✎", m.colors.success},
-		ModeCommand:       {":", m.colors.secondary},
-		ModeCreateTicket:  {"+", m.colors.success},
-		ModeEditTicket:    {"✎", m.colors.warning},
-		ModeAgentView:     {"▶", m.colors.info},
-		ModeSettings:      {"⚙", m.colors.secondary},
-		ModeHelp:          {"?", m.colors.primary},
-		ModeConfirm:       {"!", m.colors.err},
-		ModeFilter:        {"/", m.colors.info},
-		ModeCreateProject: {"📁", m.colors.success},
+		ModeNormal:          {"◆", m.colors.primary},
+		ModeInsert:          {"✎", m.colors.success},
+		ModeCommand:         {":", m.colors.secondary},
+		ModeCreateTicket:    {"+", m.colors.success},
+		ModeEditTicket:      {"✎", m.colors.warning},
+		ModeAgentView:       {"▶", m.colors.info},
+		ModeSettings:        {"⚙", m.colors.secondary},
+		ModeHelp:            {"?", m.colors.primary},
+		ModeConfirm:         {"!", m.colors.err},
+		ModeFilter:          {"/", m.colors.info},
+		ModeCreateProject:   {"📁", m.colors.success},
+		ModeOnboarding:      {"◈", m.colors.primary},
+		ModeBranchPreview:   {"⎇", m.colors.warning},
+		ModeWorktrees:       {"⎇", m.colors.info},
+		ModeSavedFilters:    {"★", m.colors.warning},
+		ModeEffectiveConfig: {"⚙", m.colors.info},
+		ModeAgentPicker:     {"▶", m.colors.secondary},
+		ModeSpawnPrompt:     {"▶", m.colors.warning},
+		ModeConfigHealth:    {"⚙", m.colors.warning},
+		ModeDashboard:       {"▶", m.colors.info},
 	}
 	cfg := modeConfigs[m.mode]
 	if cfg.bg == "" {
@@ -606,6 +824,30 @@ func (m *Model) renderStatusBar() string {
 		notif = notifBadge
 	}
 
+	if m.gitOps > 0 {
+		busy := lipgloss.NewStyle().
+			Foreground(m.colors.warning).
+			Padding(0, 1).
+			Render(m.spinner.View() + " " + m.gitOpLabel + "…")
+		notif = lipgloss.JoinHorizontal(lipgloss.Center, busy, notif)
+	}
+
+	if m.readOnly {
+		readOnly := lipgloss.NewStyle().
+			Foreground(m.colors.warning).
+			Padding(0, 1).
+			Render("🔒 read-only")
+		notif = lipgloss.JoinHorizontal(lipgloss.Center, readOnly, notif)
+	}
+
+	if len(m.spawnQueue) > 0 {
+		queued := lipgloss.NewStyle().
+			Foreground(m.colors.info).
+			Padding(0, 1).
+			Render(fmt.Sprintf("⧗ %d queued", len(m.spawnQueue)))
+		notif = lipgloss.JoinHorizontal(lipgloss.Center, queued, notif)
+	}
+
 	left := lipgloss.JoinHorizontal(lipgloss.Center, modeStr, sep, hints)
 	spacing := m.width - lipgloss.Width(left) - lipgloss.Width(notif)
 	spacing = max(spacing, 0)
@@ -615,16 +857,62 @@ func (m *Model) renderStatusBar() string {
 
 func (m *Model) contextualHints(hintStyle lipgloss.Style, sep string) string {
 	switch m.mode {
+	case ModeCommand:
+		return m.commandInput.View()
+
+	case ModeBranchPreview:
+		return m.branchPreviewInput.View() + sep +
+			hintStyle.Render("Enter") + m.dimStyle().Render(" create") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" cancel")
+
 	case ModeFilter:
 		return hintStyle.Render("Enter") + m.dimStyle().Render(" apply") + sep +
 			hintStyle.Render("Esc") + m.dimStyle().Render(" cancel") + sep +
-			m.dimStyle().Render("@project to filter by project")
+			m.dimStyle().Render("@project, #label to narrow")
 
 	case ModeSettings:
 		return hintStyle.Render("j/k") + m.dimStyle().Render(" navigate") + sep +
 			hintStyle.Render("Enter") + m.dimStyle().Render(" select") + sep +
 			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
 
+	case ModeWorktrees:
+		return hintStyle.Render("j/k") + m.dimStyle().Render(" navigate") + sep +
+			hintStyle.Render("d") + m.dimStyle().Render(" remove") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeEffectiveConfig:
+		return hintStyle.Render("j/k") + m.dimStyle().Render(" scroll") + sep +
+			hintStyle.Render("e") + m.dimStyle().Render(" edit ticket") + sep +
+			hintStyle.Render("O") + m.dimStyle().Render(" settings") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeConfigHealth:
+		return hintStyle.Render("j/k") + m.dimStyle().Render(" scroll") + sep +
+			hintStyle.Render("r") + m.dimStyle().Render(" re-check") + sep +
+			hintStyle.Render("q") + m.dimStyle().Render(" settings") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeSpawnPrompt:
+		return hintStyle.Render("ctrl+s") + m.dimStyle().Render(" spawn") + sep +
+			hintStyle.Render("ctrl+r") + m.dimStyle().Render(" reset") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" cancel")
+
+	case ModeDashboard:
+		return hintStyle.Render("j/k") + m.dimStyle().Render(" navigate") + sep +
+			hintStyle.Render("Enter") + m.dimStyle().Render(" attach") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeAgentPicker:
+		return hintStyle.Render("j/k") + m.dimStyle().Render(" navigate") + sep +
+			hintStyle.Render("Enter") + m.dimStyle().Render(" select") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" cancel")
+
+	case ModeSavedFilters:
+		return hintStyle.Render("j/k") + m.dimStyle().Render(" navigate") + sep +
+			hintStyle.Render("Enter") + m.dimStyle().Render(" apply") + sep +
+			hintStyle.Render("n") + m.dimStyle().Render(" save current") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
 	case ModeCreateTicket, ModeEditTicket:
 		action := "create"
 		if m.mode == ModeEditTicket {
@@ -636,6 +924,7 @@ func (m *Model) contextualHints(hintStyle lipgloss.Style, sep string) string {
 
 	case ModeAgentView:
 		return hintStyle.Render("Ctrl+G") + m.dimStyle().Render(" back to board") + sep +
+			hintStyle.Render("Ctrl+]") + m.dimStyle().Render(" diff split") + sep +
 			m.dimStyle().Render("Shift+click to select text")
 
 	case ModeNormal:
@@ -645,7 +934,7 @@ func (m *Model) contextualHints(hintStyle lipgloss.Style, sep string) string {
 				hintStyle.Render("l") + m.dimStyle().Render(" board")
 		}
 
-		if m.filterQuery != "" || len(m.filterProjectIDs) > 0 {
+		if m.hasActiveFilter() {
 			return hintStyle.Render("Esc") + m.dimStyle().Render(" clear filter") + sep +
 				hintStyle.Render("/") + m.dimStyle().Render(" edit filter") + sep +
 				hintStyle.Render("?") + m.dimStyle().Render(" help")
@@ -704,25 +993,45 @@ func (m *Model) renderHelp() string {
 		sep + "\n" +
 		sectionStyle.Render("  🧭 Navigation") + "                 " + sectionStyle.Render("📝 Actions") + "\n" +
 		sep + "\n" +
-		"  " + keyStyle.Render("h/l") + descStyle.Render("   Move between columns  ") + keyStyle.Render("n") + descStyle.Render("       New ticket") + "\n" +
-		"  " + keyStyle.Render("j/k") + descStyle.Render("   Move between tickets  ") + keyStyle.Render("e") + descStyle.Render("       Edit ticket") + "\n" +
-		"  " + keyStyle.Render("g") + descStyle.Render("     Go to first ticket    ") + keyStyle.Render("d") + descStyle.Render("       Delete ticket") + "\n" +
-		"  " + keyStyle.Render("G") + descStyle.Render("     Go to last ticket     ") + keyStyle.Render("Space") + descStyle.Render("   Move forward") + "\n" +
-		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render("-") + descStyle.Render("       Move backward") + "\n\n" +
+		"  " + keyStyle.Render(m.helpKey("h/l", "left", "right")) + descStyle.Render("   Move between columns  ") + keyStyle.Render(m.helpKey("n", "new")) + descStyle.Render("       New ticket") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("j/k", "down", "up")) + descStyle.Render("   Move between tickets  ") + keyStyle.Render(m.helpKey("e", "edit")) + descStyle.Render("       Edit ticket") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("g", "first")) + descStyle.Render("     Go to first ticket    ") + keyStyle.Render(m.helpKey("d", "delete")) + descStyle.Render("       Delete ticket") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("G", "last")) + descStyle.Render("     Go to last ticket     ") + keyStyle.Render(m.helpKey("Space", "move_forward")) + descStyle.Render("   Move forward") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("^d/^u", "half_page_down", "half_page_up")) + descStyle.Render(" Half page down/up     ") + keyStyle.Render(m.helpKey("-", "move_backward")) + descStyle.Render("       Move backward") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("A", "archive")) + descStyle.Render("       Archive/restore") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("+/_", "priority_up", "priority_down")) + descStyle.Render("     Raise/lower priority") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("J/K", "reorder_down", "reorder_up")) + descStyle.Render("     Reorder in column") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("m", "merge")) + descStyle.Render("       Merge into base") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("P", "pull_request")) + descStyle.Render("       Open pull request") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("u", "undo")) + descStyle.Render("       Undo move/delete") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("v", "mark")) + descStyle.Render("       Mark for batch action") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("z", "snooze")) + descStyle.Render("       Snooze/unsnooze") + "\n\n" +
 		sep + "\n" +
 		sectionStyle.Render("  📂 Sidebar") + "                    " + sectionStyle.Render("🤖 Agent") + "\n" +
 		sep + "\n" +
-		"  " + keyStyle.Render("[") + descStyle.Render("     Toggle sidebar        ") + keyStyle.Render("s") + descStyle.Render("       Spawn agent") + "\n" +
-		"  " + keyStyle.Render("h") + descStyle.Render("     Enter sidebar         ") + keyStyle.Render("S") + descStyle.Render("       Stop agent") + "\n" +
-		"  " + keyStyle.Render("l") + descStyle.Render("     Exit sidebar          ") + keyStyle.Render("Enter") + descStyle.Render("   Attach to agent") + "\n" +
-		"  " + keyStyle.Render("j/k") + descStyle.Render("   Navigate projects     ") + keyStyle.Render("Ctrl+g") + descStyle.Render("  Exit agent view") + "\n\n" +
+		"  " + keyStyle.Render(m.helpKey("[", "sidebar_toggle")) + descStyle.Render("     Toggle sidebar        ") + keyStyle.Render(m.helpKey("s", "spawn")) + descStyle.Render("       Spawn agent") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("h", "left")) + descStyle.Render("     Enter sidebar         ") + keyStyle.Render(m.helpKey("S", "stop")) + descStyle.Render("       Stop agent") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("l", "right")) + descStyle.Render("     Exit sidebar          ") + keyStyle.Render(m.helpKey("Enter", "attach")) + descStyle.Render("   Attach to agent") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("j/k", "down", "up")) + descStyle.Render("   Navigate projects     ") + keyStyle.Render("Ctrl+g") + descStyle.Render("  Exit agent view") + "\n" +
+		"  " + keyStyle.Render("/") + descStyle.Render("     Filter projects       ") + keyStyle.Render(m.helpKey("p", "pause")) + descStyle.Render("       Pause output") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("a", "agent_type")) + descStyle.Render("       Pick agent type") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render("Ctrl+]") + descStyle.Render("  Toggle diff split") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("Ctrl+s", "spawn_all")) + descStyle.Render("  Spawn all In Progress") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("c", "quick_agent")) + descStyle.Render("       Quick question") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("R", "respawn")) + descStyle.Render("       Re-run last command") + "\n" +
+		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render(m.helpKey("Ctrl+r", "restart")) + descStyle.Render("  Restart agent fresh") + "\n\n" +
 		sep + "\n" +
 		sectionStyle.Render("  👁 View") + "\n" +
 		sep + "\n" +
-		"  " + keyStyle.Render("/") + descStyle.Render("     Search/filter         ") + keyStyle.Render("O") + descStyle.Render("       Settings") + "\n" +
-		"  " + keyStyle.Render("?") + descStyle.Render("     Toggle help           ") + keyStyle.Render("q") + descStyle.Render("       Quit") + "\n\n" +
+		"  " + keyStyle.Render(m.helpKey("/", "filter")) + descStyle.Render("     Search/filter         ") + keyStyle.Render(m.helpKey("O", "settings")) + descStyle.Render("       Settings") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("V", "group_by_project")) + descStyle.Render("     Group by project      ") + keyStyle.Render(m.helpKey(":", "command")) + descStyle.Render("       Command") + "\n" +
+		"  " + keyStyle.Render("?") + descStyle.Render("     Toggle help           ") + keyStyle.Render(fmt.Sprintf("%-8s", m.quitKeyLabel())) + descStyle.Render("Quit") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("W", "worktrees")) + descStyle.Render("     Project worktrees     ") + keyStyle.Render(m.helpKey("i", "status_detail")) + descStyle.Render("       Status detail") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("y", "copy_path")) + descStyle.Render("     Copy worktree path    ") + keyStyle.Render(m.helpKey("o", "editor")) + descStyle.Render("       Open in editor") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("F", "saved_filters")) + descStyle.Render("     Saved filters         ") + keyStyle.Render(m.helpKey("C", "effective_config")) + descStyle.Render("       Effective config") + "\n" +
+		"  " + keyStyle.Render(m.helpKey("D", "dashboard")) + descStyle.Render("     Agent dashboard") + "\n\n" +
 		sep + "\n" +
-		"  " + lipgloss.NewStyle().Foreground(m.colors.warning).Render("💡") + m.dimStyle().Render(" Tip: Hold Shift to select text in agent view") + "\n\n" +
+		"  " + lipgloss.NewStyle().Foreground(m.colors.warning).Render("💡") + m.dimStyle().Render(" Tip: ctrl+y in agent view copies the visible output") + "\n\n" +
 		"  " + m.dimStyle().Render("Press any key to close")
 
 	return lipgloss.NewStyle().
@@ -732,15 +1041,56 @@ func (m *Model) renderHelp() string {
 		Render(help)
 }
 
+// helpKey returns the key label shown in help for actions: label while
+// they keep their default keys, else each action's first configured key
+// joined with "/", padded to label's width to keep the columns aligned.
+func (m *Model) helpKey(label string, actions ...string) string {
+	overridden := false
+	for _, action := range actions {
+		if _, ok := m.config.Keys[action]; ok {
+			overridden = true
+		}
+	}
+	if !overridden {
+		return label
+	}
+	actionKeys := m.config.ActionKeys()
+	parts := make([]string, len(actions))
+	for i, action := range actions {
+		parts[i] = "-"
+		if keys := actionKeys[action]; len(keys) > 0 {
+			parts[i] = config.KeyLabel(keys[0])
+		}
+	}
+	return fmt.Sprintf("%-*s", len(label), strings.Join(parts, "/"))
+}
+
+// quitKeyLabel is the key shown for quitting: behavior.quit_key, or ctrl+c
+// when single-key quit is off.
+func (m *Model) quitKeyLabel() string {
+	if m.config.Behavior.QuitKey == "" {
+		return "ctrl+c"
+	}
+	return m.config.Behavior.QuitKey
+}
+
 func (m *Model) renderConfirmDialog() string {
 	titleStyle := lipgloss.NewStyle().
 		Foreground(m.colors.err).
 		Bold(true)
 
+	yesLabel := m.dimStyle().Render(" Yes ")
+	noLabel := m.dimStyle().Render(" No ")
+	if m.confirmYes {
+		yesLabel = lipgloss.NewStyle().Foreground(m.colors.base).Background(m.colors.success).Bold(true).Render(" Yes ")
+	} else {
+		noLabel = lipgloss.NewStyle().Foreground(m.colors.base).Background(m.colors.err).Bold(true).Render(" No ")
+	}
+
 	content := titleStyle.Render("⚠ Confirm") + "\n\n" +
 		"  " + lipgloss.NewStyle().Foreground(m.colors.text).Render(m.confirmMsg) + "\n\n" +
-		"  " + lipgloss.NewStyle().Foreground(m.colors.success).Render("[y]") + m.dimStyle().Render(" Yes    ") +
-		lipgloss.NewStyle().Foreground(m.colors.err).Render("[n]") + m.dimStyle().Render(" No    ") +
+		"  " + lipgloss.NewStyle().Foreground(m.colors.success).Render("[y]") + yesLabel + "   " +
+		lipgloss.NewStyle().Foreground(m.colors.err).Render("[n]") + noLabel + "   " +
 		lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]") + m.dimStyle().Render(" Cancel")
 
 	return lipgloss.NewStyle().
@@ -750,6 +1100,37 @@ func (m *Model) renderConfirmDialog() string {
 		Render(content)
 }
 
+func (m *Model) renderRemoveProjectDialog() string {
+	p := m.removingProject
+	titleStyle := lipgloss.NewStyle().
+		Foreground(m.colors.err).
+		Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(m.colors.text)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.primary)
+
+	count := len(m.globalStore.ProjectTickets(p.ID))
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("⚠ Delete project '"+p.Name+"'") + "\n\n")
+	b.WriteString("  " + textStyle.Render(fmt.Sprintf("It has %d ticket(s). What should happen to them?", count)) + "\n\n")
+	b.WriteString("  " + keyStyle.Render("[a]") + textStyle.Render(" Archive") + m.dimStyle().Render(" (kept in tickets/archived)") + "\n")
+	b.WriteString("  " + keyStyle.Render("[d]") + textStyle.Render(" Delete permanently") + "\n")
+	if targets := m.removeTargets(); len(targets) > 0 {
+		target := targets[m.removeTargetIndex%len(targets)]
+		b.WriteString("  " + keyStyle.Render("[r]") + textStyle.Render(" Reassign to "+target.Name))
+		if len(targets) > 1 {
+			b.WriteString(m.dimStyle().Render("  (Tab: next project)"))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n  " + lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]") + m.dimStyle().Render(" Cancel"))
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.err).
+		Padding(1, 2).
+		Render(b.String())
+}
+
 func (m *Model) renderShuttingDown() string {
 	count := m.RunningAgentCount()
 	msg := fmt.Sprintf("Stopping %d agent(s)...", count)
@@ -835,12 +1216,17 @@ func (m *Model) renderTicketForm() string {
 	titleLabel := labelStyle
 	descLabel := labelStyle
 	branchLabel := labelStyle
+	baseBranchLabel := labelStyle
 	labelsLabel := labelStyle
 	priorityLabel := labelStyle
+	dueLabel := labelStyle
 	worktreeLabel := labelStyle
 	agentLabel := labelStyle
+	agentArgsLabel := labelStyle
+	contextFilesLabel := labelStyle
 	blockerLabel := labelStyle
 	projectLabel := labelStyle
+	worktreePathLabel := labelStyle
 
 	fieldStartLines := make(map[int]int)
 	currentLine := 0
@@ -852,29 +1238,55 @@ func (m *Model) renderTicketForm() string {
 		descLabel = activeLabelStyle
 	case formFieldBranch:
 		branchLabel = activeLabelStyle
+	case formFieldBaseBranch:
+		baseBranchLabel = activeLabelStyle
 	case formFieldLabels:
 		labelsLabel = activeLabelStyle
 	case formFieldPriority:
 		priorityLabel = activeLabelStyle
+	case formFieldDueDate:
+		dueLabel = activeLabelStyle
 	case formFieldWorktree:
 		worktreeLabel = activeLabelStyle
 	case formFieldAgent:
 		agentLabel = activeLabelStyle
+	case formFieldAgentArgs:
+		agentArgsLabel = activeLabelStyle
+	case formFieldContextFiles:
+		contextFilesLabel = activeLabelStyle
 	case formFieldBlockedBy:
 		blockerLabel = activeLabelStyle
 	case formFieldProject:
 		projectLabel = activeLabelStyle
+	case formFieldWorktreePath:
+		worktreePathLabel = activeLabelStyle
 	}
 
 	var branchField string
 	var branchDesc string
+	var baseBranchField string
+	var baseBranchDesc string
 	if m.branchLocked {
 		branchLabel = lockedStyle
 		branchField = lockedStyle.Render(m.branchInput.Value() + " (locked)")
 		branchDesc = descriptionStyle.Render("Branch is locked after worktree creation")
+		baseBranchLabel = lockedStyle
+		baseBranchField = lockedStyle.Render(m.baseBranchInput.Value() + " (locked)")
+		baseBranchDesc = descriptionStyle.Render("Base is locked after worktree creation")
+	} else if m.linkedBranch {
+		branchField = m.branchInput.View()
+		branchDesc = descriptionStyle.Render("Existing branch, checked out in a worktree on save (ctrl+b for next)")
+		baseBranchLabel = lockedStyle
+		baseBranchField = lockedStyle.Render("(not used for an existing branch)")
+		baseBranchDesc = descriptionStyle.Render("The existing branch keeps its own history")
 	} else {
 		branchField = m.branchInput.View()
 		branchDesc = descriptionStyle.Render("Auto-generated from title if left empty")
+		if m.mode == ModeCreateTicket {
+			branchDesc = descriptionStyle.Render("Auto-generated from title if left empty; ctrl+b picks an existing branch")
+		}
+		baseBranchField = m.baseBranchInput.View()
+		baseBranchDesc = descriptionStyle.Render("Branch to start from (defaults to the checked-out branch)")
 	}
 
 	priorityField := m.renderPrioritySelector()
@@ -895,7 +1307,7 @@ func (m *Model) renderTicketForm() string {
 	focusIndicator := lipgloss.NewStyle().Foreground(m.colors.info).Render("▸ ")
 	noFocus := "  "
 
-	titleFocus, descFocus, branchFocus, labelsFocus, priorityFocus, worktreeFocus, agentFocus, blockerFocus, projectFocus := noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus
+	titleFocus, descFocus, branchFocus, baseBranchFocus, labelsFocus, priorityFocus, dueFocus, worktreeFocus, agentFocus, agentArgsFocus, contextFilesFocus, blockerFocus, projectFocus := noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus
 	switch m.ticketFormField {
 	case formFieldTitle:
 		titleFocus = focusIndicator
@@ -903,14 +1315,22 @@ func (m *Model) renderTicketForm() string {
 		descFocus = focusIndicator
 	case formFieldBranch:
 		branchFocus = focusIndicator
+	case formFieldBaseBranch:
+		baseBranchFocus = focusIndicator
 	case formFieldLabels:
 		labelsFocus = focusIndicator
 	case formFieldPriority:
 		priorityFocus = focusIndicator
+	case formFieldDueDate:
+		dueFocus = focusIndicator
 	case formFieldWorktree:
 		worktreeFocus = focusIndicator
 	case formFieldAgent:
 		agentFocus = focusIndicator
+	case formFieldAgentArgs:
+		agentArgsFocus = focusIndicator
+	case formFieldContextFiles:
+		contextFilesFocus = focusIndicator
 	case formFieldBlockedBy:
 		blockerFocus = focusIndicator
 	case formFieldProject:
@@ -929,8 +1349,15 @@ func (m *Model) renderTicketForm() string {
 	currentLine = len(lines)
 
 	fieldStartLines[formFieldDescription] = currentLine
+	descHint := "Details, context, or acceptance criteria"
+	if m.mode == ModeCreateTicket && len(m.config.Defaults.TicketTemplates) > 0 {
+		descHint += "; ctrl+t applies a template"
+		if name := m.ticketTemplateName(); name != "" {
+			descHint = "Template: " + name + " (ctrl+t for next)"
+		}
+	}
 	lines = append(lines, descFocus+descLabel.Render("Description"))
-	lines = append(lines, "  "+descriptionStyle.Render("Details, context, or acceptance criteria"))
+	lines = append(lines, "  "+descriptionStyle.Render(descHint))
 	descLines := strings.Split(m.descInput.View(), "\n")
 	for _, dl := range descLines {
 		lines = append(lines, "  "+dl)
@@ -947,6 +1374,14 @@ func (m *Model) renderTicketForm() string {
 	fieldEndLines[formFieldBranch] = len(lines) - 1
 	currentLine = len(lines)
 
+	fieldStartLines[formFieldBaseBranch] = currentLine
+	lines = append(lines, baseBranchFocus+baseBranchLabel.Render("Base Branch"))
+	lines = append(lines, "  "+baseBranchDesc)
+	lines = append(lines, "  "+baseBranchField)
+	lines = append(lines, "")
+	fieldEndLines[formFieldBaseBranch] = len(lines) - 1
+	currentLine = len(lines)
+
 	fieldStartLines[formFieldLabels] = currentLine
 	lines = append(lines, labelsFocus+labelsLabel.Render("Labels"))
 	lines = append(lines, "  "+descriptionStyle.Render("Comma-separated tags (e.g. bug, urgent)"))
@@ -963,6 +1398,14 @@ func (m *Model) renderTicketForm() string {
 	fieldEndLines[formFieldPriority] = len(lines) - 1
 	currentLine = len(lines)
 
+	fieldStartLines[formFieldDueDate] = currentLine
+	lines = append(lines, dueFocus+dueLabel.Render("Due"))
+	lines = append(lines, "  "+descriptionStyle.Render("Date (YYYY-MM-DD) or offset (+3d, +1w, +12h)"))
+	lines = append(lines, "  "+m.dueInput.View())
+	lines = append(lines, "")
+	fieldEndLines[formFieldDueDate] = len(lines) - 1
+	currentLine = len(lines)
+
 	fieldStartLines[formFieldWorktree] = currentLine
 	lines = append(lines, worktreeFocus+worktreeLabel.Render("Worktree"))
 	lines = append(lines, "  "+descriptionStyle.Render("Use isolated worktree or work in main repo"))
@@ -979,6 +1422,22 @@ func (m *Model) renderTicketForm() string {
 	fieldEndLines[formFieldAgent] = len(lines) - 1
 	currentLine = len(lines)
 
+	fieldStartLines[formFieldAgentArgs] = currentLine
+	lines = append(lines, agentArgsFocus+agentArgsLabel.Render("Agent Args"))
+	lines = append(lines, "  "+descriptionStyle.Render("Extra args appended to the agent command"))
+	lines = append(lines, "  "+m.agentArgsInput.View())
+	lines = append(lines, "")
+	fieldEndLines[formFieldAgentArgs] = len(lines) - 1
+	currentLine = len(lines)
+
+	fieldStartLines[formFieldContextFiles] = currentLine
+	lines = append(lines, contextFilesFocus+contextFilesLabel.Render("Context Files"))
+	lines = append(lines, "  "+descriptionStyle.Render("Comma-separated repo paths listed in the agent's prompt"))
+	lines = append(lines, "  "+m.contextFilesInput.View())
+	lines = append(lines, "")
+	fieldEndLines[formFieldContextFiles] = len(lines) - 1
+	currentLine = len(lines)
+
 	fieldStartLines[formFieldBlockedBy] = currentLine
 	lines = append(lines, blockerFocus+blockerLabel.Render("Blocked By"))
 	lines = append(lines, "  "+descriptionStyle.Render("Tickets that must complete before this one"))
@@ -989,12 +1448,16 @@ func (m *Model) renderTicketForm() string {
 	fieldEndLines[formFieldBlockedBy] = len(lines) - 1
 	currentLine = len(lines)
 
-	if !isEdit {
+	if m.showProjectField(isEdit) {
+		projectHint := "Repository where this ticket belongs"
+		if isEdit {
+			projectHint = "Original project is gone - pick a new home for this ticket"
+		}
 		lines = append(lines, "")
 		currentLine = len(lines)
 		fieldStartLines[formFieldProject] = currentLine
 		lines = append(lines, projectFocus+projectLabel.Render("Project"))
-		lines = append(lines, "  "+descriptionStyle.Render("Repository where this ticket belongs"))
+		lines = append(lines, "  "+descriptionStyle.Render(projectHint))
 		projectLines := strings.Split(projectField, "\n")
 		for _, pl := range projectLines {
 			lines = append(lines, "  "+pl)
@@ -1002,6 +1465,32 @@ func (m *Model) renderTicketForm() string {
 		fieldEndLines[formFieldProject] = len(lines) - 1
 	}
 
+	if isEdit {
+		worktreePathFocus := noFocus
+		if m.ticketFormField == formFieldWorktreePath {
+			worktreePathFocus = focusIndicator
+		}
+		worktreePathField := m.worktreePathInput.View()
+		worktreePathDesc := "Attach an existing worktree of this project (locks branch)"
+		if m.worktreePathLocked {
+			worktreePathLabel = lockedStyle
+			worktreePathField = lockedStyle.Render(m.worktreePathInput.Value() + " (locked)")
+			worktreePathDesc = "Stop the agent before changing its worktree"
+			if m.reassignProject {
+				worktreePathDesc = "Reassign the ticket before attaching a worktree"
+			}
+		}
+
+		lines = append(lines, "")
+		lines = append(lines, "  "+m.dimStyle().Render("── Advanced ──"))
+		currentLine = len(lines)
+		fieldStartLines[formFieldWorktreePath] = currentLine
+		lines = append(lines, worktreePathFocus+worktreePathLabel.Render("Worktree Path"))
+		lines = append(lines, "  "+descriptionStyle.Render(worktreePathDesc))
+		lines = append(lines, "  "+worktreePathField)
+		fieldEndLines[formFieldWorktreePath] = len(lines) - 1
+	}
+
 	m.formFieldLines = fieldStartLines
 
 	viewportHeight := m.formViewportHeight()
@@ -1174,6 +1663,20 @@ func (m *Model) renderAgentSelector() string {
 	return strings.Join(parts, "  ") + hint
 }
 
+// renderBlockerStatus renders a blocking ticket's title with its column, so
+// it's clear whether the dependency is still open.
+func (m *Model) renderBlockerStatus(t *board.Ticket) string {
+	name := t.Title
+	if len(name) > 20 {
+		name = name[:18] + ".."
+	}
+	if t.Status == board.StatusDone || t.Status == board.StatusArchived {
+		return lipgloss.NewStyle().Foreground(m.colors.success).Render("✓ " + name)
+	}
+	return lipgloss.NewStyle().Foreground(m.colors.warning).Render("○ "+name) +
+		m.dimStyle().Render(" ("+m.statusColumnName(t.Status)+")")
+}
+
 func (m *Model) renderBlockerSelector() string {
 	if len(m.blockerCandidates) == 0 {
 		return m.dimStyle().Render("No other tickets available")
@@ -1184,18 +1687,20 @@ func (m *Model) renderBlockerSelector() string {
 		if count == 0 {
 			return m.dimStyle().Render("None selected")
 		}
-		var names []string
+		var blockers []*board.Ticket
 		for id := range m.selectedBlockers {
 			if t, _ := m.globalStore.Get(id); t != nil {
-				name := t.Title
-				if len(name) > 20 {
-					name = name[:18] + ".."
-				}
-				names = append(names, name)
+				blockers = append(blockers, t)
 			}
 		}
-		sort.Strings(names)
-		return lipgloss.NewStyle().Foreground(m.colors.info).Render(strings.Join(names, ", "))
+		sort.Slice(blockers, func(i, j int) bool {
+			return blockers[i].Title < blockers[j].Title
+		})
+		var lines []string
+		for _, t := range blockers {
+			lines = append(lines, m.renderBlockerStatus(t))
+		}
+		return strings.Join(lines, "\n")
 	}
 
 	var lines []string
@@ -1312,7 +1817,11 @@ func (m *Model) renderSettingsView() string {
 			vStyle = lipgloss.NewStyle().Foreground(m.colors.info)
 		}
 
-		line := cursor + lStyle.Render(fmt.Sprintf("%-18s", label)) + " " + vStyle.Render(value)
+		renderedValue := vStyle.Render(value)
+		if i == m.settingsIndex && m.settingsEditing && (field.kind == "text" || field.kind == "number") {
+			renderedValue = m.settingsInput.View()
+		}
+		line := cursor + lStyle.Render(fmt.Sprintf("%-18s", label)) + " " + renderedValue
 		lines = append(lines, line)
 		lines = append(lines, "    "+descStyle.Render(field.description))
 
@@ -1333,8 +1842,13 @@ func (m *Model) renderSettingsView() string {
 		actionHint = "Toggle"
 	case "project", "theme":
 		actionHint = "Select"
+	case "view":
+		actionHint = "Open"
 	default:
 		actionHint = "Edit"
+		if m.settingsEditing {
+			actionHint = "Save"
+		}
 	}
 
 	lines = append(lines, "  "+lipgloss.NewStyle().Foreground(m.colors.info).Render("[Enter]")+m.dimStyle().Render(" "+actionHint+"  ")+
@@ -1349,65 +1863,594 @@ func (m *Model) renderSettingsView() string {
 		Render(content)
 }
 
-func (m *Model) renderAgentView() string {
-	pane, ok := m.panes[m.focusedPane]
-	if !ok {
-		return "No pane focused"
-	}
-
-	var b strings.Builder
+func (m *Model) renderWorktreesView() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(m.colors.info).
+		Bold(true)
 
-	ticket, _ := m.globalStore.Get(m.focusedPane)
-	title := "Agent"
-	agentType := ""
-	projectName := ""
-	var sessionDuration string
-	if ticket != nil {
-		title = ticket.Title
-		agentType = ticket.AgentType
-		if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
-			projectName = proj.Name
-		}
-		if ticket.AgentSpawnedAt != nil {
-			duration := time.Since(*ticket.AgentSpawnedAt)
-			sessionDuration = formatDuration(duration)
-		}
-	}
+	pathStyle := lipgloss.NewStyle().
+		Foreground(m.colors.text)
 
-	breadcrumbStyle := lipgloss.NewStyle().Foreground(m.colors.muted)
-	titleStyle := lipgloss.NewStyle().
-		Foreground(m.colors.primary).
+	selectedPathStyle := lipgloss.NewStyle().
+		Foreground(m.colors.info).
 		Bold(true)
 
-	header := breadcrumbStyle.Render("Board → ") + titleStyle.Render(title)
+	descStyle := lipgloss.NewStyle().
+		Foreground(m.colors.subtext)
 
-	if projectName != "" {
-		projBadge := lipgloss.NewStyle().
-			Foreground(m.colors.base).
-			Background(m.colors.info).
-			Padding(0, 1).
-			Render(projectName)
-		header = header + "  " + projBadge
+	var lines []string
+	lines = append(lines, titleStyle.Render("⎇ Worktrees — "+m.worktreeProject.Name))
+	lines = append(lines, "")
+
+	switch {
+	case m.worktreeErr != nil:
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.colors.err).Render("  "+m.worktreeErr.Error()))
+		lines = append(lines, "")
+	case m.worktreeLoading && len(m.worktreeRows) == 0:
+		lines = append(lines, "  "+m.spinner.View()+m.dimStyle().Render(" Loading worktrees…"))
+		lines = append(lines, "")
 	}
 
-	if agentType != "" {
-		agentBadge := lipgloss.NewStyle().
-			Foreground(m.colors.base).
-			Background(m.colors.primary).
-			Padding(0, 1).
-			Render(agentType)
-		header = header + "  " + agentBadge
+	orphans := 0
+	for i, row := range m.worktreeRows {
+		cursor := "  "
+		pStyle := pathStyle
+		if i == m.worktreeIndex {
+			cursor = lipgloss.NewStyle().Foreground(m.colors.info).Render("▸ ")
+			pStyle = selectedPathStyle
+		}
+		lines = append(lines, cursor+pStyle.Render(row.path))
+
+		branch := row.branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		details := []string{descStyle.Render("⎇ " + branch)}
+
+		switch {
+		case row.main:
+			details = append(details, m.dimStyle().Render("main checkout"))
+		case row.ticketID != "":
+			title := string(row.ticketID)
+			if ticket, _ := m.globalStore.Get(row.ticketID); ticket != nil {
+				title = ticket.Title
+			}
+			if len(title) > 40 {
+				title = title[:37] + "..."
+			}
+			details = append(details, descStyle.Render("▪ "+title))
+		default:
+			orphans++
+			details = append(details, lipgloss.NewStyle().Foreground(m.colors.warning).Bold(true).Render("orphaned"))
+		}
+
+		if row.dirty {
+			details = append(details, lipgloss.NewStyle().Foreground(m.colors.warning).Render("● dirty"))
+		}
+		if row.driftKnown {
+			if row.ahead > 0 {
+				details = append(details, lipgloss.NewStyle().Foreground(m.colors.success).Render(fmt.Sprintf("↑%d", row.ahead)))
+			}
+			if row.behind > 0 {
+				details = append(details, lipgloss.NewStyle().Foreground(m.colors.warning).Render(fmt.Sprintf("↓%d", row.behind)))
+			}
+		}
+		lines = append(lines, "    "+strings.Join(details, "  "))
+		lines = append(lines, "")
 	}
 
-	if sessionDuration != "" {
-		durationBadge := lipgloss.NewStyle().
-			Foreground(m.colors.muted).
-			Render("⏱ " + sessionDuration)
-		header = header + "  " + durationBadge
+	if !m.worktreeLoading && m.worktreeErr == nil {
+		summary := fmt.Sprintf("%d %s", len(m.worktreeRows), pluralize(len(m.worktreeRows), "worktree", "worktrees"))
+		if orphans > 0 {
+			summary += fmt.Sprintf(", %d orphaned", orphans)
+		}
+		lines = append(lines, m.dimStyle().Render("  "+summary))
+		lines = append(lines, "")
 	}
 
-	var depsLine string
-	if ticket != nil {
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info)
+	lines = append(lines, "  "+keyStyle.Render("[Enter]")+m.dimStyle().Render(" Go to ticket  ")+
+		keyStyle.Render("[o]")+m.dimStyle().Render(" Open  ")+
+		keyStyle.Render("[d]")+m.dimStyle().Render(" Remove  ")+
+		keyStyle.Render("[r]")+m.dimStyle().Render(" Refresh  ")+
+		lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]")+m.dimStyle().Render(" Close"))
+
+	content := strings.Join(lines, "\n")
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.colors.info).
+		Padding(1, 2).
+		Render(content)
+}
+
+func (m *Model) renderAgentPickerView() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(m.colors.secondary).
+		Bold(true)
+
+	ticket := m.selectedTicket()
+	var lines []string
+	lines = append(lines, titleStyle.Render("▶ Agent Type"))
+	if ticket != nil {
+		lines = append(lines, m.dimStyle().MaxWidth(50).Render(ticket.Title))
+	}
+	lines = append(lines, "")
+
+	for i, name := range m.getAgentNames() {
+		cursor := "  "
+		nameStyle := lipgloss.NewStyle().Foreground(m.colors.text)
+		if i == m.agentPickerIndex {
+			cursor = lipgloss.NewStyle().Foreground(m.colors.info).Render("▸ ")
+			nameStyle = lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+		}
+		line := cursor + nameStyle.Render(name)
+		if ticket != nil {
+			switch name {
+			case ticket.AgentOverride:
+				line += "  " + lipgloss.NewStyle().Foreground(m.colors.warning).Render("● next spawn")
+			case ticket.AgentType:
+				line += "  " + lipgloss.NewStyle().Foreground(m.colors.success).Render("● current")
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	if ticket != nil && ticket.AgentSpawnedAt != nil {
+		lines = append(lines, "")
+		lines = append(lines, m.dimStyle().Render("Switching starts a new session on the next spawn;"))
+		lines = append(lines, m.dimStyle().Render("the worktree and branch are kept."))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.colors.secondary).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+func (m *Model) renderConfigHealthView() string {
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.warning).Bold(true)
+	fieldStyle := lipgloss.NewStyle().Foreground(m.colors.subtext)
+	messageStyle := lipgloss.NewStyle().Foreground(m.colors.text)
+	width := max(min(m.width-16, 90), 30)
+
+	issue := func(marker string, e config.ValidationError) []string {
+		name := e.Section
+		if e.Field != "" {
+			name += "." + e.Field
+		}
+		lines := []string{marker + " " + fieldStyle.Render(name)}
+		wrapped := lipgloss.NewStyle().Width(width - 4).Render(messageStyle.Render(e.Message))
+		for _, line := range strings.Split(wrapped, "\n") {
+			lines = append(lines, "    "+line)
+		}
+		if e.Value != nil {
+			lines = append(lines, "    "+m.dimStyle().MaxWidth(width-4).Render(fmt.Sprintf("got: %v", e.Value)))
+		}
+		return lines
+	}
+
+	var body []string
+	result := m.configHealth
+	switch {
+	case result == nil:
+		body = append(body, m.dimStyle().Render("The config has not been validated."))
+	case !result.HasErrors() && !result.HasWarnings():
+		body = append(body, lipgloss.NewStyle().Foreground(m.colors.success).Render("✓ No errors or warnings"))
+	default:
+		errMarker := lipgloss.NewStyle().Foreground(m.colors.err).Render("✗")
+		for _, e := range result.Errors {
+			body = append(body, issue(errMarker, e)...)
+		}
+		warnMarker := lipgloss.NewStyle().Foreground(m.colors.warning).Render("!")
+		for _, w := range result.Warnings {
+			body = append(body, issue(warnMarker, w)...)
+		}
+	}
+
+	visible := max(m.height-14, 5)
+	m.configHealthScroll = min(m.configHealthScroll, max(len(body)-visible, 0))
+	end := min(m.configHealthScroll+visible, len(body))
+
+	path := m.configPath
+	if path == "" {
+		path = "default config (no file)"
+	}
+
+	var lines []string
+	lines = append(lines, titleStyle.Render("⚙ Config Health")+"  "+m.dimStyle().Render(configHealthSummary(result)))
+	lines = append(lines, m.dimStyle().MaxWidth(width).Render(path))
+	lines = append(lines, "")
+	lines = append(lines, body[m.configHealthScroll:end]...)
+	if len(body) > visible {
+		lines = append(lines, "", m.dimStyle().Render(fmt.Sprintf("lines %d-%d of %d", m.configHealthScroll+1, end, len(body))))
+	}
+
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info)
+	lines = append(lines, "")
+	lines = append(lines, keyStyle.Render("[r]")+m.dimStyle().Render(" Re-check file  ")+
+		keyStyle.Render("[q]")+m.dimStyle().Render(" Settings  ")+
+		lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]")+m.dimStyle().Render(" Close"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.colors.warning).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+func (m *Model) renderSpawnPromptView() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(m.colors.warning).
+		Bold(true)
+
+	var lines []string
+	lines = append(lines, titleStyle.Render("▶ Init Prompt"))
+	if target := m.pendingSpawnPrompt; target != nil {
+		lines = append(lines, m.dimStyle().MaxWidth(60).Render(target.agentType+" · "+target.ticket.Title))
+	}
+	lines = append(lines, "")
+	lines = append(lines, m.spawnPromptInput.View())
+	lines = append(lines, "")
+	lines = append(lines, m.dimStyle().Render("ctrl+s spawns with this prompt · ctrl+r resets it · esc cancels"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.colors.warning).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+func (m *Model) renderSavedFiltersView() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(m.colors.warning).
+		Bold(true)
+
+	nameStyle := lipgloss.NewStyle().
+		Foreground(m.colors.text)
+
+	selectedNameStyle := lipgloss.NewStyle().
+		Foreground(m.colors.info).
+		Bold(true)
+
+	descStyle := lipgloss.NewStyle().
+		Foreground(m.colors.subtext)
+
+	var lines []string
+	lines = append(lines, titleStyle.Render("★ Saved Filters"))
+	lines = append(lines, "")
+
+	filters := m.filterRegistry.List()
+	if len(filters) == 0 {
+		lines = append(lines, m.dimStyle().Render("  No saved filters yet. Filter the board, then press n here to save it."))
+		lines = append(lines, "")
+	}
+
+	for i, f := range filters {
+		cursor := "  "
+		nStyle := nameStyle
+		if i == m.savedFilterIndex {
+			cursor = lipgloss.NewStyle().Foreground(m.colors.info).Render("▸ ")
+			nStyle = selectedNameStyle
+		}
+		name := nStyle.Render(f.Name)
+		if f.IsDefault {
+			name += "  " + lipgloss.NewStyle().Foreground(m.colors.warning).Render("★ default")
+		}
+		if m.savedFilter != nil && m.savedFilter.ID == f.ID {
+			name += "  " + lipgloss.NewStyle().Foreground(m.colors.success).Render("● applied")
+		}
+		lines = append(lines, cursor+name)
+
+		var details []string
+		for _, id := range f.ProjectIDs {
+			if p := m.globalStore.GetProject(id); p != nil {
+				details = append(details, "@"+p.Name)
+			}
+		}
+		if len(f.Statuses) > 0 {
+			details = append(details, "status: "+strings.Join(f.Statuses, ", "))
+		}
+		if len(f.Labels) > 0 {
+			details = append(details, "labels: "+strings.Join(f.Labels, ", "))
+		}
+		if f.Query != "" {
+			details = append(details, fmt.Sprintf("%q", f.Query))
+		}
+		if len(details) == 0 {
+			details = append(details, "all tickets")
+		}
+		lines = append(lines, "    "+descStyle.Render(strings.Join(details, "  ")))
+		lines = append(lines, "")
+	}
+
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info)
+	if m.filterNaming {
+		lines = append(lines, "  "+descStyle.Render("Name: ")+m.filterNameInput.View())
+		lines = append(lines, "")
+		lines = append(lines, "  "+keyStyle.Render("[Enter]")+m.dimStyle().Render(" Save  ")+
+			lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]")+m.dimStyle().Render(" Cancel"))
+	} else {
+		lines = append(lines, "  "+keyStyle.Render("[Enter]")+m.dimStyle().Render(" Apply  ")+
+			keyStyle.Render("[n]")+m.dimStyle().Render(" Save current  ")+
+			keyStyle.Render("[*]")+m.dimStyle().Render(" Default  ")+
+			keyStyle.Render("[d]")+m.dimStyle().Render(" Delete  ")+
+			lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]")+m.dimStyle().Render(" Close"))
+	}
+
+	content := strings.Join(lines, "\n")
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.colors.warning).
+		Padding(1, 2).
+		Render(content)
+}
+
+// renderDashboardView lists every running agent across all projects with
+// its status, ticket, project, agent and how long it has been running.
+func (m *Model) renderDashboardView() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(m.colors.info).
+		Bold(true)
+
+	entries := m.dashboardEntries()
+	title := "▶ Agents"
+	if len(entries) > 0 {
+		title += m.dimStyle().Render(fmt.Sprintf("  %d running", len(entries)))
+	}
+
+	var lines []string
+	lines = append(lines, titleStyle.Render(title))
+	lines = append(lines, "")
+
+	if len(entries) == 0 {
+		lines = append(lines, m.dimStyle().Render("  No agents running."))
+		lines = append(lines, "")
+	}
+
+	titleWidth := min(max(m.width/3, 20), 50)
+	selected := m.dashboardIndex(entries)
+	for i, e := range entries {
+		cursor := "  "
+		nameStyle := lipgloss.NewStyle().Foreground(m.colors.text)
+		if i == selected {
+			cursor = lipgloss.NewStyle().Foreground(m.colors.info).Render("▸ ")
+			nameStyle = nameStyle.Foreground(m.colors.info).Bold(true)
+		}
+
+		statusIcon, statusText, statusColor := "●", "running", m.colors.success
+		switch e.ticket.AgentStatus {
+		case board.AgentIdle:
+			statusIcon, statusText, statusColor = "◆", "idle", m.colors.primary
+		case board.AgentWorking:
+			statusIcon, statusText, statusColor = m.spinner.View(), "working", m.colors.warning
+		case board.AgentWaiting:
+			statusIcon, statusText, statusColor = "◐", "waiting", m.colors.secondary
+		case board.AgentCompleted:
+			statusIcon, statusText, statusColor = "✓", "done", m.colors.success
+		case board.AgentError:
+			statusIcon, statusText, statusColor = "✗", "error", m.colors.err
+		}
+		if e.pane.Paused() {
+			statusIcon, statusText, statusColor = "⏸", "paused", m.colors.muted
+		}
+		status := lipgloss.NewStyle().
+			Foreground(statusColor).
+			Width(10).
+			Render(statusIcon + " " + statusText)
+
+		name := e.ticket.Title
+		if lipgloss.Width(name) > titleWidth {
+			name = lipgloss.NewStyle().MaxWidth(titleWidth-1).Render(name) + "…"
+		}
+		name = nameStyle.Width(titleWidth).Render(name)
+
+		projectName := ""
+		if e.project != nil {
+			projectName = e.project.Name
+		}
+		proj := lipgloss.NewStyle().
+			Foreground(m.colors.info).
+			Width(16).
+			MaxWidth(16).
+			Render(projectName)
+
+		agentType := e.ticket.AgentType
+		agentBadge := lipgloss.NewStyle().
+			Foreground(m.agentColor(agentType)).
+			Width(12).
+			MaxWidth(12).
+			Render(agentType)
+
+		elapsed := m.dimStyle().Render(formatDuration(time.Since(e.pane.StartedAt())))
+
+		lines = append(lines, cursor+status+" "+name+"  "+proj+" "+agentBadge+" "+elapsed)
+	}
+	if len(entries) > 0 {
+		lines = append(lines, "")
+	}
+
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info)
+	lines = append(lines, "  "+keyStyle.Render("[Enter]")+m.dimStyle().Render(" Attach  ")+
+		lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]")+m.dimStyle().Render(" Close"))
+
+	content := strings.Join(lines, "\n")
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.colors.info).
+		Padding(1, 2).
+		Render(content)
+}
+
+// renderEffectiveConfigView lists the resolved settings the selected
+// ticket's agent would be spawned with and the init prompt it would get.
+func (m *Model) renderEffectiveConfigView() string {
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+
+	title := ticket.Title
+	if key := m.ticketKey(ticket); key != "" {
+		title = key + " " + title
+	}
+
+	body, nameWidth, valueWidth := m.effectiveConfigBody()
+	visible := m.effectiveConfigVisibleLines()
+	// The window may have grown since the view last scrolled.
+	scroll := min(m.effectiveConfigScroll, max(len(body)-visible, 0))
+	end := min(scroll+visible, len(body))
+
+	var lines []string
+	lines = append(lines, titleStyle.Render("⚙ Effective Config"))
+	lines = append(lines, m.dimStyle().MaxWidth(nameWidth+valueWidth).Render(title))
+	lines = append(lines, "")
+	lines = append(lines, body[scroll:end]...)
+	if len(body) > visible {
+		lines = append(lines, "", m.dimStyle().Render(fmt.Sprintf("lines %d-%d of %d", scroll+1, end, len(body))))
+	}
+
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info)
+	lines = append(lines, "")
+	lines = append(lines, keyStyle.Render("[e]")+m.dimStyle().Render(" Edit ticket  ")+
+		keyStyle.Render("[O]")+m.dimStyle().Render(" Settings  ")+
+		lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]")+m.dimStyle().Render(" Close"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.colors.info).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// effectiveConfigBody lays out the resolved settings of the effective config
+// view, one string per screen line, with the widths of its name and value
+// columns.
+func (m *Model) effectiveConfigBody() (body []string, nameWidth, valueWidth int) {
+	nameStyle := lipgloss.NewStyle().Foreground(m.colors.subtext)
+	valueStyle := lipgloss.NewStyle().Foreground(m.colors.text)
+	sourceStyle := lipgloss.NewStyle().Foreground(m.colors.muted).Italic(true)
+
+	cfg := m.effectiveConfig
+	for _, s := range cfg.settings {
+		nameWidth = max(nameWidth, lipgloss.Width(s.name))
+	}
+	valueWidth = max(min(m.width-nameWidth-40, 70), 20)
+
+	for _, s := range cfg.settings {
+		value := lipgloss.NewStyle().Width(valueWidth).Render(valueStyle.Render(s.value))
+		row := lipgloss.JoinHorizontal(lipgloss.Top,
+			nameStyle.Render(fmt.Sprintf("%-*s  ", nameWidth, s.name)),
+			value,
+			"  "+sourceStyle.Render(s.source))
+		body = append(body, strings.Split(row, "\n")...)
+	}
+	if cfg.promptSource != "" {
+		body = append(body, "", nameStyle.Render("Init prompt  ")+sourceStyle.Render(cfg.promptSource))
+		if cfg.prompt != "" {
+			wrapped := lipgloss.NewStyle().Width(nameWidth + valueWidth).Render(valueStyle.Render(cfg.prompt))
+			for _, line := range strings.Split(wrapped, "\n") {
+				body = append(body, "  "+line)
+			}
+		}
+	}
+	return body, nameWidth, valueWidth
+}
+
+// effectiveConfigVisibleLines is how many body lines the effective config
+// view shows at once.
+func (m *Model) effectiveConfigVisibleLines() int {
+	return max(m.height-12, 5)
+}
+
+func (m *Model) renderAgentView() string {
+	pane, ok := m.focusedTerminal()
+	if !ok {
+		return "No pane focused"
+	}
+
+	var b strings.Builder
+
+	ticket, _ := m.globalStore.Get(m.focusedPane)
+	title := "Agent"
+	agentType := ""
+	projectName := ""
+	var sessionDuration string
+	if ticket != nil {
+		title = ticket.Title
+		if key := m.ticketKey(ticket); key != "" {
+			title = key + " " + title
+		}
+		agentType = ticket.AgentType
+		if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+			projectName = proj.Name
+		}
+		if ticket.AgentSpawnedAt != nil {
+			duration := time.Since(*ticket.AgentSpawnedAt)
+			sessionDuration = formatDuration(duration)
+		}
+	}
+	if m.focusedEditor {
+		agentType = "editor"
+		sessionDuration = ""
+	}
+	if quick, ok := m.quickAgents[m.focusedPane]; ok {
+		title = "Quick question"
+		agentType = quick.agentType
+		projectName = quick.project.Name
+	}
+
+	breadcrumbStyle := lipgloss.NewStyle().Foreground(m.colors.muted)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(m.colors.primary).
+		Bold(true)
+
+	header := breadcrumbStyle.Render("Board → ") + titleStyle.Render(title)
+
+	if projectName != "" {
+		projBadge := lipgloss.NewStyle().
+			Foreground(m.colors.base).
+			Background(m.colors.info).
+			Padding(0, 1).
+			Render(projectName)
+		header = header + "  " + projBadge
+	}
+
+	if agentType != "" {
+		agentBadge := lipgloss.NewStyle().
+			Foreground(m.colors.base).
+			Background(m.agentColor(agentType)).
+			Padding(0, 1).
+			Render(agentType)
+		header = header + "  " + agentBadge
+	}
+
+	if sessionDuration != "" {
+		durationBadge := lipgloss.NewStyle().
+			Foreground(m.colors.muted).
+			Render("⏱ " + sessionDuration)
+		header = header + "  " + durationBadge
+	}
+
+	if summary := m.workSummary; summary.valid && summary.ticketID == m.focusedPane {
+		text := fmt.Sprintf("±%d %s", summary.files, pluralize(summary.files, "file", "files"))
+		if summary.commits >= 0 {
+			text += fmt.Sprintf(", %d %s", summary.commits, pluralize(summary.commits, "commit", "commits"))
+		}
+		workColor := m.colors.muted
+		if summary.files > 0 || summary.commits > 0 {
+			workColor = m.colors.success
+		}
+		header = header + "  " + lipgloss.NewStyle().Foreground(workColor).Render(text)
+	}
+
+	var depsLine string
+	if ticket != nil {
 		blockedBy := m.globalStore.GetBlockedBy(ticket.ID)
 		blocks := m.globalStore.GetBlocks(ticket.ID)
 		if len(blockedBy) > 0 || len(blocks) > 0 {
@@ -1416,18 +2459,18 @@ func (m *Model) renderAgentView() string {
 			if len(blockedBy) > 0 {
 				var names []string
 				for _, t := range blockedBy {
-					names = append(names, t.Title)
+					names = append(names, m.renderBlockerStatus(t))
 				}
-				depParts = append(depParts, "⛓↑ "+strings.Join(names, ", "))
+				depParts = append(depParts, depStyle.Render("⛓↑ ")+strings.Join(names, depStyle.Render(", ")))
 			}
 			if len(blocks) > 0 {
 				var names []string
 				for _, t := range blocks {
 					names = append(names, t.Title)
 				}
-				depParts = append(depParts, "⛓↓ "+strings.Join(names, ", "))
+				depParts = append(depParts, depStyle.Render("⛓↓ "+strings.Join(names, ", ")))
 			}
-			depsLine = depStyle.Render(strings.Join(depParts, "  "))
+			depsLine = strings.Join(depParts, "  ")
 		}
 	}
 
@@ -1454,11 +2497,28 @@ func (m *Model) renderAgentView() string {
 			Foreground(m.colors.warning).
 			Bold(true)
 		scrollIndicator = scrollStyle.Render(fmt.Sprintf("↑%d/%d", offset, scrollbackLen)) + "  "
+		if query := pane.SearchQuery(); query != "" && !m.agentSearching {
+			current, total := pane.SearchPosition()
+			searchText := fmt.Sprintf("/%s %d/%d", query, current, total)
+			if total == 0 {
+				searchText = fmt.Sprintf("/%s no matches", query)
+			}
+			scrollIndicator += lipgloss.NewStyle().Foreground(m.colors.info).Render(searchText) + m.dimStyle().Render(" n/N") + "  "
+		} else if !m.agentSearching {
+			scrollIndicator += m.dimStyle().Render("/ search") + "  "
+		}
+	}
+	if m.agentSearching {
+		scrollIndicator += m.agentSearchInput.View() + "  "
 	}
 
 	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info)
 	hints := scrollIndicator + paneIndicator + "  " +
+		keyStyle.Render("Ctrl+]") + m.dimStyle().Render(" Diff") + "  " +
 		keyStyle.Render("Ctrl+g") + m.dimStyle().Render(" Board")
+	if m.notification != "" {
+		hints = lipgloss.NewStyle().Foreground(m.colors.success).Render(m.notification) + "  " + hints
+	}
 
 	spacing := m.width - lipgloss.Width(header) - lipgloss.Width(hints)
 	spacing = max(spacing, 0)
@@ -1473,11 +2533,64 @@ func (m *Model) renderAgentView() string {
 		b.WriteString("\n")
 	}
 
-	b.WriteString(pane.View())
+	if m.diffSplit {
+		paneWidth, paneHeight := m.agentPaneSize()
+		diff := m.renderDiffPanel(m.width-paneWidth, paneHeight)
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, pane.View(), diff))
+	} else {
+		b.WriteString(pane.View())
+	}
 
 	return b.String()
 }
 
+func (m *Model) renderDiffPanel(width, height int) string {
+	// One column for the divider, one row for the panel title.
+	contentWidth := max(width-2, 1)
+	lineStyle := lipgloss.NewStyle().MaxWidth(contentWidth)
+	addStyle := lineStyle.Foreground(m.colors.success)
+	delStyle := lineStyle.Foreground(m.colors.err)
+	hunkStyle := lineStyle.Foreground(m.colors.info)
+	metaStyle := lineStyle.Foreground(m.colors.muted).Bold(true)
+
+	lines := []string{lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true).Render("git diff")}
+
+	switch {
+	case m.diffErr != nil:
+		lines = append(lines, lineStyle.Foreground(m.colors.err).Render(m.diffErr.Error()))
+	case strings.TrimSpace(m.diffContent) == "":
+		lines = append(lines, m.dimStyle().Render("No changes"))
+	default:
+		for _, line := range strings.Split(strings.TrimRight(m.diffContent, "\n"), "\n") {
+			if len(lines) >= height {
+				break
+			}
+			line = strings.ReplaceAll(line, "\t", "    ")
+			switch {
+			case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+				strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+				lines = append(lines, metaStyle.Render(line))
+			case strings.HasPrefix(line, "@@"):
+				lines = append(lines, hunkStyle.Render(line))
+			case strings.HasPrefix(line, "+"):
+				lines = append(lines, addStyle.Render(line))
+			case strings.HasPrefix(line, "-"):
+				lines = append(lines, delStyle.Render(line))
+			default:
+				lines = append(lines, lineStyle.Render(line))
+			}
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Width(width-1).
+		Height(height).
+		PaddingLeft(1).
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(m.colors.overlay).
+		Render(strings.Join(lines, "\n"))
+}
+
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
 		return fmt.Sprintf("%ds", int(d.Seconds()))
@@ -1493,6 +2606,13 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dh%dm", hours, mins)
 }
 
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
 func (m *Model) renderFilterInput() string {
 	inputStyle := lipgloss.NewStyle().
 		Foreground(m.colors.base).
@@ -1514,7 +2634,9 @@ func (m *Model) renderActiveFilter() string {
 		Padding(0, 1)
 
 	filterText := m.filterQuery
-	if len(m.filterProjectIDs) > 0 && m.filterQuery == "" {
+	if m.filterOrphaned && m.filterQuery == "" {
+		filterText = "orphaned"
+	} else if len(m.filterProjectIDs) > 0 && m.filterQuery == "" {
 		count := len(m.filterProjectIDs)
 		if count == 1 {
 			for id := range m.filterProjectIDs {
@@ -1528,13 +2650,21 @@ func (m *Model) renderActiveFilter() string {
 		}
 	}
 
+	if m.savedFilter != nil {
+		if filterText == "" {
+			filterText = "★ " + m.savedFilter.Name
+		} else {
+			filterText = "★ " + m.savedFilter.Name + " · " + filterText
+		}
+	}
+
 	return filterStyle.Render("FILTERED: "+filterText) + " " + clearStyle.Render("× clear")
 }
 
 func (m *Model) renderFilterHint() string {
 	return lipgloss.NewStyle().
 		Foreground(m.colors.muted).
-		Render("/ search (@project to filter)")
+		Render("/ search (@project, #label)")
 }
 
 func (m *Model) countVisibleTickets() int {
@@ -1627,9 +2757,24 @@ func (m *Model) renderCreateProjectForm() string {
 		"  " + descStyle.Render("Absolute path to a git repository") + "\n" +
 		"  " + m.addProjectPath.View() + errorLine + "\n" +
 		"  " + descStyle.Render("The project name will be derived from the directory name.") + "\n" +
-		"  " + descStyle.Render("Example: ~/projects/myapp → \"myapp\"") + "\n\n" +
-		"  " + lipgloss.NewStyle().Foreground(m.colors.success).Render("[Enter]") + m.dimStyle().Render(" Add  ") +
-		lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]") + m.dimStyle().Render(" Cancel")
+		"  " + descStyle.Render("Example: ~/projects/myapp → \"myapp\"") + "\n"
+
+	if len(m.globalStore.Projects()) > 0 {
+		source := m.dimStyle().Render("none (use global defaults)")
+		if src := m.copySettingsSource(); src != nil {
+			source = lipgloss.NewStyle().Foreground(m.colors.text).Render(src.Name)
+		}
+		content += "\n" +
+			"  " + labelStyle.Render("Copy Settings From") + "\n" +
+			"  " + descStyle.Render("Branch naming, columns and spawn options") + "\n" +
+			"  ◂ " + source + " ▸\n"
+	}
+
+	content += "\n  " + lipgloss.NewStyle().Foreground(m.colors.success).Render("[Enter]") + m.dimStyle().Render(" Add  ")
+	if len(m.globalStore.Projects()) > 0 {
+		content += lipgloss.NewStyle().Foreground(m.colors.info).Render("[Tab]") + m.dimStyle().Render(" Copy from  ")
+	}
+	content += lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]") + m.dimStyle().Render(" Cancel")
 
 	formWidth := min(55, m.width-4)
 	if formWidth < 40 {
@@ -1644,6 +2789,74 @@ func (m *Model) renderCreateProjectForm() string {
 		Render(content)
 }
 
+func (m *Model) renderOnboarding() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(m.colors.primary).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+	descStyle := lipgloss.NewStyle().Foreground(m.colors.muted).Italic(true)
+	stepStyle := lipgloss.NewStyle().Foreground(m.colors.subtext)
+
+	var content string
+	switch m.onboardingStep {
+	case onboardingProject:
+		var errorLine string
+		if m.notification != "" {
+			errorStyle := lipgloss.NewStyle().Foreground(m.colors.err).Bold(true)
+			errorLine = "\n  " + errorStyle.Render("⚠ "+m.notification) + "\n"
+		}
+
+		content = titleStyle.Render("◈ Welcome to OpenKanban") + "\n\n" +
+			"  " + stepStyle.Render("Step 1 of 2 · Add your first project") + "\n\n" +
+			"  " + labelStyle.Render("Repository Path") + "\n" +
+			"  " + descStyle.Render("Absolute path to a git repository") + "\n" +
+			"  " + m.addProjectPath.View() + errorLine + "\n" +
+			"  " + descStyle.Render("Example: ~/projects/myapp → \"myapp\"") + "\n\n" +
+			"  " + lipgloss.NewStyle().Foreground(m.colors.success).Render("[Enter]") + m.dimStyle().Render(" Add  ") +
+			lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]") + m.dimStyle().Render(" Quit")
+
+	case onboardingAgent:
+		var rows []string
+		for i, name := range m.getAgentNames() {
+			marker := "○ "
+			style := lipgloss.NewStyle().Foreground(m.colors.text)
+			if i == m.onboardingAgentIndex {
+				marker = "● "
+				style = style.Foreground(m.colors.primary).Bold(true)
+			}
+			row := "  " + style.Render(marker+name)
+			// Nothing is shown while the command is still being looked up.
+			if installed, detected := m.installedAgents[name]; detected && installed {
+				row += "  " + lipgloss.NewStyle().Foreground(m.colors.success).Render("✓ installed")
+			} else if detected {
+				row += "  " + m.dimStyle().Render("not found")
+			}
+			rows = append(rows, row)
+		}
+
+		content = titleStyle.Render("◈ Welcome to OpenKanban") + "\n\n" +
+			"  " + stepStyle.Render("Step 2 of 2 · Pick a default agent") + "\n\n" +
+			"  " + descStyle.Render("Used when spawning agents; change it later with O") + "\n\n" +
+			strings.Join(rows, "\n") + "\n\n" +
+			"  " + lipgloss.NewStyle().Foreground(m.colors.info).Render("[j/k]") + m.dimStyle().Render(" Select  ") +
+			lipgloss.NewStyle().Foreground(m.colors.success).Render("[Enter]") + m.dimStyle().Render(" Confirm  ") +
+			lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]") + m.dimStyle().Render(" Skip")
+	}
+
+	formWidth := min(60, m.width-4)
+	if formWidth < 40 {
+		formWidth = 40
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.colors.primary).
+		Padding(1, 2).
+		Width(formWidth).
+		Render(content)
+}
+
 func shortenPath(path string) string {
 	home, _ := os.UserHomeDir()
 	if strings.HasPrefix(path, home) {
@@ -1657,7 +2870,8 @@ func (m *Model) renderSidebar() string {
 		return ""
 	}
 
-	projects := m.globalStore.Projects()
+	allProjects := m.globalStore.Projects()
+	projects := m.sidebarProjects()
 	statusHeight := 1
 	availableHeight := m.height - m.headerHeight() - statusHeight
 
@@ -1681,23 +2895,27 @@ func (m *Model) renderSidebar() string {
 	var lines []string
 
 	lines = append(lines, titleStyle.Render("  Projects"))
-	lines = append(lines, "")
+	if m.sidebarFiltering || m.sidebarFilter.Value() != "" {
+		lines = append(lines, " "+m.sidebarFilter.View())
+	} else {
+		lines = append(lines, "")
+	}
 
-	allCount := m.globalStore.Count()
+	allCount := m.boardTicketCount()
 	selectedCount := len(m.filterProjectIDs)
 	noFilter := selectedCount == 0
 	var allLabel string
 	if noFilter {
 		allLabel = fmt.Sprintf("[✓] All (%d)", allCount)
-	} else if selectedCount == len(projects) {
+	} else if selectedCount == len(allProjects) {
 		allLabel = fmt.Sprintf("[✓] All (%d)", allCount)
 	} else {
-		allLabel = fmt.Sprintf("[-] %d/%d", selectedCount, len(projects))
+		allLabel = fmt.Sprintf("[-] %d/%d", selectedCount, len(allProjects))
 	}
 
 	if m.sidebarIndex == 0 && m.sidebarFocused {
 		lines = append(lines, selectedStyle.Render(allLabel))
-	} else if noFilter || selectedCount == len(projects) {
+	} else if noFilter || selectedCount == len(allProjects) {
 		lines = append(lines, checkStyle.Render(allLabel))
 	} else {
 		lines = append(lines, normalStyle.Render(allLabel))
@@ -1709,7 +2927,7 @@ func (m *Model) renderSidebar() string {
 		idx := i + 1
 		count := 0
 		for _, t := range m.globalStore.All() {
-			if t.ProjectID == p.ID {
+			if t.ProjectID == p.ID && m.countsTowardBoard(t) {
 				count++
 			}
 		}
@@ -1734,8 +2952,21 @@ func (m *Model) renderSidebar() string {
 		}
 	}
 
+	if orphanCount := len(m.globalStore.OrphanedTickets()); orphanCount > 0 {
+		label := fmt.Sprintf("⚠ Orphaned (%d)", orphanCount)
+		orphanStyle := lipgloss.NewStyle().Foreground(m.colors.err).Padding(0, 1)
+		if m.filterOrphaned {
+			orphanStyle = orphanStyle.Bold(true)
+		}
+		if m.sidebarIndex == len(projects)+1 && m.sidebarFocused {
+			lines = append(lines, selectedStyle.Render(label))
+		} else {
+			lines = append(lines, orphanStyle.Render(label))
+		}
+	}
+
 	lines = append(lines, "")
-	addIndex := len(projects) + 1
+	addIndex := len(projects) + 1 + m.sidebarOrphanRows()
 	if m.sidebarIndex == addIndex && m.sidebarFocused {
 		lines = append(lines, selectedStyle.Render("+ Add project"))
 	} else {
@@ -1749,7 +2980,7 @@ func (m *Model) renderSidebar() string {
 
 	hintStyle := lipgloss.NewStyle().Foreground(m.colors.muted).Italic(true)
 	if m.sidebarFocused {
-		lines = append(lines, hintStyle.Render("  j/k ⏎toggle a/d"))
+		lines = append(lines, hintStyle.Render("  j/k ⏎toggle a/d /"))
 	} else {
 		lines = append(lines, hintStyle.Render("  h→focus  [hide"))
 	}
@@ -1871,6 +3102,33 @@ func (m *Model) dimStyle() lipgloss.Style {
 	return lipgloss.NewStyle().Foreground(m.colors.muted)
 }
 
+// columnEmptyState returns the placeholder shown in an empty column,
+// preferring the column's own EmptyIcon/EmptyText.
+func (m *Model) columnEmptyState(col board.Column) (icon, text string) {
+	icon, text = "○", "Drag or Space to move here"
+	if col.Status == board.StatusBacklog || m.groupByProject {
+		icon, text = "+", "Press n to add a ticket"
+	} else if col.Status == board.StatusDone {
+		icon, text = "✓", "Finished tickets land here"
+	}
+	if col.EmptyIcon != "" {
+		icon = col.EmptyIcon
+	}
+	if col.EmptyText != "" {
+		text = col.EmptyText
+	}
+	return icon, text
+}
+
+// agentColor is the badge color for agentType: its configured color, or the
+// theme's primary accent.
+func (m *Model) agentColor(agentType string) lipgloss.Color {
+	if cfg, ok := m.config.Agents[agentType]; ok && cfg.Color != "" {
+		return lipgloss.Color(cfg.Color)
+	}
+	return m.colors.primary
+}
+
 func (m *Model) columnColor(status board.TicketStatus) lipgloss.Color {
 	switch status {
 	case board.StatusBacklog:
@@ -1879,7 +3137,14 @@ func (m *Model) columnColor(status board.TicketStatus) lipgloss.Color {
 		return m.colors.warning
 	case board.StatusDone:
 		return m.colors.success
+	case board.StatusArchived:
+		return m.colors.muted
 	default:
+		for _, col := range m.statusColumns {
+			if col.Status == status && col.Color != "" {
+				return lipgloss.Color(col.Color)
+			}
+		}
 		return m.colors.muted
 	}
 }